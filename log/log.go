@@ -24,6 +24,9 @@ var EnableColorExpressions = func() bool {
 	}
 }()
 
+// The Dumper used to render values logged via Dump and Dumpf.
+var Dumper = typeutil.NewDumper()
+
 var DefaultInterceptStackDepth int = 5
 var SynchronousIntercepts = false
 
@@ -248,18 +251,31 @@ func Debugf(format string, args ...interface{}) {
 
 // Pretty-print the given arguments to the log at debug-level.
 func Dump(args ...interface{}) {
+	var dumper = logDumper()
+
 	for _, arg := range args {
-		Log(LogLevel, typeutil.Dump(arg))
+		Log(LogLevel, dumper.Dump(arg))
 	}
 }
 
 // Same as Dump, but accepts a format string.
 func Dumpf(format string, args ...interface{}) {
+	var dumper = logDumper()
+
 	for _, arg := range args {
-		Logf(LogLevel, format, typeutil.Dump(arg))
+		Logf(LogLevel, format, dumper.Dump(arg))
 	}
 }
 
+// returns a copy of Dumper with colors enabled whenever color expressions are.
+func logDumper() *typeutil.Dumper {
+	var dumper = *Dumper
+
+	dumper.Color = EnableColorExpressions
+
+	return &dumper
+}
+
 // Marshal the arguments as indented JSON and log them at debug-level.
 func DumpJSON(args ...interface{}) {
 	for _, arg := range args {
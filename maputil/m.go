@@ -298,6 +298,16 @@ func (self *Map) String(key string, fallbacks ...interface{}) string {
 	return self.Get(key, fallbacks...).String()
 }
 
+// Return the value at key as a string, and whether a non-nil value exists at that key.  This
+// can be used as a stringutil.LookupFunc to expand variables from the Map.
+func (self *Map) LookupString(key string) (string, bool) {
+	if v := self.Get(key); !v.IsNil() {
+		return v.String(), true
+	}
+
+	return ``, false
+}
+
 // Return the value at key interpreted as a Time.
 func (self *Map) Time(key string, fallbacks ...interface{}) time.Time {
 	return self.Get(key, fallbacks...).Time()
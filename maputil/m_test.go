@@ -85,6 +85,34 @@ func TestMSet(t *testing.T) {
 	assert.Equal(`2funny4me`, input.String(`lol`))
 }
 
+func TestMLookupString(t *testing.T) {
+	assert := require.New(t)
+	input := M(map[string]interface{}{
+		`HOST`:  `example.com`,
+		`PORT`:  8080,
+		`EMPTY`: ``,
+	})
+
+	v, ok := input.LookupString(`PORT`)
+	assert.True(ok)
+	assert.Equal(`8080`, v)
+
+	v, ok = input.LookupString(`EMPTY`)
+	assert.True(ok)
+	assert.Equal(``, v)
+
+	_, ok = input.LookupString(`NOPE`)
+	assert.False(ok)
+
+	out, err := stringutil.ExpandOptions{
+		Lookup: input.LookupString,
+		Strict: true,
+	}.Expand(`http://${HOST}:${PORT}/${EMPTY:-index}`)
+
+	assert.NoError(err)
+	assert.Equal(`http://example.com:8080/index`, out)
+}
+
 func TestMStruct(t *testing.T) {
 	assert := require.New(t)
 	input := M(&testMstruct{
@@ -0,0 +1,555 @@
+package mathutil
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ghetzel/go-stockutil/stringutil"
+	"github.com/ghetzel/go-stockutil/typeutil"
+)
+
+// the largest exponent accepted when parsing a decimal (e.g. "1e100"), to bound allocations
+const maxDecimalExponent = 1 << 14
+
+// If true, Decimals are marshaled to JSON as strings (e.g. "1.50") instead of as numbers, for
+// consumers that would otherwise parse them into floating point values.
+var DecimalJSONQuoted = false
+
+// Specifies how a Decimal is rounded when digits must be discarded.
+type RoundingMode int
+
+const (
+	RoundHalfUp   RoundingMode = iota // round to nearest, with ties away from zero (2.5 → 3, -2.5 → -3)
+	RoundHalfEven                     // round to nearest, with ties to the even neighbor (2.5 → 2, 3.5 → 4)
+	RoundHalfDown                     // round to nearest, with ties toward zero (2.5 → 2, -2.5 → -2)
+	RoundDown                         // round toward zero (truncate)
+	RoundUp                           // round away from zero
+	RoundCeiling                      // round toward positive infinity
+	RoundFloor                        // round toward negative infinity
+)
+
+func (self RoundingMode) String() string {
+	switch self {
+	case RoundHalfUp:
+		return `half-up`
+	case RoundHalfEven:
+		return `half-even`
+	case RoundHalfDown:
+		return `half-down`
+	case RoundDown:
+		return `down`
+	case RoundUp:
+		return `up`
+	case RoundCeiling:
+		return `ceiling`
+	case RoundFloor:
+		return `floor`
+	default:
+		return ``
+	}
+}
+
+// A Decimal is an arbitrary-precision decimal number with a fixed number of digits after the
+// decimal point (its scale), suitable for values such as currency where floating point rounding
+// errors are unacceptable.  Decimals are immutable; arithmetic returns a new value.  The zero
+// value is 0 with a scale of 0.
+//
+// Decimals implement fmt.Stringer, so they can be converted with typeutil (e.g.
+// typeutil.Float(d) or typeutil.V(d).String()).
+type Decimal struct {
+	unscaled *big.Int // the value multiplied by 10^scale; nil means zero
+	scale    int
+}
+
+// Returns a Decimal equal to unscaled × 10^-scale (e.g. NewDecimal(1234, 2) is 12.34).  Negative
+// scales are applied by multiplying unscaled, so the result always has a scale of zero or more.
+func NewDecimal(unscaled int64, scale int) Decimal {
+	var value = big.NewInt(unscaled)
+
+	if scale < 0 {
+		value.Mul(value, pow10(-scale))
+		scale = 0
+	}
+
+	return Decimal{
+		unscaled: value,
+		scale:    scale,
+	}
+}
+
+// Returns a Decimal equal to the given integer.
+func DecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+// Returns a Decimal equal to the shortest decimal representation of the given float (so 0.1
+// becomes exactly 0.1, not 0.1000000000000000055511151231257827).
+func DecimalFromFloat(value float64) (Decimal, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Decimal{}, fmt.Errorf("cannot represent %v as a decimal", value)
+	}
+
+	return ParseDecimalWith(strconv.FormatFloat(value, 'f', -1, 64), ``, `.`)
+}
+
+// Parses a decimal number from the given string.  Thousands separators are permitted using
+// stringutil.DefaultThousandsSeparator (e.g. "-1,234.50"), and so is an exponent (e.g. "1.5e3").
+// The scale of the result is the number of digits after the decimal point.
+func ParseDecimal(in string) (Decimal, error) {
+	return ParseDecimalWith(in, stringutil.DefaultThousandsSeparator, stringutil.DefaultDecimalSeparator)
+}
+
+// Same as ParseDecimal, but panics if the string cannot be parsed.
+func MustParseDecimal(in string) Decimal {
+	if d, err := ParseDecimal(in); err == nil {
+		return d
+	} else {
+		panic(err.Error())
+	}
+}
+
+// Same as ParseDecimal, but with the given thousands and decimal separators (e.g. "." and "," for
+// "1.234,50").  If thousands is empty, no thousands separators are permitted.  Thousands
+// separators must be placed every three digits.
+func ParseDecimalWith(in string, thousands string, decimal string) (Decimal, error) {
+	var s = strings.TrimSpace(in)
+	var negative bool
+	var exponent int64
+
+	if s != `` && (s[0] == '-' || s[0] == '+') {
+		negative = (s[0] == '-')
+		s = s[1:]
+	}
+
+	if i := strings.IndexAny(s, `eE`); i >= 0 {
+		if e, err := strconv.ParseInt(s[i+1:], 10, 64); err == nil && e >= -maxDecimalExponent && e <= maxDecimalExponent {
+			exponent = e
+			s = s[:i]
+		} else {
+			return Decimal{}, fmt.Errorf("invalid decimal %q: bad exponent", in)
+		}
+	}
+
+	var whole, fraction = s, ``
+
+	if decimal != `` {
+		if i := strings.Index(s, decimal); i >= 0 {
+			whole, fraction = s[:i], s[i+len(decimal):]
+		}
+	}
+
+	if thousands != `` && strings.Contains(whole, thousands) {
+		var groups = strings.Split(whole, thousands)
+
+		for i, group := range groups {
+			if (i == 0 && (len(group) == 0 || len(group) > 3)) || (i > 0 && len(group) != 3) {
+				return Decimal{}, fmt.Errorf("invalid decimal %q: misplaced thousands separator", in)
+			}
+		}
+
+		whole = strings.Join(groups, ``)
+	}
+
+	if whole+fraction == `` || !isDigits(whole) || !isDigits(fraction) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", in)
+	}
+
+	var unscaled, _ = new(big.Int).SetString(whole+fraction, 10)
+	var scale = len(fraction) - int(exponent)
+
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+
+	return Decimal{
+		unscaled: unscaled,
+		scale:    scale,
+	}, nil
+}
+
+// Converts the given value to a Decimal.  Decimals, integers and floats are converted directly;
+// anything else is converted to a string with typeutil.String and parsed with ParseDecimal.
+func ToDecimal(in interface{}) (Decimal, error) {
+	switch v := in.(type) {
+	case Decimal:
+		return v, nil
+	case *Decimal:
+		if v == nil {
+			return Decimal{}, nil
+		}
+
+		return *v, nil
+	case int:
+		return DecimalFromInt(int64(v)), nil
+	case int8:
+		return DecimalFromInt(int64(v)), nil
+	case int16:
+		return DecimalFromInt(int64(v)), nil
+	case int32:
+		return DecimalFromInt(int64(v)), nil
+	case int64:
+		return DecimalFromInt(v), nil
+	case uint:
+		return Decimal{unscaled: new(big.Int).SetUint64(uint64(v))}, nil
+	case uint8:
+		return DecimalFromInt(int64(v)), nil
+	case uint16:
+		return DecimalFromInt(int64(v)), nil
+	case uint32:
+		return DecimalFromInt(int64(v)), nil
+	case uint64:
+		return Decimal{unscaled: new(big.Int).SetUint64(v)}, nil
+	case float32:
+		return DecimalFromFloat(float64(v))
+	case float64:
+		return DecimalFromFloat(v)
+	case nil:
+		return Decimal{}, nil
+	default:
+		return ParseDecimal(typeutil.String(in))
+	}
+}
+
+func (self Decimal) value() *big.Int {
+	if self.unscaled == nil {
+		return new(big.Int)
+	}
+
+	return self.unscaled
+}
+
+// Returns the number of digits after the decimal point.
+func (self Decimal) Scale() int {
+	return self.scale
+}
+
+// Returns -1, 0 or 1 depending on whether the value is negative, zero or positive.
+func (self Decimal) Sign() int {
+	return self.value().Sign()
+}
+
+// Returns whether the value is zero.
+func (self Decimal) IsZero() bool {
+	return self.Sign() == 0
+}
+
+// Returns -1, 0 or 1 depending on whether this value is less than, equal to or greater than the
+// other.  Scale is not considered, so 1.5 and 1.50 are equal.
+func (self Decimal) Cmp(other Decimal) int {
+	var a, b, _ = align(self, other)
+
+	return a.Cmp(b)
+}
+
+// Returns whether both values are numerically equal, regardless of scale.
+func (self Decimal) Equal(other Decimal) bool {
+	return self.Cmp(other) == 0
+}
+
+// Returns the negation of the value.
+func (self Decimal) Neg() Decimal {
+	return Decimal{
+		unscaled: new(big.Int).Neg(self.value()),
+		scale:    self.scale,
+	}
+}
+
+// Returns the absolute value.
+func (self Decimal) Abs() Decimal {
+	return Decimal{
+		unscaled: new(big.Int).Abs(self.value()),
+		scale:    self.scale,
+	}
+}
+
+// Returns the sum of both values, with the larger of their scales.
+func (self Decimal) Add(other Decimal) Decimal {
+	var a, b, scale = align(self, other)
+
+	return Decimal{
+		unscaled: a.Add(a, b),
+		scale:    scale,
+	}
+}
+
+// Returns the difference of both values, with the larger of their scales.
+func (self Decimal) Sub(other Decimal) Decimal {
+	var a, b, scale = align(self, other)
+
+	return Decimal{
+		unscaled: a.Sub(a, b),
+		scale:    scale,
+	}
+}
+
+// Returns the product of both values, with a scale that is the sum of their scales.
+func (self Decimal) Mul(other Decimal) Decimal {
+	return Decimal{
+		unscaled: new(big.Int).Mul(self.value(), other.value()),
+		scale:    self.scale + other.scale,
+	}
+}
+
+// Returns the quotient of both values, rounded to the given scale using the given mode.  Returns
+// an error if other is zero.
+func (self Decimal) Div(other Decimal, scale int, mode RoundingMode) (Decimal, error) {
+	if other.IsZero() {
+		return Decimal{}, fmt.Errorf("division by zero")
+	}
+
+	// (a × 10^-sa) / (b × 10^-sb) = (a × 10^(scale+sb-sa) / b) × 10^-scale
+	var num = new(big.Int).Set(self.value())
+	var den = new(big.Int).Set(other.value())
+
+	if shift := scale + other.scale - self.scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+
+	return fromScaled(divideRounded(num, den, mode), scale), nil
+}
+
+// Returns the value with exactly the given number of digits after the decimal point, rounding
+// with the given mode if digits must be discarded, or padding with zeros otherwise.  A negative
+// scale rounds to the left of the decimal point (e.g. -2 rounds to the nearest hundred).
+func (self Decimal) Round(scale int, mode RoundingMode) Decimal {
+	if scale >= self.scale {
+		return Decimal{
+			unscaled: new(big.Int).Mul(self.value(), pow10(scale-self.scale)),
+			scale:    scale,
+		}
+	}
+
+	return fromScaled(divideRounded(self.value(), pow10(self.scale-scale), mode), scale)
+}
+
+// Same as Round, but always rounds toward zero.
+func (self Decimal) Truncate(scale int) Decimal {
+	return self.Round(scale, RoundDown)
+}
+
+// Returns the value with any trailing zeros after the decimal point removed (e.g. 1.500 becomes
+// 1.5).
+func (self Decimal) Trim() Decimal {
+	var value = new(big.Int).Set(self.value())
+	var scale = self.scale
+	var ten = big.NewInt(10)
+	var q, r = new(big.Int), new(big.Int)
+
+	for scale > 0 {
+		if q.QuoRem(value, ten, r); r.Sign() != 0 {
+			break
+		}
+
+		value.Set(q)
+		scale--
+	}
+
+	return Decimal{
+		unscaled: value,
+		scale:    scale,
+	}
+}
+
+// Returns the value as an exact rational number.
+func (self Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(self.value(), pow10(self.scale))
+}
+
+// Returns the nearest float64 to the value.
+func (self Decimal) Float64() float64 {
+	var f, _ = strconv.ParseFloat(self.String(), 64)
+
+	return f
+}
+
+// Returns the integer part of the value (truncated toward zero).  The result is undefined if it
+// does not fit in an int64.
+func (self Decimal) Int64() int64 {
+	return new(big.Int).Quo(self.value(), pow10(self.scale)).Int64()
+}
+
+// Returns the value in plain decimal notation, with exactly Scale() digits after the decimal
+// point (e.g. "-1234.50").
+func (self Decimal) String() string {
+	var digits = new(big.Int).Abs(self.value()).String()
+	var sign string
+
+	if self.Sign() < 0 {
+		sign = `-`
+	}
+
+	if self.scale == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= self.scale {
+		digits = strings.Repeat(`0`, self.scale-len(digits)+1) + digits
+	}
+
+	var point = len(digits) - self.scale
+
+	return sign + digits[:point] + `.` + digits[point:]
+}
+
+// Decimals are marshaled as JSON numbers with all of their digits (or as strings, if
+// DecimalJSONQuoted is set).
+func (self Decimal) MarshalJSON() ([]byte, error) {
+	if DecimalJSONQuoted {
+		return []byte(strconv.Quote(self.String())), nil
+	}
+
+	return []byte(self.String()), nil
+}
+
+// Decimals are unmarshaled from JSON numbers or numeric strings.  A JSON null leaves the value
+// unchanged.
+func (self *Decimal) UnmarshalJSON(data []byte) error {
+	var s = string(data)
+
+	if s == `null` {
+		return nil
+	} else if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	return self.UnmarshalText([]byte(s))
+}
+
+func (self Decimal) MarshalText() ([]byte, error) {
+	return []byte(self.String()), nil
+}
+
+func (self *Decimal) UnmarshalText(data []byte) error {
+	if d, err := ParseDecimalWith(string(data), ``, `.`); err == nil {
+		*self = d
+		return nil
+	} else {
+		return err
+	}
+}
+
+// Implements sql.Scanner, accepting numeric strings, integers and floats.
+func (self *Decimal) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*self = Decimal{}
+		return nil
+	case string:
+		return self.UnmarshalText([]byte(src))
+	case []byte:
+		return self.UnmarshalText(src)
+	case int64:
+		*self = DecimalFromInt(src)
+		return nil
+	case float64:
+		if d, err := DecimalFromFloat(src); err == nil {
+			*self = d
+			return nil
+		} else {
+			return err
+		}
+	default:
+		return fmt.Errorf("Scan: unable to scan type %T into Decimal", src)
+	}
+}
+
+// Implements driver.Valuer; Decimals are stored as strings to preserve their precision.
+func (self Decimal) Value() (driver.Value, error) {
+	return self.String(), nil
+}
+
+// returns copies of the unscaled values of both decimals, rescaled to the larger of their scales.
+func align(a Decimal, b Decimal) (*big.Int, *big.Int, int) {
+	var x, y = new(big.Int).Set(a.value()), new(big.Int).Set(b.value())
+
+	switch {
+	case a.scale < b.scale:
+		x.Mul(x, pow10(b.scale-a.scale))
+		return x, y, b.scale
+	case a.scale > b.scale:
+		y.Mul(y, pow10(a.scale-b.scale))
+		return x, y, a.scale
+	default:
+		return x, y, a.scale
+	}
+}
+
+// builds a decimal from an unscaled value, multiplying out negative scales.
+func fromScaled(unscaled *big.Int, scale int) Decimal {
+	if scale < 0 {
+		unscaled.Mul(unscaled, pow10(-scale))
+		scale = 0
+	}
+
+	return Decimal{
+		unscaled: unscaled,
+		scale:    scale,
+	}
+}
+
+// divides num by den, rounding the quotient to an integer using the given mode.
+func divideRounded(num *big.Int, den *big.Int, mode RoundingMode) *big.Int {
+	var q, r = new(big.Int).QuoRem(num, den, new(big.Int))
+
+	if r.Sign() == 0 {
+		return q
+	}
+
+	var sign = num.Sign() * den.Sign()
+	var away bool
+
+	switch mode {
+	case RoundDown:
+		away = false
+	case RoundUp:
+		away = true
+	case RoundCeiling:
+		away = (sign > 0)
+	case RoundFloor:
+		away = (sign < 0)
+	default:
+		// compare the remainder to half of the divisor
+		var half = new(big.Int).Abs(r)
+		var c = half.Lsh(half, 1).CmpAbs(den)
+
+		switch mode {
+		case RoundHalfDown:
+			away = (c > 0)
+		case RoundHalfEven:
+			away = (c > 0 || (c == 0 && q.Bit(0) == 1))
+		default:
+			away = (c >= 0)
+		}
+	}
+
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+
+	return q
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func isDigits(in string) bool {
+	for _, c := range in {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,234 @@
+package mathutil
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/ghetzel/go-stockutil/typeutil"
+	"github.com/ghetzel/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	assert := assert.New(t)
+
+	for in, expected := range map[string]string{
+		`0`:            `0`,
+		`12.34`:        `12.34`,
+		`-0.05`:        `-0.05`,
+		`+7`:           `7`,
+		`.5`:           `0.5`,
+		`5.`:           `5`,
+		`1,234,567.89`: `1234567.89`,
+		`-1,000`:       `-1000`,
+		`1.5e3`:        `1500`,
+		`25E-4`:        `0.0025`,
+		` 3.10 `:       `3.10`,
+	} {
+		var d, err = ParseDecimal(in)
+
+		assert.NoError(err, in)
+		assert.Equal(expected, d.String(), in)
+	}
+
+	for _, in := range []string{``, `-`, `.`, `abc`, `1.2.3`, `1,23`, `12,3456`, `,123`, `1e`, `1e99999`, `0x10`} {
+		var _, err = ParseDecimal(in)
+
+		assert.Error(err, in)
+	}
+
+	var d, err = ParseDecimalWith(`1.234.567,5`, `.`, `,`)
+	assert.NoError(err)
+	assert.Equal(`1234567.5`, d.String())
+
+	assert.Equal(`2.50`, MustParseDecimal(`2.50`).String())
+	assert.Panics(func() {
+		MustParseDecimal(`nope`)
+	})
+}
+
+func TestDecimalConstructors(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(`12.34`, NewDecimal(1234, 2).String())
+	assert.Equal(`-0.001`, NewDecimal(-1, 3).String())
+	assert.Equal(`1200`, NewDecimal(12, -2).String())
+	assert.Equal(`42`, DecimalFromInt(42).String())
+	assert.Equal(`0`, Decimal{}.String())
+
+	var d, err = DecimalFromFloat(0.1)
+	assert.NoError(err)
+	assert.Equal(`0.1`, d.String())
+
+	_, err = DecimalFromFloat(math.NaN())
+	assert.Error(err)
+
+	for in, expected := range map[interface{}]string{
+		int8(-3):             `-3`,
+		uint64(1 << 63):      `9223372036854775808`,
+		float32(0.25):        `0.25`,
+		`1,000.5`:            `1000.5`,
+		NewDecimal(15, 1):    `1.5`,
+		typeutil.V(`3.1400`): `3.1400`,
+	} {
+		var d, err = ToDecimal(in)
+
+		assert.NoError(err)
+		assert.Equal(expected, d.String())
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	assert := assert.New(t)
+	var a = MustParseDecimal(`0.1`)
+	var b = MustParseDecimal(`0.20`)
+
+	assert.Equal(`0.30`, a.Add(b).String())
+	assert.True(a.Add(b).Equal(MustParseDecimal(`0.3`)))
+	assert.Equal(`-0.10`, a.Sub(b).String())
+	assert.Equal(`0.020`, a.Mul(b).String())
+	assert.Equal(`0.1`, a.Abs().String())
+	assert.Equal(`-0.1`, a.Neg().String())
+	assert.Equal(-1, a.Cmp(b))
+	assert.Equal(1, b.Cmp(a))
+	assert.Equal(0, MustParseDecimal(`1.50`).Cmp(MustParseDecimal(`1.5`)))
+	assert.Equal(-1, a.Neg().Sign())
+	assert.True(Decimal{}.IsZero())
+
+	// the zero value is usable in arithmetic
+	assert.Equal(`0.1`, Decimal{}.Add(a).String())
+
+	var q, err = DecimalFromInt(1).Div(DecimalFromInt(3), 4, RoundHalfUp)
+	assert.NoError(err)
+	assert.Equal(`0.3333`, q.String())
+
+	q, err = DecimalFromInt(2).Div(DecimalFromInt(3), 2, RoundDown)
+	assert.NoError(err)
+	assert.Equal(`0.66`, q.String())
+
+	q, err = MustParseDecimal(`10.00`).Div(MustParseDecimal(`0.004`), 0, RoundHalfEven)
+	assert.NoError(err)
+	assert.Equal(`2500`, q.String())
+
+	q, err = DecimalFromInt(-7).Div(DecimalFromInt(2), 0, RoundHalfEven)
+	assert.NoError(err)
+	assert.Equal(`-4`, q.String())
+
+	_, err = a.Div(Decimal{}, 2, RoundHalfUp)
+	assert.Error(err)
+}
+
+func TestDecimalRound(t *testing.T) {
+	assert := assert.New(t)
+
+	var cases = []struct {
+		in       string
+		mode     RoundingMode
+		expected string
+	}{
+		{`2.5`, RoundHalfUp, `3`},
+		{`-2.5`, RoundHalfUp, `-3`},
+		{`2.5`, RoundHalfEven, `2`},
+		{`3.5`, RoundHalfEven, `4`},
+		{`-2.5`, RoundHalfEven, `-2`},
+		{`2.5`, RoundHalfDown, `2`},
+		{`2.51`, RoundHalfDown, `3`},
+		{`2.9`, RoundDown, `2`},
+		{`-2.9`, RoundDown, `-2`},
+		{`2.1`, RoundUp, `3`},
+		{`-2.1`, RoundUp, `-3`},
+		{`2.1`, RoundCeiling, `3`},
+		{`-2.9`, RoundCeiling, `-2`},
+		{`2.9`, RoundFloor, `2`},
+		{`-2.1`, RoundFloor, `-3`},
+		{`2.0`, RoundUp, `2`},
+	}
+
+	for _, c := range cases {
+		assert.Equal(c.expected, MustParseDecimal(c.in).Round(0, c.mode).String(), "%s %v", c.in, c.mode)
+	}
+
+	assert.Equal(`1.01`, MustParseDecimal(`1.005`).Round(2, RoundHalfUp).String())
+	assert.Equal(`1.00`, MustParseDecimal(`1.005`).Round(2, RoundHalfEven).String())
+	assert.Equal(`1.5000`, MustParseDecimal(`1.5`).Round(4, RoundHalfUp).String())
+	assert.Equal(`1200`, MustParseDecimal(`1250`).Round(-2, RoundHalfEven).String())
+	assert.Equal(`1.99`, MustParseDecimal(`1.999`).Truncate(2).String())
+	assert.Equal(`1.5`, MustParseDecimal(`1.5000`).Trim().String())
+	assert.Equal(`100`, MustParseDecimal(`100.00`).Trim().String())
+	assert.Equal(`half-even`, RoundHalfEven.String())
+}
+
+func TestDecimalConversions(t *testing.T) {
+	assert := assert.New(t)
+	var d = MustParseDecimal(`-1234.5678`)
+
+	assert.Equal(-1234.5678, d.Float64())
+	assert.Equal(int64(-1234), d.Int64())
+	assert.Equal(`-6172839/5000`, d.Rat().String())
+	assert.Equal(4, d.Scale())
+
+	assert.Equal(-1234.5678, typeutil.Float(d))
+	assert.Equal(`-1234.5678`, typeutil.String(d))
+	assert.Equal(`-1234.5678`, typeutil.V(&d).String())
+	assert.Equal(int64(-1234), typeutil.Int(d))
+}
+
+func TestDecimalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	type invoice struct {
+		Total Decimal  `json:"total"`
+		Tax   *Decimal `json:"tax,omitempty"`
+	}
+
+	var data, err = json.Marshal(invoice{
+		Total: MustParseDecimal(`19.90`),
+	})
+
+	assert.NoError(err)
+	assert.Equal(`{"total":19.90}`, string(data))
+
+	DecimalJSONQuoted = true
+	data, err = json.Marshal(invoice{
+		Total: MustParseDecimal(`19.90`),
+	})
+	DecimalJSONQuoted = false
+
+	assert.NoError(err)
+	assert.Equal(`{"total":"19.90"}`, string(data))
+
+	var out invoice
+
+	assert.NoError(json.Unmarshal([]byte(`{"total":12345678901234567890.123456789,"tax":"1.50"}`), &out))
+	assert.Equal(`12345678901234567890.123456789`, out.Total.String())
+	assert.Equal(`1.50`, out.Tax.String())
+
+	assert.NoError(json.Unmarshal([]byte(`{"total":null}`), &out))
+	assert.Error(json.Unmarshal([]byte(`{"total":"abc"}`), &out))
+}
+
+func TestDecimalSQL(t *testing.T) {
+	assert := assert.New(t)
+	var d Decimal
+
+	assert.NoError(d.Scan(`99.95`))
+	assert.Equal(`99.95`, d.String())
+
+	assert.NoError(d.Scan([]byte(`-0.5`)))
+	assert.Equal(`-0.5`, d.String())
+
+	assert.NoError(d.Scan(int64(7)))
+	assert.Equal(`7`, d.String())
+
+	assert.NoError(d.Scan(2.25))
+	assert.Equal(`2.25`, d.String())
+
+	assert.NoError(d.Scan(nil))
+	assert.True(d.IsZero())
+
+	assert.Error(d.Scan(true))
+
+	var value, err = MustParseDecimal(`1.10`).Value()
+	assert.NoError(err)
+	assert.Equal(`1.10`, value)
+}
@@ -0,0 +1,764 @@
+package mathutil
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ghetzel/go-stockutil/stringutil"
+	"github.com/ghetzel/go-stockutil/typeutil"
+)
+
+// An ExpressionError describes a problem parsing or evaluating an expression, and the byte offset
+// in the expression where it occurred.
+type ExpressionError struct {
+	Message string
+	Offset  int
+}
+
+func (self *ExpressionError) Error() string {
+	return fmt.Sprintf("%s at position %d", self.Message, self.Offset)
+}
+
+// The longest expression (in bytes) that Compile will accept.
+var MaxExpressionLength = 65536
+
+// The deepest nesting of parentheses, function calls and unary or exponent operators that Compile
+// will accept.
+var MaxExpressionDepth = 256
+
+// anything that can look up values by name, such as a *maputil.Map
+type variantGetter interface {
+	Get(key string, fallbacks ...interface{}) typeutil.Variant
+}
+
+type exprFunction struct {
+	minArgs int
+	maxArgs int // -1 for any number
+	call    func(args []float64) float64
+}
+
+var exprFunctions = map[string]exprFunction{
+	`abs`: {1, 1, func(args []float64) float64 {
+		return math.Abs(args[0])
+	}},
+	`clamp`: {3, 3, func(args []float64) float64 {
+		return Clamp(args[0], args[1], args[2])
+	}},
+	`log`: {1, 2, func(args []float64) float64 {
+		if len(args) == 2 {
+			return math.Log(args[0]) / math.Log(args[1])
+		}
+
+		return math.Log(args[0])
+	}},
+	`max`: {1, -1, func(args []float64) float64 {
+		return Max(args)
+	}},
+	`min`: {1, -1, func(args []float64) float64 {
+		return Min(args)
+	}},
+	`pow`: {2, 2, func(args []float64) float64 {
+		return math.Pow(args[0], args[1])
+	}},
+	`round`: {1, 2, func(args []float64) float64 {
+		if len(args) == 2 {
+			return RoundPlaces(args[0], int(args[1]))
+		}
+
+		return Round(args[0])
+	}},
+	`sqrt`: {1, 1, func(args []float64) float64 {
+		return math.Sqrt(args[0])
+	}},
+}
+
+// An Expression is a compiled arithmetic expression that can be evaluated repeatedly against
+// different sets of variables.  Expressions are safe for concurrent use.
+//
+// Expressions support numbers (e.g. 42, 3.5, 1e6), variables, parentheses, and the following
+// operators, from lowest to highest precedence:
+//
+//	||                  logical or
+//	&&                  logical and
+//	== !=               equality
+//	< <= > >=           comparison
+//	+ -                 addition and subtraction
+//	* / %               multiplication, division and remainder
+//	- + !               negation, unary plus and logical not
+//	^                   exponentiation (right-associative)
+//
+// All values are numbers: comparison and logical operators return 1 for true and 0 for false,
+// and treat any non-zero value as true.  The && and || operators short-circuit.
+//
+// Variable names may contain letters, digits, underscores and dots (e.g. "net.rx_bytes").  The
+// following functions are available: abs(x), clamp(x, lower, upper), log(x) (natural logarithm),
+// log(x, base), max(x, ...), min(x, ...), pow(x, y), round(x), round(x, places), and sqrt(x).
+type Expression struct {
+	source    string
+	root      exprNode
+	variables []string
+}
+
+// Parses the given expression, returning an *ExpressionError describing the first problem found.
+func Compile(expr string) (*Expression, error) {
+	if len(expr) > MaxExpressionLength {
+		return nil, &ExpressionError{
+			Message: fmt.Sprintf("expression is longer than %d bytes", MaxExpressionLength),
+			Offset:  MaxExpressionLength,
+		}
+	}
+
+	var parser = &exprParser{
+		source:    expr,
+		variables: make(map[string]bool),
+	}
+
+	if err := parser.tokenize(); err != nil {
+		return nil, err
+	}
+
+	var root, err = parser.parseOr()
+
+	if err != nil {
+		return nil, err
+	} else if tok := parser.peek(); tok.kind != exprEnd {
+		return nil, parser.unexpected(tok)
+	}
+
+	var compiled = &Expression{
+		source:    expr,
+		root:      root,
+		variables: make([]string, 0, len(parser.variables)),
+	}
+
+	for name := range parser.variables {
+		compiled.variables = append(compiled.variables, name)
+	}
+
+	sort.Strings(compiled.variables)
+
+	return compiled, nil
+}
+
+// Same as Compile, but panics if the expression cannot be parsed.
+func MustCompile(expr string) *Expression {
+	if compiled, err := Compile(expr); err == nil {
+		return compiled
+	} else {
+		panic(err.Error())
+	}
+}
+
+// Compiles and evaluates the given expression in one step.  See Expression.Eval.
+func Eval(expr string, vars interface{}) (float64, error) {
+	if compiled, err := Compile(expr); err == nil {
+		return compiled.Eval(vars)
+	} else {
+		return 0, err
+	}
+}
+
+// Evaluates the expression, resolving variables from vars, which may be nil, a map with string
+// keys, or anything with a Get(key string, fallbacks ...interface{}) typeutil.Variant method (such
+// as a *maputil.Map, which also resolves dotted names as nested keys).  Variable values may be
+// numbers, numeric strings, booleans, or anything else convertible with stringutil.ConvertToFloat.
+// Referring to a variable that is missing or not numeric is an error, as is dividing by zero.
+func (self *Expression) Eval(vars interface{}) (float64, error) {
+	return self.root.eval(vars)
+}
+
+// Evaluates the expression and returns whether the result is non-zero.
+func (self *Expression) EvalBool(vars interface{}) (bool, error) {
+	var result, err = self.Eval(vars)
+
+	return (result != 0 && !math.IsNaN(result)), err
+}
+
+// Returns the names of the variables referred to in the expression, sorted and without
+// duplicates.
+func (self *Expression) Variables() []string {
+	var out = make([]string, len(self.variables))
+
+	copy(out, self.variables)
+
+	return out
+}
+
+// Returns the source text of the expression.
+func (self *Expression) String() string {
+	return self.source
+}
+
+func lookupVariable(vars interface{}, name string) (interface{}, bool) {
+	switch vars := vars.(type) {
+	case nil:
+		return nil, false
+	case map[string]float64:
+		var value, ok = vars[name]
+		return value, ok
+	case map[string]interface{}:
+		var value, ok = vars[name]
+		return value, ok
+	case variantGetter:
+		var value = vars.Get(name).Value
+		return value, (value != nil)
+	}
+
+	var varsV = reflect.ValueOf(vars)
+
+	for varsV.Kind() == reflect.Ptr && !varsV.IsNil() {
+		varsV = varsV.Elem()
+	}
+
+	if varsV.Kind() == reflect.Map && varsV.Type().Key().Kind() == reflect.String {
+		if value := varsV.MapIndex(reflect.ValueOf(name).Convert(varsV.Type().Key())); value.IsValid() {
+			return value.Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+func boolToFloat(in bool) float64 {
+	if in {
+		return 1
+	}
+
+	return 0
+}
+
+type exprNode interface {
+	eval(vars interface{}) (float64, error)
+}
+
+type exprNumber float64
+
+func (self exprNumber) eval(vars interface{}) (float64, error) {
+	return float64(self), nil
+}
+
+type exprVariable struct {
+	name   string
+	offset int
+}
+
+func (self *exprVariable) eval(vars interface{}) (float64, error) {
+	var value, ok = lookupVariable(vars, self.name)
+
+	if !ok || value == nil {
+		return 0, &ExpressionError{
+			Message: fmt.Sprintf("undefined variable %q", self.name),
+			Offset:  self.offset,
+		}
+	}
+
+	if v, ok := value.(typeutil.Variant); ok {
+		value = v.Value
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		return boolToFloat(v), nil
+	}
+
+	if f, err := stringutil.ConvertToFloat(value); err == nil {
+		return f, nil
+	} else {
+		return 0, &ExpressionError{
+			Message: fmt.Sprintf("variable %q is not a number", self.name),
+			Offset:  self.offset,
+		}
+	}
+}
+
+type exprUnary struct {
+	operator string
+	operand  exprNode
+}
+
+func (self *exprUnary) eval(vars interface{}) (float64, error) {
+	var value, err = self.operand.eval(vars)
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch self.operator {
+	case `-`:
+		return -value, nil
+	case `!`:
+		return boolToFloat(value == 0), nil
+	default:
+		return value, nil
+	}
+}
+
+type exprBinary struct {
+	operator string
+	left     exprNode
+	right    exprNode
+	offset   int
+}
+
+func (self *exprBinary) eval(vars interface{}) (float64, error) {
+	var left, err = self.left.eval(vars)
+
+	if err != nil {
+		return 0, err
+	}
+
+	// short-circuit the logical operators
+	switch self.operator {
+	case `&&`:
+		if left == 0 {
+			return 0, nil
+		}
+	case `||`:
+		if left != 0 {
+			return 1, nil
+		}
+	}
+
+	right, err := self.right.eval(vars)
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch self.operator {
+	case `+`:
+		return left + right, nil
+	case `-`:
+		return left - right, nil
+	case `*`:
+		return left * right, nil
+	case `/`, `%`:
+		if right == 0 {
+			return 0, &ExpressionError{
+				Message: `division by zero`,
+				Offset:  self.offset,
+			}
+		} else if self.operator == `%` {
+			return math.Mod(left, right), nil
+		}
+
+		return left / right, nil
+	case `^`:
+		return math.Pow(left, right), nil
+	case `==`:
+		return boolToFloat(left == right), nil
+	case `!=`:
+		return boolToFloat(left != right), nil
+	case `<`:
+		return boolToFloat(left < right), nil
+	case `<=`:
+		return boolToFloat(left <= right), nil
+	case `>`:
+		return boolToFloat(left > right), nil
+	case `>=`:
+		return boolToFloat(left >= right), nil
+	case `&&`, `||`:
+		return boolToFloat(right != 0), nil
+	default:
+		return 0, &ExpressionError{
+			Message: fmt.Sprintf("unknown operator %q", self.operator),
+			Offset:  self.offset,
+		}
+	}
+}
+
+type exprCall struct {
+	function exprFunction
+	args     []exprNode
+}
+
+func (self *exprCall) eval(vars interface{}) (float64, error) {
+	var args = make([]float64, len(self.args))
+
+	for i, arg := range self.args {
+		if value, err := arg.eval(vars); err == nil {
+			args[i] = value
+		} else {
+			return 0, err
+		}
+	}
+
+	return self.function.call(args), nil
+}
+
+type exprTokenKind int
+
+const (
+	exprEnd exprTokenKind = iota
+	exprNumberToken
+	exprIdentifier
+	exprOperator
+)
+
+type exprToken struct {
+	kind   exprTokenKind
+	text   string
+	offset int
+}
+
+type exprParser struct {
+	source    string
+	tokens    []exprToken
+	position  int
+	depth     int
+	variables map[string]bool
+}
+
+// operators, longest first so that "<=" is matched before "<"
+var exprOperators = []string{`&&`, `||`, `==`, `!=`, `<=`, `>=`, `<`, `>`, `+`, `-`, `*`, `/`, `%`, `^`, `!`, `(`, `)`, `,`}
+
+func (self *exprParser) tokenize() error {
+	var in = self.source
+
+	for i := 0; i < len(in); {
+		var r, size = utf8.DecodeRuneInString(in[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case isASCIIDigit(r) || (r == '.' && i+1 < len(in) && isASCIIDigit(rune(in[i+1]))):
+			var start = i
+
+			for i < len(in) && (isASCIIDigit(rune(in[i])) || in[i] == '.') {
+				i++
+			}
+
+			// exponent
+			if i < len(in) && (in[i] == 'e' || in[i] == 'E') {
+				var j = i + 1
+
+				if j < len(in) && (in[j] == '+' || in[j] == '-') {
+					j++
+				}
+
+				if j < len(in) && isASCIIDigit(rune(in[j])) {
+					for i = j; i < len(in) && isASCIIDigit(rune(in[i])); i++ {
+					}
+				}
+			}
+
+			if _, err := strconv.ParseFloat(in[start:i], 64); err != nil {
+				return &ExpressionError{
+					Message: fmt.Sprintf("invalid number %q", in[start:i]),
+					Offset:  start,
+				}
+			}
+
+			self.tokens = append(self.tokens, exprToken{exprNumberToken, in[start:i], start})
+
+		case r == '_' || unicode.IsLetter(r):
+			var start = i
+
+			for i < len(in) {
+				var c, n = utf8.DecodeRuneInString(in[i:])
+
+				if c != '_' && c != '.' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+					break
+				}
+
+				i += n
+			}
+
+			self.tokens = append(self.tokens, exprToken{exprIdentifier, in[start:i], start})
+
+		default:
+			var matched bool
+
+			for _, op := range exprOperators {
+				if strings.HasPrefix(in[i:], op) {
+					self.tokens = append(self.tokens, exprToken{exprOperator, op, i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+
+			if !matched {
+				return &ExpressionError{
+					Message: fmt.Sprintf("unexpected %q", r),
+					Offset:  i,
+				}
+			}
+		}
+	}
+
+	self.tokens = append(self.tokens, exprToken{exprEnd, ``, len(in)})
+
+	return nil
+}
+
+func (self *exprParser) peek() exprToken {
+	return self.tokens[self.position]
+}
+
+func (self *exprParser) next() exprToken {
+	var tok = self.tokens[self.position]
+
+	if tok.kind != exprEnd {
+		self.position++
+	}
+
+	return tok
+}
+
+// consumes the next token if it is one of the given operators
+func (self *exprParser) accept(operators ...string) (exprToken, bool) {
+	if tok := self.peek(); tok.kind == exprOperator {
+		for _, op := range operators {
+			if tok.text == op {
+				return self.next(), true
+			}
+		}
+	}
+
+	return exprToken{}, false
+}
+
+func (self *exprParser) unexpected(tok exprToken) error {
+	if tok.kind == exprEnd {
+		return &ExpressionError{
+			Message: `unexpected end of expression`,
+			Offset:  tok.offset,
+		}
+	}
+
+	return &ExpressionError{
+		Message: fmt.Sprintf("unexpected %q", tok.text),
+		Offset:  tok.offset,
+	}
+}
+
+// parses a left-associative chain of the given operators over operands parsed by next
+func (self *exprParser) parseBinary(next func() (exprNode, error), operators ...string) (exprNode, error) {
+	var left, err = next()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var tok, ok = self.accept(operators...)
+
+		if !ok {
+			return left, nil
+		}
+
+		right, err := next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		left = &exprBinary{
+			operator: tok.text,
+			left:     left,
+			right:    right,
+			offset:   tok.offset,
+		}
+	}
+}
+
+func (self *exprParser) parseOr() (exprNode, error) {
+	return self.parseBinary(self.parseAnd, `||`)
+}
+
+func (self *exprParser) parseAnd() (exprNode, error) {
+	return self.parseBinary(self.parseEquality, `&&`)
+}
+
+func (self *exprParser) parseEquality() (exprNode, error) {
+	return self.parseBinary(self.parseComparison, `==`, `!=`)
+}
+
+func (self *exprParser) parseComparison() (exprNode, error) {
+	return self.parseBinary(self.parseAdditive, `<`, `<=`, `>`, `>=`)
+}
+
+func (self *exprParser) parseAdditive() (exprNode, error) {
+	return self.parseBinary(self.parseMultiplicative, `+`, `-`)
+}
+
+func (self *exprParser) parseMultiplicative() (exprNode, error) {
+	return self.parseBinary(self.parseUnary, `*`, `/`, `%`)
+}
+
+func (self *exprParser) parseUnary() (exprNode, error) {
+	// every recursive path through the parser passes through here, so bounding the depth here
+	// keeps hostile input from exhausting the stack
+	if self.depth++; self.depth > MaxExpressionDepth {
+		return nil, &ExpressionError{
+			Message: `expression is nested too deeply`,
+			Offset:  self.peek().offset,
+		}
+	}
+
+	defer func() {
+		self.depth--
+	}()
+
+	if tok, ok := self.accept(`-`, `+`, `!`); ok {
+		if operand, err := self.parseUnary(); err == nil {
+			return &exprUnary{
+				operator: tok.text,
+				operand:  operand,
+			}, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return self.parsePower()
+}
+
+func (self *exprParser) parsePower() (exprNode, error) {
+	var base, err = self.parsePrimary()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, ok := self.accept(`^`); ok {
+		// right-associative, and the exponent may carry its own sign (e.g. 2^-1)
+		if exponent, err := self.parseUnary(); err == nil {
+			return &exprBinary{
+				operator: `^`,
+				left:     base,
+				right:    exponent,
+				offset:   tok.offset,
+			}, nil
+		} else {
+			return nil, err
+		}
+	}
+
+	return base, nil
+}
+
+func (self *exprParser) parsePrimary() (exprNode, error) {
+	var tok = self.next()
+
+	switch tok.kind {
+	case exprNumberToken:
+		var value, _ = strconv.ParseFloat(tok.text, 64)
+
+		return exprNumber(value), nil
+
+	case exprIdentifier:
+		if _, ok := self.accept(`(`); ok {
+			return self.parseCall(tok)
+		}
+
+		self.variables[tok.text] = true
+
+		return &exprVariable{
+			name:   tok.text,
+			offset: tok.offset,
+		}, nil
+
+	case exprOperator:
+		if tok.text == `(` {
+			var inner, err = self.parseOr()
+
+			if err != nil {
+				return nil, err
+			} else if _, ok := self.accept(`)`); !ok {
+				return nil, self.expected(`')'`)
+			}
+
+			return inner, nil
+		}
+	}
+
+	return nil, self.unexpected(tok)
+}
+
+func (self *exprParser) parseCall(name exprToken) (exprNode, error) {
+	var function, ok = exprFunctions[strings.ToLower(name.text)]
+
+	if !ok {
+		return nil, &ExpressionError{
+			Message: fmt.Sprintf("unknown function %q", name.text),
+			Offset:  name.offset,
+		}
+	}
+
+	var args = make([]exprNode, 0)
+
+	if _, ok := self.accept(`)`); !ok {
+		for {
+			if arg, err := self.parseOr(); err == nil {
+				args = append(args, arg)
+			} else {
+				return nil, err
+			}
+
+			if _, ok := self.accept(`)`); ok {
+				break
+			} else if _, ok := self.accept(`,`); !ok {
+				return nil, self.expected(`',' or ')'`)
+			}
+		}
+	}
+
+	if len(args) < function.minArgs || (function.maxArgs >= 0 && len(args) > function.maxArgs) {
+		var expected string
+		var noun = `arguments`
+
+		if function.minArgs == 1 && function.maxArgs <= 1 {
+			noun = `argument`
+		}
+
+		switch {
+		case function.minArgs == function.maxArgs:
+			expected = strconv.Itoa(function.minArgs)
+		case function.maxArgs < 0:
+			expected = fmt.Sprintf("at least %d", function.minArgs)
+		default:
+			expected = fmt.Sprintf("%d or %d", function.minArgs, function.maxArgs)
+		}
+
+		return nil, &ExpressionError{
+			Message: fmt.Sprintf("%s() expects %s %s, got %d", name.text, expected, noun, len(args)),
+			Offset:  name.offset,
+		}
+	}
+
+	return &exprCall{
+		function: function,
+		args:     args,
+	}, nil
+}
+
+func (self *exprParser) expected(what string) error {
+	var tok = self.peek()
+
+	if tok.kind == exprEnd {
+		return &ExpressionError{
+			Message: fmt.Sprintf("expected %s but reached end of expression", what),
+			Offset:  tok.offset,
+		}
+	}
+
+	return &ExpressionError{
+		Message: fmt.Sprintf("expected %s but found %q", what, tok.text),
+		Offset:  tok.offset,
+	}
+}
+
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
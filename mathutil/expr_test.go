@@ -0,0 +1,209 @@
+package mathutil
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ghetzel/go-stockutil/maputil"
+	"github.com/ghetzel/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	assert := assert.New(t)
+
+	for expr, expected := range map[string]float64{
+		`1 + 2 * 3`:          7,
+		`(1 + 2) * 3`:        9,
+		`10 - 4 - 3`:         3,
+		`100 / 10 / 5`:       2,
+		`7 % 4`:              3,
+		`2 ^ 3 ^ 2`:          512,
+		`-2 ^ 2`:             -4,
+		`2 ^ -1`:             0.5,
+		`--3`:                3,
+		`+.5 + 1e3`:          1000.5,
+		`1.5E-1 * 10`:        1.5,
+		`3 > 2`:              1,
+		`3 < 2`:              0,
+		`2 <= 2 && 2 >= 3`:   0,
+		`1 == 1 || 1 / 0`:    1,
+		`0 && 1 / 0`:         0,
+		`1 != 2`:             1,
+		`!0 + !5`:            1,
+		`1 + 2 > 2 == 1`:     1,
+		`abs(-4)`:            4,
+		`min(3, 1, 2)`:       1,
+		`max(3, 1, 2)`:       3,
+		`round(2.5)`:         3,
+		`round(3.14159, 2)`:  3.14,
+		`sqrt(16)`:           4,
+		`log(8, 2)`:          3,
+		`pow(2, 10)`:         1024,
+		`clamp(15, 0, 10)`:   10,
+		`MAX(1, min(5, 4))`:  4,
+		`max(1, 2) * (3)`:    6,
+		`  42  `:             42,
+		`round(log(1) + 1)`:  1,
+		`clamp(-1, 0, 10)^2`: 0,
+	} {
+		var actual, err = Eval(expr, nil)
+
+		assert.NoError(err, expr)
+		assert.InDelta(expected, actual, 1e-12, expr)
+	}
+}
+
+func TestEvalVariables(t *testing.T) {
+	assert := assert.New(t)
+	var expr = MustCompile(`(rx_bytes + tx_bytes) / uptime * 8`)
+
+	assert.Equal([]string{`rx_bytes`, `tx_bytes`, `uptime`}, expr.Variables())
+	assert.Equal(`(rx_bytes + tx_bytes) / uptime * 8`, expr.String())
+
+	var actual, err = expr.Eval(map[string]interface{}{
+		`rx_bytes`: 600,
+		`tx_bytes`: int64(400),
+		`uptime`:   `10`,
+	})
+
+	assert.NoError(err)
+	assert.Equal(float64(800), actual)
+
+	actual, err = expr.Eval(map[string]float64{
+		`rx_bytes`: 1,
+		`tx_bytes`: 1,
+		`uptime`:   2,
+	})
+
+	assert.NoError(err)
+	assert.Equal(float64(8), actual)
+
+	actual, err = expr.Eval(map[string]int{
+		`rx_bytes`: 5,
+		`tx_bytes`: 5,
+		`uptime`:   10,
+	})
+
+	assert.NoError(err)
+	assert.Equal(float64(8), actual)
+
+	actual, err = Eval(`net.rx * 2 + enabled`, maputil.M(map[string]interface{}{
+		`net`: map[string]interface{}{
+			`rx`: 21,
+		},
+		`enabled`: true,
+	}))
+
+	assert.NoError(err)
+	assert.Equal(float64(43), actual)
+
+	ok, err := MustCompile(`load > 0.75 && !maintenance`).EvalBool(map[string]interface{}{
+		`load`:        0.9,
+		`maintenance`: false,
+	})
+
+	assert.NoError(err)
+	assert.True(ok)
+}
+
+func TestEvalRuntimeErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	var _, err = Eval(`a + b`, map[string]interface{}{
+		`a`: 1,
+	})
+
+	assert.EqualError(err, `undefined variable "b" at position 4`)
+
+	_, err = Eval(`a * 2`, map[string]interface{}{
+		`a`: `lots`,
+	})
+
+	assert.EqualError(err, `variable "a" is not a number at position 0`)
+
+	_, err = Eval(`10 / (x - 1)`, map[string]float64{
+		`x`: 1,
+	})
+
+	assert.EqualError(err, `division by zero at position 3`)
+
+	_, err = Eval(`5 % 0`, nil)
+	assert.IsType(&ExpressionError{}, err)
+
+	var result, _ = Eval(`sqrt(-1)`, nil)
+	assert.True(math.IsNaN(result))
+}
+
+func TestCompileErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	for expr, message := range map[string]string{
+		``:                `unexpected end of expression at position 0`,
+		`1 +`:             `unexpected end of expression at position 3`,
+		`(1 + 2`:          `expected ')' but reached end of expression at position 6`,
+		`1 + 2)`:          `unexpected ")" at position 5`,
+		`2 $ 3`:           `unexpected '$' at position 2`,
+		`1.2.3 + 1`:       `invalid number "1.2.3" at position 0`,
+		`foo(1)`:          `unknown function "foo" at position 0`,
+		`pow(2)`:          `pow() expects 2 arguments, got 1 at position 0`,
+		`abs(1, 2)`:       `abs() expects 1 argument, got 2 at position 0`,
+		`min()`:           `min() expects at least 1 argument, got 0 at position 0`,
+		`round(1, 2, 3)`:  `round() expects 1 or 2 arguments, got 3 at position 0`,
+		`max(1 2)`:        `expected ',' or ')' but found "2" at position 6`,
+		`1 2`:             `unexpected "2" at position 2`,
+		`x = 1`:           `unexpected '=' at position 2`,
+		`exec("rm -rf")`:  `unexpected '"' at position 5`,
+		`a.b.c(1)`:        `unknown function "a.b.c" at position 0`,
+		`1 + * 2`:         `unexpected "*" at position 4`,
+		`clamp(1, 2, 3`:   `expected ',' or ')' but reached end of expression at position 13`,
+		`max(,)`:          `unexpected "," at position 4`,
+		`!`:               `unexpected end of expression at position 1`,
+		`2 ^`:             `unexpected end of expression at position 3`,
+		`sqrt(4) sqrt(9)`: `unexpected "sqrt" at position 8`,
+	} {
+		var _, err = Compile(expr)
+
+		if assert.Error(err, expr) {
+			assert.Equal(message, err.Error(), expr)
+			assert.IsType(&ExpressionError{}, err, expr)
+		}
+	}
+
+	assert.Panics(func() {
+		MustCompile(`(`)
+	})
+}
+
+func TestCompileLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	// nesting up to the limit is fine
+	var value, err = Eval(strings.Repeat(`(`, MaxExpressionDepth-1)+`1`+strings.Repeat(`)`, MaxExpressionDepth-1), nil)
+	assert.NoError(err)
+	assert.Equal(float64(1), value)
+
+	for _, expr := range []string{
+		strings.Repeat(`(`, 1000000) + `1` + strings.Repeat(`)`, 1000000),
+		strings.Repeat(`(`, MaxExpressionDepth+1) + `1` + strings.Repeat(`)`, MaxExpressionDepth+1),
+		strings.Repeat(`-`, MaxExpressionDepth+1) + `1`,
+		strings.Repeat(`2^`, MaxExpressionDepth+1) + `1`,
+		strings.Repeat(`abs(`, MaxExpressionDepth+1) + `1` + strings.Repeat(`)`, MaxExpressionDepth+1),
+	} {
+		var _, err = Compile(expr)
+
+		assert.IsType(&ExpressionError{}, err)
+	}
+
+	_, err = Compile(strings.Repeat(`(`, MaxExpressionDepth+1) + `1` + strings.Repeat(`)`, MaxExpressionDepth+1))
+	assert.EqualError(err, fmt.Sprintf("expression is nested too deeply at position %d", MaxExpressionDepth))
+
+	_, err = Compile(strings.Repeat(`1+`, MaxExpressionLength) + `1`)
+	assert.EqualError(err, fmt.Sprintf("expression is longer than %d bytes at position %d", MaxExpressionLength, MaxExpressionLength))
+
+	// long flat chains within the length limit still evaluate
+	value, err = Eval(strings.Repeat(`1+`, 30000)+`1`, nil)
+	assert.NoError(err)
+	assert.Equal(float64(30001), value)
+}
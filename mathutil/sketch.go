@@ -0,0 +1,346 @@
+package mathutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// The relative accuracy used by NewQuantileSketch when given an invalid one.
+var DefaultSketchAccuracy = 0.01
+
+// A QuantileSketch estimates quantiles of a stream of values in bounded space, using the DDSketch
+// algorithm.  Every quantile it returns is within a fixed relative error of the true value (e.g.
+// an accuracy of 0.01 means within 1%).  Sketches with the same accuracy can be merged, and
+// sketches can be serialized to and from JSON.  QuantileSketches must be created with
+// NewQuantileSketch, and are safe for concurrent use.
+type QuantileSketch struct {
+	accuracy float64
+	gamma    float64
+	logGamma float64
+	positive map[int]uint64
+	negative map[int]uint64
+	zeros    uint64
+	count    uint64
+	sum      float64
+	min      float64
+	max      float64
+	lock     sync.RWMutex
+}
+
+// the serialized form of a QuantileSketch
+type quantileSketchJSON struct {
+	Accuracy float64        `json:"accuracy"`
+	Positive map[int]uint64 `json:"positive,omitempty"`
+	Negative map[int]uint64 `json:"negative,omitempty"`
+	Zeros    uint64         `json:"zeros,omitempty"`
+	Count    uint64         `json:"count"`
+	Sum      sketchFloat    `json:"sum"`
+	Min      float64        `json:"min"`
+	Max      float64        `json:"max"`
+}
+
+// the sum of a sketch can overflow to infinity even though its values are finite, which JSON
+// numbers cannot represent, so non-finite values are encoded as the strings "+Inf", "-Inf" and
+// "NaN".
+type sketchFloat float64
+
+func (self sketchFloat) MarshalJSON() ([]byte, error) {
+	var f = float64(self)
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return []byte(strconv.Quote(strconv.FormatFloat(f, 'g', -1, 64))), nil
+	}
+
+	return json.Marshal(f)
+}
+
+func (self *sketchFloat) UnmarshalJSON(data []byte) error {
+	var s = string(data)
+
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		*self = sketchFloat(f)
+		return nil
+	} else {
+		return fmt.Errorf("invalid sketch value %q", string(data))
+	}
+}
+
+// Returns a new QuantileSketch with the given relative accuracy.  Accuracies outside of (0, 1)
+// are replaced with DefaultSketchAccuracy.
+func NewQuantileSketch(accuracy float64) *QuantileSketch {
+	var sketch = new(QuantileSketch)
+
+	sketch.init(accuracy)
+
+	return sketch
+}
+
+func (self *QuantileSketch) init(accuracy float64) {
+	if !(accuracy > 0 && accuracy < 1) {
+		accuracy = DefaultSketchAccuracy
+	}
+
+	self.accuracy = accuracy
+	self.gamma = (1 + accuracy) / (1 - accuracy)
+	self.logGamma = math.Log(self.gamma)
+	self.positive = make(map[int]uint64)
+	self.negative = make(map[int]uint64)
+	self.zeros = 0
+	self.count = 0
+	self.sum = 0
+	self.min = 0
+	self.max = 0
+}
+
+// Returns the relative accuracy of the sketch.
+func (self *QuantileSketch) Accuracy() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	return self.accuracy
+}
+
+// Adds the given values to the sketch.  NaN and infinite values are ignored.
+func (self *QuantileSketch) Add(values ...float64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	for _, value := range values {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			continue
+		}
+
+		switch {
+		case value > 0:
+			self.positive[self.key(value)]++
+		case value < 0:
+			self.negative[self.key(-value)]++
+		default:
+			self.zeros++
+		}
+
+		if self.count == 0 {
+			self.min, self.max = value, value
+		} else {
+			self.min = math.Min(self.min, value)
+			self.max = math.Max(self.max, value)
+		}
+
+		self.count++
+		self.sum += value
+	}
+}
+
+// Returns the number of values added.
+func (self *QuantileSketch) Count() uint64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	return self.count
+}
+
+// Returns the sum of the values added, which may overflow to ±Inf (or NaN, if it overflowed in
+// both directions) even though the values themselves are finite.
+func (self *QuantileSketch) Sum() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	return self.sum
+}
+
+// Returns the exact smallest value added, or NaN if there are none.
+func (self *QuantileSketch) Min() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count == 0 {
+		return math.NaN()
+	}
+
+	return self.min
+}
+
+// Returns the exact largest value added, or NaN if there are none.
+func (self *QuantileSketch) Max() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count == 0 {
+		return math.NaN()
+	}
+
+	return self.max
+}
+
+// Returns the estimated qth quantile (0-1) of the values added, or NaN if there are none or q is
+// out of range.  The 0th and 1st quantiles are the exact minimum and maximum.
+func (self *QuantileSketch) Quantile(q float64) float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	} else if q == 0 {
+		return self.min
+	} else if q == 1 {
+		return self.max
+	}
+
+	var rank = uint64(q * float64(self.count-1))
+	var seen uint64
+
+	// negative values, from most to least negative
+	for _, key := range sortedKeys(self.negative, true) {
+		if seen += self.negative[key]; seen > rank {
+			return Clamp(-self.value(key), self.min, self.max)
+		}
+	}
+
+	if seen += self.zeros; seen > rank {
+		return 0
+	}
+
+	for _, key := range sortedKeys(self.positive, false) {
+		if seen += self.positive[key]; seen > rank {
+			return Clamp(self.value(key), self.min, self.max)
+		}
+	}
+
+	return self.max
+}
+
+// Combines the values from other into this sketch.  Both sketches must have the same accuracy.
+func (self *QuantileSketch) Merge(other *QuantileSketch) error {
+	var snapshot = other.snapshot()
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if snapshot.Accuracy != self.accuracy {
+		return fmt.Errorf("cannot merge sketches with accuracies %v and %v", self.accuracy, snapshot.Accuracy)
+	}
+
+	self.merge(snapshot)
+
+	return nil
+}
+
+func (self *QuantileSketch) merge(other *quantileSketchJSON) {
+	if other.Count == 0 {
+		return
+	}
+
+	for key, n := range other.Positive {
+		self.positive[key] += n
+	}
+
+	for key, n := range other.Negative {
+		self.negative[key] += n
+	}
+
+	if self.count == 0 {
+		self.min, self.max = other.Min, other.Max
+	} else {
+		self.min = math.Min(self.min, other.Min)
+		self.max = math.Max(self.max, other.Max)
+	}
+
+	self.zeros += other.Zeros
+	self.count += other.Count
+	self.sum += float64(other.Sum)
+}
+
+// Discards all values added.
+func (self *QuantileSketch) Reset() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.init(self.accuracy)
+}
+
+// QuantileSketches are marshaled as a JSON object containing their accuracy and bucket counts.
+func (self *QuantileSketch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(self.snapshot())
+}
+
+// Replaces the contents of the sketch with one previously marshaled with MarshalJSON.
+func (self *QuantileSketch) UnmarshalJSON(data []byte) error {
+	var in quantileSketchJSON
+
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	if !(in.Accuracy > 0 && in.Accuracy < 1) {
+		return fmt.Errorf("invalid sketch accuracy %v", in.Accuracy)
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.init(in.Accuracy)
+	self.merge(&in)
+
+	return nil
+}
+
+func (self *QuantileSketch) snapshot() *quantileSketchJSON {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	var out = &quantileSketchJSON{
+		Accuracy: self.accuracy,
+		Positive: make(map[int]uint64, len(self.positive)),
+		Negative: make(map[int]uint64, len(self.negative)),
+		Zeros:    self.zeros,
+		Count:    self.count,
+		Sum:      sketchFloat(self.sum),
+		Min:      self.min,
+		Max:      self.max,
+	}
+
+	for key, n := range self.positive {
+		out.Positive[key] = n
+	}
+
+	for key, n := range self.negative {
+		out.Negative[key] = n
+	}
+
+	return out
+}
+
+// returns the bucket for the given positive value: bucket k holds values in (gamma^(k-1), gamma^k]
+func (self *QuantileSketch) key(value float64) int {
+	return int(math.Ceil(math.Log(value) / self.logGamma))
+}
+
+// returns the representative value for the given bucket, which is within the sketch's accuracy
+// of every value in it
+func (self *QuantileSketch) value(key int) float64 {
+	return 2 * math.Pow(self.gamma, float64(key)) / (self.gamma + 1)
+}
+
+func sortedKeys(buckets map[int]uint64, descending bool) []int {
+	var keys = make([]int, 0, len(buckets))
+
+	for key := range buckets {
+		keys = append(keys, key)
+	}
+
+	if descending {
+		sort.Sort(sort.Reverse(sort.IntSlice(keys)))
+	} else {
+		sort.Ints(keys)
+	}
+
+	return keys
+}
@@ -0,0 +1,179 @@
+package mathutil
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/ghetzel/testify/assert"
+)
+
+func TestQuantileSketch(t *testing.T) {
+	assert := assert.New(t)
+	var sketch = NewQuantileSketch(0.01)
+	var rng = rand.New(rand.NewSource(42))
+	var values = make([]float64, 10000)
+
+	assert.True(math.IsNaN(sketch.Quantile(0.5)))
+
+	for i := range values {
+		values[i] = rng.ExpFloat64()*100 - 20
+	}
+
+	sketch.Add(values...)
+	sort.Float64s(values)
+
+	assert.Equal(uint64(len(values)), sketch.Count())
+	assert.InDelta(Sum(values), sketch.Sum(), 1e-6)
+	assert.Equal(values[0], sketch.Min())
+	assert.Equal(values[len(values)-1], sketch.Max())
+	assert.Equal(values[0], sketch.Quantile(0))
+	assert.Equal(values[len(values)-1], sketch.Quantile(1))
+
+	for _, q := range []float64{0.01, 0.1, 0.25, 0.5, 0.75, 0.9, 0.99} {
+		var expected = values[int(q*float64(len(values)-1))]
+
+		assert.InDelta(expected, sketch.Quantile(q), math.Abs(expected)*0.01+1e-9, "q=%v", q)
+	}
+
+	assert.True(math.IsNaN(sketch.Quantile(1.5)))
+
+	sketch.Reset()
+	assert.Equal(uint64(0), sketch.Count())
+	assert.Equal(0.01, sketch.Accuracy())
+}
+
+func TestQuantileSketchZeros(t *testing.T) {
+	assert := assert.New(t)
+	var sketch = NewQuantileSketch(0)
+
+	sketch.Add(0, 0, 0, 5, math.NaN(), math.Inf(1))
+
+	assert.Equal(DefaultSketchAccuracy, sketch.Accuracy())
+	assert.Equal(uint64(4), sketch.Count())
+	assert.Equal(float64(0), sketch.Quantile(0.5))
+	assert.Equal(float64(5), sketch.Quantile(1))
+}
+
+func TestQuantileSketchMerge(t *testing.T) {
+	assert := assert.New(t)
+	var a, b, all = NewQuantileSketch(0.02), NewQuantileSketch(0.02), NewQuantileSketch(0.02)
+
+	for i := 1; i <= 1000; i++ {
+		if i%2 == 0 {
+			a.Add(float64(i))
+		} else {
+			b.Add(float64(-i))
+		}
+
+		all.Add(float64(i) * float64(1-2*(i%2)))
+	}
+
+	assert.NoError(a.Merge(b))
+	assert.Equal(all.Count(), a.Count())
+	assert.Equal(all.Min(), a.Min())
+	assert.Equal(all.Max(), a.Max())
+
+	for _, q := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		assert.Equal(all.Quantile(q), a.Quantile(q))
+	}
+
+	assert.Error(a.Merge(NewQuantileSketch(0.05)))
+}
+
+func TestQuantileSketchJSON(t *testing.T) {
+	assert := assert.New(t)
+	var sketch = NewQuantileSketch(0.01)
+
+	sketch.Add(-3, 0, 1, 2, 3, 1000)
+
+	var data, err = json.Marshal(sketch)
+	assert.NoError(err)
+
+	var decoded = NewQuantileSketch(0.5)
+	assert.NoError(json.Unmarshal(data, decoded))
+
+	assert.Equal(sketch.Accuracy(), decoded.Accuracy())
+	assert.Equal(sketch.Count(), decoded.Count())
+	assert.Equal(sketch.Sum(), decoded.Sum())
+
+	for _, q := range []float64{0, 0.2, 0.5, 0.8, 1} {
+		assert.Equal(sketch.Quantile(q), decoded.Quantile(q))
+	}
+
+	assert.Error(json.Unmarshal([]byte(`{"accuracy":2}`), decoded))
+}
+
+func TestQuantileSketchJSONOverflow(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, values := range [][]float64{
+		{1e308, 1e308},
+		{-1e308, -1e308},
+		{1e308, 1e308, -1e308, -1e308, -1e308},
+	} {
+		var sketch = NewQuantileSketch(0.01)
+
+		sketch.Add(values...)
+		sketch.Add(1e308, -1e308)
+
+		var data, err = json.Marshal(sketch)
+		assert.NoError(err)
+
+		var decoded = NewQuantileSketch(0.01)
+		assert.NoError(json.Unmarshal(data, decoded))
+		assert.Equal(sketch.Count(), decoded.Count())
+		assert.Equal(sketch.Max(), decoded.Max())
+
+		if math.IsNaN(sketch.Sum()) {
+			assert.True(math.IsNaN(decoded.Sum()))
+		} else {
+			assert.Equal(sketch.Sum(), decoded.Sum())
+		}
+	}
+
+	var sketch = NewQuantileSketch(0.01)
+	sketch.Add(1e308, 1e308)
+
+	var data, _ = json.Marshal(sketch)
+	assert.Contains(string(data), `"sum":"+Inf"`)
+
+	assert.Error(json.Unmarshal([]byte(`{"accuracy":0.01,"sum":"lots"}`), sketch))
+}
+
+func TestQuantileSketchConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	var sketch = NewQuantileSketch(0.01)
+	var other = NewQuantileSketch(0.01)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 250; j++ {
+				sketch.Add(float64(j))
+				other.Add(float64(j))
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				sketch.Quantile(0.5)
+				sketch.Merge(NewQuantileSketch(0.01))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(uint64(1000), sketch.Count())
+	assert.Equal(uint64(1000), other.Count())
+}
@@ -0,0 +1,347 @@
+package mathutil
+
+import (
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/ghetzel/go-stockutil/typeutil"
+)
+
+// Specifies how Percentile estimates values that fall between two samples.
+type PercentileMethod int
+
+const (
+	PercentileLinear   PercentileMethod = iota // interpolate linearly between the two closest samples
+	PercentileLower                            // use the lower of the two closest samples
+	PercentileHigher                           // use the higher of the two closest samples
+	PercentileNearest                          // use the closer of the two closest samples (ties round to even)
+	PercentileMidpoint                         // use the average of the two closest samples
+)
+
+// A range of values and the number of samples that fell within it.
+type HistogramBucket struct {
+	Lower float64
+	Upper float64
+	Count int
+}
+
+// Converts each element of the given slice (or array) to a float64 using typeutil.Float, for use
+// with the functions in this package.  Scalar values are returned as a single-element slice.
+func Float64s(in interface{}) []float64 {
+	if values, ok := in.([]float64); ok {
+		return values
+	}
+
+	var inV = reflect.ValueOf(in)
+
+	switch inV.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Slice, reflect.Array:
+		var out = make([]float64, inV.Len())
+
+		for i := range out {
+			out[i] = typeutil.Float(inV.Index(i).Interface())
+		}
+
+		return out
+	default:
+		return []float64{typeutil.Float(in)}
+	}
+}
+
+// Returns the sum of the given values.
+func Sum(values []float64) float64 {
+	var sum float64
+
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum
+}
+
+// Returns the arithmetic mean of the given values, or NaN if there are none.
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	return Sum(values) / float64(len(values))
+}
+
+// Returns the median of the given values, or NaN if there are none.  For an even number of
+// values, this is the mean of the two middle values.
+func Median(values []float64) float64 {
+	return Percentile(values, 50, PercentileMidpoint)
+}
+
+// Returns the most frequently-occurring values, in ascending order.  If all values occur equally
+// often, all of them are returned.
+func Mode(values []float64) []float64 {
+	if len(values) == 0 {
+		return nil
+	}
+
+	var counts = make(map[float64]int)
+	var highest int
+
+	for _, v := range values {
+		counts[v]++
+
+		if counts[v] > highest {
+			highest = counts[v]
+		}
+	}
+
+	var modes = make([]float64, 0)
+
+	for v, count := range counts {
+		if count == highest {
+			modes = append(modes, v)
+		}
+	}
+
+	sort.Float64s(modes)
+
+	return modes
+}
+
+// Returns the population variance of the given values, or NaN if there are none.
+func Variance(values []float64) float64 {
+	return variance(values, 0)
+}
+
+// Returns the sample variance (with Bessel's correction) of the given values, or NaN if there
+// are fewer than two.
+func SampleVariance(values []float64) float64 {
+	return variance(values, 1)
+}
+
+// Returns the population standard deviation of the given values, or NaN if there are none.
+func StdDev(values []float64) float64 {
+	return math.Sqrt(Variance(values))
+}
+
+// Returns the sample standard deviation of the given values, or NaN if there are fewer than two.
+func SampleStdDev(values []float64) float64 {
+	return math.Sqrt(SampleVariance(values))
+}
+
+func variance(values []float64, ddof int) float64 {
+	if len(values)-ddof <= 0 {
+		return math.NaN()
+	}
+
+	var mean = Mean(values)
+	var sum float64
+
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+
+	return sum / float64(len(values)-ddof)
+}
+
+// Returns the pth percentile (0-100) of the given values, using the given method to estimate
+// values between samples.  Returns NaN if there are no values or p is out of range.  The input
+// slice is not modified.
+func Percentile(values []float64, p float64, method PercentileMethod) float64 {
+	if len(values) == 0 || p < 0 || p > 100 || math.IsNaN(p) {
+		return math.NaN()
+	}
+
+	var sorted = make([]float64, len(values))
+
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	return percentileSorted(sorted, p, method)
+}
+
+func percentileSorted(sorted []float64, p float64, method PercentileMethod) float64 {
+	var rank = p / 100 * float64(len(sorted)-1)
+	var lower = math.Floor(rank)
+	var upper = math.Ceil(rank)
+	var lo, hi = sorted[int(lower)], sorted[int(upper)]
+
+	switch method {
+	case PercentileLower:
+		return lo
+	case PercentileHigher:
+		return hi
+	case PercentileNearest:
+		return sorted[int(math.RoundToEven(rank))]
+	case PercentileMidpoint:
+		return (lo + hi) / 2
+	default:
+		return lo + (hi-lo)*(rank-lower)
+	}
+}
+
+// Returns the smallest of the given values, or NaN if there are none.
+func Min(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	var min = values[0]
+
+	for _, v := range values[1:] {
+		min = math.Min(min, v)
+	}
+
+	return min
+}
+
+// Returns the largest of the given values, or NaN if there are none.
+func Max(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+
+	var max = values[0]
+
+	for _, v := range values[1:] {
+		max = math.Max(max, v)
+	}
+
+	return max
+}
+
+// Returns the difference between the largest and smallest of the given values, or NaN if there
+// are none.
+func Range(values []float64) float64 {
+	return Max(values) - Min(values)
+}
+
+// Returns n+1 evenly-spaced bucket edges from min to max, for use with Histogram.
+func LinearBuckets(min float64, max float64, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+
+	var edges = make([]float64, n+1)
+	var width = (max - min) / float64(n)
+
+	for i := range edges {
+		edges[i] = min + width*float64(i)
+	}
+
+	edges[n] = max
+
+	return edges
+}
+
+// Returns n+1 bucket edges starting at start, with each edge factor times the previous one, for
+// use with Histogram.
+func ExponentialBuckets(start float64, factor float64, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+
+	var edges = make([]float64, n+1)
+
+	for i := range edges {
+		edges[i] = start * math.Pow(factor, float64(i))
+	}
+
+	return edges
+}
+
+// Counts the given values into buckets bounded by consecutive pairs of the given (ascending)
+// edges.  Each bucket includes its lower edge and excludes its upper edge, except for the last,
+// which includes both.  Values outside of the edges are not counted.
+func Histogram(values []float64, edges []float64) []HistogramBucket {
+	if len(edges) < 2 {
+		return nil
+	}
+
+	var buckets = make([]HistogramBucket, len(edges)-1)
+
+	for i := range buckets {
+		buckets[i].Lower = edges[i]
+		buckets[i].Upper = edges[i+1]
+	}
+
+	for _, v := range values {
+		if v < edges[0] || v > edges[len(edges)-1] || math.IsNaN(v) {
+			continue
+		}
+
+		// the first edge greater than v marks the end of its bucket
+		var i = sort.Search(len(edges), func(e int) bool {
+			return edges[e] > v
+		}) - 1
+
+		if i >= len(buckets) {
+			i = len(buckets) - 1
+		}
+
+		buckets[i].Count++
+	}
+
+	return buckets
+}
+
+// Returns the Pearson correlation coefficient of the given paired values, from -1 to 1.  Returns
+// NaN if the slices differ in length, have fewer than two values, or either has no variance.
+func Correlation(x []float64, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return math.NaN()
+	}
+
+	var mx, my = Mean(x), Mean(y)
+	var sxy, sxx, syy float64
+
+	for i := range x {
+		var dx, dy = x[i] - mx, y[i] - my
+
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// Fits a line to the given paired values using ordinary least squares, returning its slope and
+// intercept (such that y = slope*x + intercept) and the coefficient of determination (R²).
+// Returns NaNs if the slices differ in length, have fewer than two values, or x has no variance.
+func LinearRegression(x []float64, y []float64) (slope float64, intercept float64, r2 float64) {
+	if len(x) != len(y) || len(x) < 2 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	var mx, my = Mean(x), Mean(y)
+	var sxy, sxx, syy float64
+
+	for i := range x {
+		var dx, dy = x[i] - mx, y[i] - my
+
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+
+	if sxx == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+
+	slope = sxy / sxx
+	intercept = my - slope*mx
+
+	if syy == 0 {
+		// every y is the same, and the horizontal line fits perfectly
+		r2 = 1
+	} else {
+		r2 = (sxy * sxy) / (sxx * syy)
+	}
+
+	return slope, intercept, r2
+}
@@ -0,0 +1,115 @@
+package mathutil
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ghetzel/testify/assert"
+)
+
+func TestFloat64s(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]float64{1, 2.5, 3}, Float64s([]interface{}{1, `2.5`, int64(3)}))
+	assert.Equal([]float64{1, 2}, Float64s([2]int{1, 2}))
+	assert.Equal([]float64{4}, Float64s(4))
+	assert.Nil(Float64s(nil))
+}
+
+func TestCentralTendency(t *testing.T) {
+	assert := assert.New(t)
+	var values = []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	assert.Equal(float64(40), Sum(values))
+	assert.Equal(float64(5), Mean(values))
+	assert.Equal(4.5, Median(values))
+	assert.Equal(float64(3), Median([]float64{5, 1, 3}))
+	assert.Equal([]float64{4}, Mode(values))
+	assert.Equal([]float64{1, 2}, Mode([]float64{2, 1, 2, 1}))
+	assert.Nil(Mode(nil))
+
+	assert.True(math.IsNaN(Mean(nil)))
+	assert.True(math.IsNaN(Median(nil)))
+}
+
+func TestSpread(t *testing.T) {
+	assert := assert.New(t)
+	var values = []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	assert.Equal(float64(4), Variance(values))
+	assert.Equal(float64(2), StdDev(values))
+	assert.InDelta(32.0/7.0, SampleVariance(values), 1e-12)
+	assert.InDelta(math.Sqrt(32.0/7.0), SampleStdDev(values), 1e-12)
+	assert.True(math.IsNaN(SampleVariance([]float64{1})))
+	assert.Equal(float64(0), Variance([]float64{1}))
+
+	assert.Equal(float64(2), Min(values))
+	assert.Equal(float64(9), Max(values))
+	assert.Equal(float64(7), Range(values))
+	assert.True(math.IsNaN(Range(nil)))
+}
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+	var values = []float64{40, 10, 30, 20}
+
+	assert.Equal(float64(10), Percentile(values, 0, PercentileLinear))
+	assert.Equal(float64(40), Percentile(values, 100, PercentileLinear))
+	assert.Equal(float64(25), Percentile(values, 50, PercentileLinear))
+	assert.InDelta(17.5, Percentile(values, 25, PercentileLinear), 1e-12)
+	assert.Equal(float64(10), Percentile(values, 25, PercentileLower))
+	assert.Equal(float64(20), Percentile(values, 25, PercentileHigher))
+	assert.Equal(float64(20), Percentile(values, 25, PercentileNearest))
+	assert.Equal(float64(15), Percentile(values, 25, PercentileMidpoint))
+
+	// input is left unsorted
+	assert.Equal([]float64{40, 10, 30, 20}, values)
+
+	assert.True(math.IsNaN(Percentile(values, 101, PercentileLinear)))
+	assert.True(math.IsNaN(Percentile(nil, 50, PercentileLinear)))
+}
+
+func TestHistogram(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]float64{0, 2.5, 5, 7.5, 10}, LinearBuckets(0, 10, 4))
+	assert.Equal([]float64{1, 2, 4, 8}, ExponentialBuckets(1, 2, 3))
+	assert.Nil(LinearBuckets(0, 10, 0))
+
+	var buckets = Histogram([]float64{-1, 0, 1, 2.5, 4, 5, 9.9, 10, 11}, LinearBuckets(0, 10, 2))
+
+	assert.Equal([]HistogramBucket{
+		{Lower: 0, Upper: 5, Count: 4},
+		{Lower: 5, Upper: 10, Count: 3},
+	}, buckets)
+
+	assert.Nil(Histogram([]float64{1}, []float64{0}))
+}
+
+func TestCorrelation(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.InDelta(1, Correlation([]float64{1, 2, 3}, []float64{2, 4, 6}), 1e-12)
+	assert.InDelta(-1, Correlation([]float64{1, 2, 3}, []float64{3, 2, 1}), 1e-12)
+	assert.True(math.IsNaN(Correlation([]float64{1, 2}, []float64{1})))
+	assert.True(math.IsNaN(Correlation([]float64{1, 2}, []float64{3, 3})))
+}
+
+func TestLinearRegression(t *testing.T) {
+	assert := assert.New(t)
+
+	var slope, intercept, r2 = LinearRegression([]float64{1, 2, 3, 4}, []float64{3, 5, 7, 9})
+
+	assert.InDelta(2, slope, 1e-12)
+	assert.InDelta(1, intercept, 1e-12)
+	assert.InDelta(1, r2, 1e-12)
+
+	slope, intercept, r2 = LinearRegression([]float64{1, 2, 3}, []float64{1, 3, 2})
+
+	assert.InDelta(0.5, slope, 1e-12)
+	assert.InDelta(1, intercept, 1e-12)
+	assert.InDelta(0.25, r2, 1e-12)
+
+	slope, _, _ = LinearRegression([]float64{1, 1}, []float64{1, 2})
+	assert.True(math.IsNaN(slope))
+}
@@ -0,0 +1,332 @@
+package mathutil
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// An EWMA tracks an exponentially-weighted moving average of a stream of values, where each new
+// value contributes alpha (0 < alpha <= 1) of the result and older values decay geometrically.
+// EWMAs are safe for concurrent use.
+type EWMA struct {
+	alpha       float64
+	value       float64
+	initialized bool
+	lock        sync.RWMutex
+}
+
+// Returns a new EWMA with the given smoothing factor.  Values of alpha outside of (0, 1] are
+// clamped to that range.
+func NewEWMA(alpha float64) *EWMA {
+	return &EWMA{
+		alpha: Clamp(alpha, math.SmallestNonzeroFloat64, 1),
+	}
+}
+
+// Returns a new EWMA whose smoothing factor approximates a simple moving average over the last
+// n values (alpha = 2 / (n + 1)).
+func NewEWMAFromSpan(n int) *EWMA {
+	return NewEWMA(2 / (float64(n) + 1))
+}
+
+// Adds a value to the average.  The first value added becomes the initial average.
+func (self *EWMA) Add(value float64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if self.initialized {
+		self.value += self.alpha * (value - self.value)
+	} else {
+		self.value = value
+		self.initialized = true
+	}
+}
+
+// Returns the current average, or zero if no values have been added.
+func (self *EWMA) Value() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	return self.value
+}
+
+// Replaces the current average with the given value.
+func (self *EWMA) Set(value float64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.value = value
+	self.initialized = true
+}
+
+// Discards the current average.
+func (self *EWMA) Reset() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.value = 0
+	self.initialized = false
+}
+
+// The window used by NewRateCounter when given one that is zero or negative.
+var DefaultRateCounterWindow = time.Minute
+
+// A RateCounter counts events over a sliding window of time, such as "requests in the last
+// minute".  The window is divided into buckets of the given resolution, and events expire one
+// bucket at a time.  RateCounters are safe for concurrent use.
+type RateCounter struct {
+	window     time.Duration
+	resolution time.Duration
+	counts     []int64
+	slots      []int64
+	now        func() time.Time
+	lock       sync.Mutex
+}
+
+// Returns a new RateCounter covering the given window, tracked at the given resolution.  If
+// window is zero or negative, DefaultRateCounterWindow is used.  If resolution is zero or
+// negative, it defaults to one second (or the window, if shorter).  Windows that are not a
+// multiple of the resolution are rounded up to the next multiple (see Window).
+func NewRateCounter(window time.Duration, resolution time.Duration) *RateCounter {
+	if window <= 0 {
+		window = DefaultRateCounterWindow
+	}
+
+	if resolution <= 0 {
+		resolution = time.Second
+	}
+
+	if resolution > window {
+		resolution = window
+	}
+
+	var n = int((window + resolution - 1) / resolution)
+
+	return &RateCounter{
+		window:     time.Duration(n) * resolution,
+		resolution: resolution,
+		counts:     make([]int64, n),
+		slots:      make([]int64, n),
+		now:        time.Now,
+	}
+}
+
+// Records n events at the current time.
+func (self *RateCounter) Add(n int64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var slot = self.slot()
+	var i = int(slot % int64(len(self.counts)))
+
+	if self.slots[i] != slot {
+		self.slots[i] = slot
+		self.counts[i] = 0
+	}
+
+	self.counts[i] += n
+}
+
+// Records a single event at the current time.
+func (self *RateCounter) Incr() {
+	self.Add(1)
+}
+
+// Returns the number of events recorded within the window.
+func (self *RateCounter) Count() int64 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	var oldest = self.slot() - int64(len(self.counts))
+	var total int64
+
+	for i, slot := range self.slots {
+		if slot > oldest {
+			total += self.counts[i]
+		}
+	}
+
+	return total
+}
+
+// Returns the average number of events per second within the window.
+func (self *RateCounter) Rate() float64 {
+	return float64(self.Count()) / self.window.Seconds()
+}
+
+// Returns the span of time the counter covers, which is the requested window rounded up to a
+// multiple of the resolution.
+func (self *RateCounter) Window() time.Duration {
+	return self.window
+}
+
+// Discards all recorded events.
+func (self *RateCounter) Reset() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	for i := range self.counts {
+		self.counts[i] = 0
+		self.slots[i] = 0
+	}
+}
+
+func (self *RateCounter) slot() int64 {
+	// slots are numbered from 1 so that unused buckets (slot 0) are never counted
+	return self.now().UnixNano()/int64(self.resolution) + 1
+}
+
+// RunningStats computes the count, mean, variance, minimum and maximum of a stream of values in
+// constant space using Welford's algorithm.  The zero value is ready to use, and RunningStats are
+// safe for concurrent use.
+type RunningStats struct {
+	count int64
+	mean  float64
+	m2    float64
+	min   float64
+	max   float64
+	lock  sync.RWMutex
+}
+
+// Returns a new, empty RunningStats.
+func NewRunningStats() *RunningStats {
+	return new(RunningStats)
+}
+
+// Adds the given values to the statistics.
+func (self *RunningStats) Add(values ...float64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	for _, value := range values {
+		if self.count == 0 {
+			self.min, self.max = value, value
+		} else {
+			self.min = math.Min(self.min, value)
+			self.max = math.Max(self.max, value)
+		}
+
+		self.count++
+
+		var delta = value - self.mean
+
+		self.mean += delta / float64(self.count)
+		self.m2 += delta * (value - self.mean)
+	}
+}
+
+// Combines the statistics from other into these, as if all of its values had been added here.
+func (self *RunningStats) Merge(other *RunningStats) {
+	other.lock.RLock()
+	var count, mean, m2, min, max = other.count, other.mean, other.m2, other.min, other.max
+	other.lock.RUnlock()
+
+	if count == 0 {
+		return
+	}
+
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if self.count == 0 {
+		self.count, self.mean, self.m2, self.min, self.max = count, mean, m2, min, max
+		return
+	}
+
+	// Chan et al.'s method for combining the moments of two partitions
+	var total = self.count + count
+	var delta = mean - self.mean
+
+	self.mean += delta * float64(count) / float64(total)
+	self.m2 += m2 + delta*delta*float64(self.count)*float64(count)/float64(total)
+	self.count = total
+	self.min = math.Min(self.min, min)
+	self.max = math.Max(self.max, max)
+}
+
+// Returns the number of values added.
+func (self *RunningStats) Count() int64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	return self.count
+}
+
+// Returns the mean of the values added, or NaN if there are none.
+func (self *RunningStats) Mean() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count == 0 {
+		return math.NaN()
+	}
+
+	return self.mean
+}
+
+// Returns the population variance of the values added, or NaN if there are none.
+func (self *RunningStats) Variance() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count == 0 {
+		return math.NaN()
+	}
+
+	return self.m2 / float64(self.count)
+}
+
+// Returns the sample variance of the values added, or NaN if there are fewer than two.
+func (self *RunningStats) SampleVariance() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count < 2 {
+		return math.NaN()
+	}
+
+	return self.m2 / float64(self.count-1)
+}
+
+// Returns the population standard deviation of the values added, or NaN if there are none.
+func (self *RunningStats) StdDev() float64 {
+	return math.Sqrt(self.Variance())
+}
+
+// Returns the sample standard deviation of the values added, or NaN if there are fewer than two.
+func (self *RunningStats) SampleStdDev() float64 {
+	return math.Sqrt(self.SampleVariance())
+}
+
+// Returns the smallest value added, or NaN if there are none.
+func (self *RunningStats) Min() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count == 0 {
+		return math.NaN()
+	}
+
+	return self.min
+}
+
+// Returns the largest value added, or NaN if there are none.
+func (self *RunningStats) Max() float64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	if self.count == 0 {
+		return math.NaN()
+	}
+
+	return self.max
+}
+
+// Discards all values added.
+func (self *RunningStats) Reset() {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.count, self.mean, self.m2, self.min, self.max = 0, 0, 0, 0, 0
+}
@@ -0,0 +1,162 @@
+package mathutil
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ghetzel/testify/assert"
+)
+
+func TestEWMA(t *testing.T) {
+	assert := assert.New(t)
+	var avg = NewEWMA(0.5)
+
+	assert.Equal(float64(0), avg.Value())
+
+	avg.Add(10)
+	assert.Equal(float64(10), avg.Value())
+
+	avg.Add(20)
+	assert.Equal(float64(15), avg.Value())
+
+	avg.Add(20)
+	assert.Equal(17.5, avg.Value())
+
+	avg.Set(4)
+	assert.Equal(float64(4), avg.Value())
+
+	avg.Reset()
+	avg.Add(8)
+	assert.Equal(float64(8), avg.Value())
+
+	assert.InDelta(2.0/11.0, NewEWMAFromSpan(10).alpha, 1e-12)
+	assert.Equal(float64(1), NewEWMA(5).alpha)
+}
+
+func TestRateCounter(t *testing.T) {
+	assert := assert.New(t)
+	var now = time.Unix(1000, 0)
+	var counter = NewRateCounter(10*time.Second, time.Second)
+
+	counter.now = func() time.Time {
+		return now
+	}
+
+	counter.Add(5)
+	now = now.Add(3 * time.Second)
+	counter.Incr()
+	counter.Incr()
+
+	assert.Equal(int64(7), counter.Count())
+	assert.Equal(0.7, counter.Rate())
+
+	// the first five events expire once ten seconds have passed since they were recorded
+	now = now.Add(6 * time.Second)
+	assert.Equal(int64(7), counter.Count())
+
+	now = now.Add(time.Second)
+	assert.Equal(int64(2), counter.Count())
+
+	// reusing a bucket discards its old count
+	now = now.Add(10 * time.Second)
+	counter.Add(1)
+	assert.Equal(int64(1), counter.Count())
+
+	counter.Reset()
+	assert.Equal(int64(0), counter.Count())
+}
+
+func TestRateCounterWindows(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, window := range []time.Duration{0, -time.Second} {
+		var counter = NewRateCounter(window, 0)
+
+		assert.Equal(DefaultRateCounterWindow, counter.Window())
+		counter.Incr()
+		assert.Equal(int64(1), counter.Count())
+		assert.InDelta(1/DefaultRateCounterWindow.Seconds(), counter.Rate(), 1e-12)
+	}
+
+	// windows shorter than the resolution use the window as the resolution
+	var short = NewRateCounter(500*time.Millisecond, -1)
+	assert.Equal(500*time.Millisecond, short.Window())
+	short.Incr()
+	assert.Equal(float64(2), short.Rate())
+
+	// the window is rounded up to a multiple of the resolution, and the rate reflects it
+	var now = time.Unix(1000, 0)
+	var uneven = NewRateCounter(2500*time.Millisecond, time.Second)
+
+	uneven.now = func() time.Time {
+		return now
+	}
+
+	assert.Equal(3*time.Second, uneven.Window())
+
+	uneven.Add(6)
+	assert.Equal(float64(2), uneven.Rate())
+
+	now = now.Add(3 * time.Second)
+	assert.Equal(int64(0), uneven.Count())
+}
+
+func TestRunningStats(t *testing.T) {
+	assert := assert.New(t)
+	var values = []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	var stats RunningStats
+
+	assert.True(math.IsNaN(stats.Mean()))
+	assert.True(math.IsNaN(stats.Min()))
+
+	stats.Add(values...)
+
+	assert.Equal(int64(8), stats.Count())
+	assert.InDelta(Mean(values), stats.Mean(), 1e-12)
+	assert.InDelta(Variance(values), stats.Variance(), 1e-12)
+	assert.InDelta(SampleVariance(values), stats.SampleVariance(), 1e-12)
+	assert.InDelta(StdDev(values), stats.StdDev(), 1e-12)
+	assert.Equal(float64(2), stats.Min())
+	assert.Equal(float64(9), stats.Max())
+
+	var a, b = NewRunningStats(), NewRunningStats()
+
+	a.Add(values[:3]...)
+	b.Add(values[3:]...)
+	a.Merge(b)
+	a.Merge(NewRunningStats())
+
+	assert.Equal(stats.Count(), a.Count())
+	assert.InDelta(stats.Mean(), a.Mean(), 1e-12)
+	assert.InDelta(stats.Variance(), a.Variance(), 1e-12)
+	assert.Equal(float64(2), a.Min())
+	assert.Equal(float64(9), a.Max())
+
+	stats.Reset()
+	assert.Equal(int64(0), stats.Count())
+}
+
+func TestRunningStatsConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	var stats = NewRunningStats()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 1; j <= 100; j++ {
+				stats.Add(float64(j))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(int64(800), stats.Count())
+	assert.InDelta(50.5, stats.Mean(), 1e-9)
+}
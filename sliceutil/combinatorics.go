@@ -0,0 +1,421 @@
+package sliceutil
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Returns every run of size consecutive elements in the given slice, starting every step
+// elements (e.g. a size of 3 and step of 1 over [1 2 3 4] yields [1 2 3] and [2 3 4]).  Windows
+// that would extend past the end of the slice are omitted.  The windows share the underlying
+// array of the input slice.
+func Windows[T any](in []T, size int, step int) [][]T {
+	if size <= 0 || step <= 0 {
+		return make([][]T, 0)
+	}
+
+	var out = make([][]T, 0)
+
+	for i := 0; i+size <= len(in); i += step {
+		out = append(out, in[i:i+size:i+size])
+	}
+
+	return out
+}
+
+// Combines the given slices element-wise, returning a slice of tuples where the nth tuple holds
+// the nth element of each input.  The result is as long as the shortest input.
+func Zip[T any](slices ...[]T) [][]T {
+	if len(slices) == 0 {
+		return make([][]T, 0)
+	}
+
+	var n = len(slices[0])
+
+	for _, slice := range slices[1:] {
+		if len(slice) < n {
+			n = len(slice)
+		}
+	}
+
+	var out = make([][]T, n)
+
+	for i := range out {
+		out[i] = make([]T, len(slices))
+
+		for s, slice := range slices {
+			out[i][s] = slice[i]
+		}
+	}
+
+	return out
+}
+
+// The inverse of Zip: splits a slice of tuples into one slice per tuple position.  The number of
+// slices returned is the length of the shortest tuple.
+func Unzip[T any](tuples [][]T) [][]T {
+	if len(tuples) == 0 {
+		return make([][]T, 0)
+	}
+
+	var width = len(tuples[0])
+
+	for _, tuple := range tuples[1:] {
+		if len(tuple) < width {
+			width = len(tuple)
+		}
+	}
+
+	var out = make([][]T, width)
+
+	for s := range out {
+		out[s] = make([]T, len(tuples))
+
+		for i, tuple := range tuples {
+			out[s][i] = tuple[s]
+		}
+	}
+
+	return out
+}
+
+// Returns the elements of the given slices in round-robin order (the first element of each
+// slice, then the second of each, and so on).  Once a slice is exhausted, it is skipped.
+func Interleave[T any](slices ...[]T) []T {
+	var total, longest int
+
+	for _, slice := range slices {
+		total += len(slice)
+
+		if len(slice) > longest {
+			longest = len(slice)
+		}
+	}
+
+	var out = make([]T, 0, total)
+
+	for i := 0; i < longest; i++ {
+		for _, slice := range slices {
+			if i < len(slice) {
+				out = append(out, slice[i])
+			}
+		}
+	}
+
+	return out
+}
+
+// Returns a copy of the given slice with its elements shifted n positions toward the end, with
+// elements that fall off the end wrapping around to the start.  Negative values of n shift
+// elements toward the start.
+func Rotate[T any](in []T, n int) []T {
+	var out = make([]T, len(in))
+
+	if len(in) == 0 {
+		return out
+	}
+
+	n = ((n % len(in)) + len(in)) % len(in)
+
+	copy(out[n:], in)
+	copy(out, in[len(in)-n:])
+
+	return out
+}
+
+// Returns a shuffled copy of the given slice.  If rng is nil, the default source from the
+// math/rand package is used.
+func Shuffle[T any](in []T, rng *rand.Rand) []T {
+	var out = make([]T, len(in))
+	var swap = func(i int, j int) {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	copy(out, in)
+
+	if rng != nil {
+		rng.Shuffle(len(out), swap)
+	} else {
+		rand.Shuffle(len(out), swap)
+	}
+
+	return out
+}
+
+// Returns k elements chosen at random (without replacement) from the given slice, in random
+// order.  If k exceeds the length of the slice, all elements are returned.  If rng is nil, the
+// default source from the math/rand package is used.
+func Sample[T any](in []T, k int, rng *rand.Rand) []T {
+	var out = Shuffle(in, rng)
+
+	if k < 0 {
+		k = 0
+	}
+
+	if k < len(out) {
+		out = out[:k]
+	}
+
+	return out
+}
+
+// Returns k elements chosen at random (without replacement) from the given slice, where each
+// element's chance of being chosen is proportional to its weight.  Elements are returned in the
+// order they were chosen.  Elements with a weight of zero are never chosen, so fewer than k
+// elements may be returned.  If rng is nil, the default source from the math/rand package is
+// used.
+func WeightedSample[T any](in []T, weights []float64, k int, rng *rand.Rand) ([]T, error) {
+	if len(weights) != len(in) {
+		return nil, fmt.Errorf("expected %d weights, got %d", len(in), len(weights))
+	}
+
+	var float = rand.Float64
+
+	if rng != nil {
+		float = rng.Float64
+	}
+
+	type keyed struct {
+		index int
+		key   float64
+	}
+
+	// Efraimidis-Spirakis: choosing the k largest values of u^(1/w) is equivalent to drawing k
+	// items one at a time in proportion to their weight.
+	var candidates = make([]keyed, 0, len(in))
+
+	for i, weight := range weights {
+		if weight < 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("invalid weight %v at index %d", weight, i)
+		} else if weight == 0 {
+			continue
+		}
+
+		candidates = append(candidates, keyed{
+			index: i,
+			key:   math.Log(1-float()) / weight,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i int, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+
+	if k < 0 {
+		k = 0
+	}
+
+	if k < len(candidates) {
+		candidates = candidates[:k]
+	}
+
+	var out = make([]T, len(candidates))
+
+	for i, candidate := range candidates {
+		out[i] = in[candidate.index]
+	}
+
+	return out, nil
+}
+
+// An Iterator lazily produces a sequence of slices, such as those returned by Permutations,
+// Combinations, and CartesianProduct.  Call Next to advance to each value, then Value to
+// retrieve it:
+//
+//	for it := sliceutil.Permutations(items, 2); it.Next(); {
+//		fmt.Println(it.Value())
+//	}
+type Iterator[T any] struct {
+	advance func() bool
+	current func() []T
+	started bool
+	done    bool
+}
+
+// Advances the iterator to the next value, returning false once there are no more values.
+func (self *Iterator[T]) Next() bool {
+	if self.done {
+		return false
+	}
+
+	if !self.started {
+		self.started = true
+	} else if !self.advance() {
+		self.done = true
+		return false
+	}
+
+	return true
+}
+
+// Returns the current value.  The returned slice is newly allocated and safe to retain.  Nil is
+// returned if Next has not been called, or if the iterator is exhausted.
+func (self *Iterator[T]) Value() []T {
+	if self.current == nil || !self.started || self.done {
+		return nil
+	}
+
+	return self.current()
+}
+
+// Returns all remaining values as a slice.
+func (self *Iterator[T]) Collect() [][]T {
+	var out = make([][]T, 0)
+
+	for self.Next() {
+		out = append(out, self.Value())
+	}
+
+	return out
+}
+
+func emptyIterator[T any]() *Iterator[T] {
+	return &Iterator[T]{
+		done: true,
+	}
+}
+
+// Returns an Iterator over every ordered arrangement of k elements from the given slice, in
+// lexicographic order of their positions.  If k is negative, it defaults to the length of the
+// slice.  Elements are treated as distinct based on their position, not their value.
+func Permutations[T any](in []T, k int) *Iterator[T] {
+	var n = len(in)
+
+	if k < 0 {
+		k = n
+	}
+
+	if k > n {
+		return emptyIterator[T]()
+	}
+
+	// the algorithm used by Python's itertools.permutations
+	var indices = make([]int, n)
+	var cycles = make([]int, k)
+
+	for i := range indices {
+		indices[i] = i
+	}
+
+	for i := range cycles {
+		cycles[i] = n - i
+	}
+
+	return &Iterator[T]{
+		current: func() []T {
+			var out = make([]T, k)
+
+			for i := range out {
+				out[i] = in[indices[i]]
+			}
+
+			return out
+		},
+		advance: func() bool {
+			for i := k - 1; i >= 0; i-- {
+				cycles[i]--
+
+				if cycles[i] == 0 {
+					// move indices[i] to the end
+					var moved = indices[i]
+
+					copy(indices[i:], indices[i+1:])
+					indices[n-1] = moved
+					cycles[i] = n - i
+				} else {
+					var j = n - cycles[i]
+
+					indices[i], indices[j] = indices[j], indices[i]
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// Returns an Iterator over every selection of k elements from the given slice, preserving their
+// original order, in lexicographic order of their positions.
+func Combinations[T any](in []T, k int) *Iterator[T] {
+	var n = len(in)
+
+	if k < 0 || k > n {
+		return emptyIterator[T]()
+	}
+
+	var indices = make([]int, k)
+
+	for i := range indices {
+		indices[i] = i
+	}
+
+	return &Iterator[T]{
+		current: func() []T {
+			var out = make([]T, k)
+
+			for i, index := range indices {
+				out[i] = in[index]
+			}
+
+			return out
+		},
+		advance: func() bool {
+			// find the rightmost index that can still be incremented
+			var i = k - 1
+
+			for i >= 0 && indices[i] == i+n-k {
+				i--
+			}
+
+			if i < 0 {
+				return false
+			}
+
+			indices[i]++
+
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[j-1] + 1
+			}
+
+			return true
+		},
+	}
+}
+
+// Returns an Iterator over every tuple that takes one element from each of the given slices, with
+// the last slice varying fastest.  If any slice is empty, there are no tuples.
+func CartesianProduct[T any](slices ...[]T) *Iterator[T] {
+	for _, slice := range slices {
+		if len(slice) == 0 {
+			return emptyIterator[T]()
+		}
+	}
+
+	var positions = make([]int, len(slices))
+
+	return &Iterator[T]{
+		current: func() []T {
+			var out = make([]T, len(slices))
+
+			for s, position := range positions {
+				out[s] = slices[s][position]
+			}
+
+			return out
+		},
+		advance: func() bool {
+			for s := len(slices) - 1; s >= 0; s-- {
+				if positions[s]++; positions[s] < len(slices[s]) {
+					return true
+				}
+
+				positions[s] = 0
+			}
+
+			return false
+		},
+	}
+}
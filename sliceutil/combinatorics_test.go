@@ -0,0 +1,171 @@
+package sliceutil
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestWindows(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}, Windows([]int{1, 2, 3, 4, 5}, 3, 1))
+	assert.Equal([][]int{{1, 2}, {3, 4}}, Windows([]int{1, 2, 3, 4, 5}, 2, 2))
+	assert.Equal([][]int{}, Windows([]int{1, 2}, 3, 1))
+	assert.Equal([][]int{}, Windows([]int{1, 2}, 0, 1))
+}
+
+func TestZipUnzipInterleave(t *testing.T) {
+	assert := require.New(t)
+
+	var zipped = Zip([]string{`a`, `b`, `c`}, []string{`1`, `2`}, []string{`x`, `y`, `z`})
+
+	assert.Equal([][]string{{`a`, `1`, `x`}, {`b`, `2`, `y`}}, zipped)
+	assert.Equal([][]string{{`a`, `b`}, {`1`, `2`}, {`x`, `y`}}, Unzip(zipped))
+	assert.Equal([][]int{}, Zip[int]())
+	assert.Equal([][]int{}, Unzip[int](nil))
+
+	assert.Equal([]int{1, 10, 100, 2, 20, 3, 4}, Interleave([]int{1, 2, 3, 4}, []int{10, 20}, []int{100}))
+	assert.Equal([]int{}, Interleave[int]())
+}
+
+func TestRotate(t *testing.T) {
+	assert := require.New(t)
+
+	var in = []int{1, 2, 3, 4, 5}
+
+	assert.Equal([]int{4, 5, 1, 2, 3}, Rotate(in, 2))
+	assert.Equal([]int{3, 4, 5, 1, 2}, Rotate(in, -2))
+	assert.Equal([]int{1, 2, 3, 4, 5}, Rotate(in, 5))
+	assert.Equal([]int{5, 1, 2, 3, 4}, Rotate(in, 11))
+	assert.Equal([]int{1, 2, 3, 4, 5}, in)
+	assert.Equal([]int{}, Rotate([]int{}, 3))
+}
+
+func TestShuffleSample(t *testing.T) {
+	assert := require.New(t)
+
+	var in = []int{1, 2, 3, 4, 5, 6, 7, 8}
+	var shuffled = Shuffle(in, rand.New(rand.NewSource(1)))
+
+	assert.Equal(shuffled, Shuffle(in, rand.New(rand.NewSource(1))))
+	assert.NotEqual(in, shuffled)
+	assert.Equal([]int{1, 2, 3, 4, 5, 6, 7, 8}, in)
+
+	var sorted = append([]int(nil), shuffled...)
+	sort.Ints(sorted)
+	assert.Equal(in, sorted)
+
+	var sample = Sample(in, 3, rand.New(rand.NewSource(2)))
+	assert.Len(sample, 3)
+	assert.Len(UniqueOf(sample), 3)
+	assert.Len(Sample(in, 20, nil), 8)
+	assert.Empty(Sample(in, -1, nil))
+}
+
+func TestWeightedSample(t *testing.T) {
+	assert := require.New(t)
+
+	var rng = rand.New(rand.NewSource(42))
+	var counts = make(map[string]int)
+
+	for i := 0; i < 10000; i++ {
+		out, err := WeightedSample([]string{`rare`, `common`, `never`}, []float64{1, 9, 0}, 1, rng)
+		assert.NoError(err)
+		assert.Len(out, 1)
+		counts[out[0]]++
+	}
+
+	assert.Zero(counts[`never`])
+	assert.InDelta(9000, counts[`common`], 300)
+
+	out, err := WeightedSample([]int{1, 2, 3}, []float64{1, 1, 0}, 5, rng)
+	assert.NoError(err)
+	assert.ElementsMatch([]int{1, 2}, out)
+
+	_, err = WeightedSample([]int{1, 2}, []float64{1}, 1, nil)
+	assert.Error(err)
+
+	_, err = WeightedSample([]int{1, 2}, []float64{1, -1}, 1, nil)
+	assert.Error(err)
+}
+
+func TestPermutations(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([][]int{
+		{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1},
+	}, Permutations([]int{1, 2, 3}, -1).Collect())
+
+	assert.Equal([][]string{
+		{`a`, `b`}, {`a`, `c`}, {`b`, `a`}, {`b`, `c`}, {`c`, `a`}, {`c`, `b`},
+	}, Permutations([]string{`a`, `b`, `c`}, 2).Collect())
+
+	assert.Equal([][]int{{}}, Permutations([]int{1, 2}, 0).Collect())
+	assert.Empty(Permutations([]int{1, 2}, 3).Collect())
+
+	// lazy: only the values we ask for are produced
+	var it = Permutations(make([]int, 20), -1)
+	var count int
+
+	for it.Next() {
+		if count++; count == 5 {
+			break
+		}
+	}
+
+	assert.Equal(5, count)
+}
+
+func TestCombinations(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([][]string{
+		{`a`, `b`}, {`a`, `c`}, {`a`, `d`}, {`b`, `c`}, {`b`, `d`}, {`c`, `d`},
+	}, Combinations([]string{`a`, `b`, `c`, `d`}, 2).Collect())
+
+	assert.Equal([][]int{{1, 2, 3}}, Combinations([]int{1, 2, 3}, 3).Collect())
+	assert.Equal([][]int{{}}, Combinations([]int{1, 2, 3}, 0).Collect())
+	assert.Empty(Combinations([]int{1, 2, 3}, 4).Collect())
+	assert.Len(Combinations(make([]int, 10), 3).Collect(), 120)
+
+	// values are safe to retain
+	var it = Combinations([]int{1, 2, 3}, 1)
+	it.Next()
+	var first = it.Value()
+	it.Next()
+	assert.Equal([]int{1}, first)
+	assert.Equal([]int{2}, it.Value())
+}
+
+func TestCartesianProduct(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([][]string{
+		{`a`, `x`}, {`a`, `y`}, {`b`, `x`}, {`b`, `y`}, {`c`, `x`}, {`c`, `y`},
+	}, CartesianProduct([]string{`a`, `b`, `c`}, []string{`x`, `y`}).Collect())
+
+	assert.Empty(CartesianProduct([]int{1}, []int{}).Collect())
+	assert.Equal([][]int{{}}, CartesianProduct[int]().Collect())
+}
+
+func TestIteratorValueOutOfRange(t *testing.T) {
+	assert := require.New(t)
+
+	// empty iterators
+	var empty = Permutations([]int{1, 2}, 3)
+	assert.Nil(empty.Value())
+	assert.False(empty.Next())
+	assert.Nil(empty.Value())
+	assert.Nil(CartesianProduct([]int{1}, []int{}).Value())
+
+	// before the first call to Next, and once exhausted
+	var it = Combinations([]int{1, 2}, 2)
+	assert.Nil(it.Value())
+	assert.True(it.Next())
+	assert.Equal([]int{1, 2}, it.Value())
+	assert.False(it.Next())
+	assert.Nil(it.Value())
+}
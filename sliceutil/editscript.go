@@ -0,0 +1,208 @@
+package sliceutil
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Represents what happened to an item between two versions of a slice.
+type EditOperation int
+
+const (
+	EditKeep   EditOperation = iota // the item is in both slices, in the same relative order
+	EditInsert                      // the item is only in the new slice
+	EditDelete                      // the item is only in the old slice
+	EditMove                        // the item is in both slices, but its relative order changed
+)
+
+func (self EditOperation) String() string {
+	switch self {
+	case EditKeep:
+		return `keep`
+	case EditInsert:
+		return `insert`
+	case EditDelete:
+		return `delete`
+	case EditMove:
+		return `move`
+	default:
+		return ``
+	}
+}
+
+// A single step in an edit script produced by EditScript.
+type Edit[T any] struct {
+	Operation EditOperation
+
+	// The item from the old slice and its index there (or the zero value and -1 for insertions).
+	Old      T
+	OldIndex int
+
+	// The item from the new slice and its index there (or the zero value and -1 for deletions).
+	New      T
+	NewIndex int
+
+	// For kept and moved items, whether the old and new items differ according to the equality
+	// function given to EditScript.
+	Modified bool
+}
+
+// Compares two slices and returns the edit script that transforms before into after.  Items are
+// identified by the value returned from key; if a key appears more than once, its occurrences are
+// paired up in order.  The items that keep their relative order are found using the longest
+// common subsequence of keys, and all other items present in both slices are reported as moves.
+//
+// Items present in both slices are compared with equal to detect modifications; if equal is nil,
+// reflect.DeepEqual is used.
+//
+// The script lists every item exactly once, in the order of after.  Deletions are placed just
+// before the next kept item that followed them in before, or at the end.
+func EditScript[T any, K comparable](before []T, after []T, key func(value T) K, equal func(a T, b T) bool) []Edit[T] {
+	if equal == nil {
+		equal = func(a T, b T) bool {
+			return reflect.DeepEqual(a, b)
+		}
+	}
+
+	type occurrence struct {
+		key K
+		n   int
+	}
+
+	// pair up items by key (and by occurrence, for repeated keys)
+	var oldPositions = make(map[occurrence]int, len(before))
+	var seen = make(map[K]int, len(before))
+
+	for i, item := range before {
+		var k = key(item)
+
+		oldPositions[occurrence{k, seen[k]}] = i
+		seen[k]++
+	}
+
+	var matchOld = make([]int, len(after))  // for each new item, its old index (or -1)
+	var matchNew = make([]int, len(before)) // for each old item, its new index (or -1)
+
+	for i := range matchNew {
+		matchNew[i] = -1
+	}
+
+	seen = make(map[K]int, len(after))
+
+	for j, item := range after {
+		var k = key(item)
+
+		if i, ok := oldPositions[occurrence{k, seen[k]}]; ok {
+			matchOld[j] = i
+			matchNew[i] = j
+		} else {
+			matchOld[j] = -1
+		}
+
+		seen[k]++
+	}
+
+	// since each pairing is unique, the longest common subsequence of the paired items is the
+	// longest increasing subsequence of their old indices, taken in new order.
+	var kept = make([]bool, len(after))
+
+	for _, j := range longestIncreasing(matchOld) {
+		kept[j] = true
+	}
+
+	var script = make([]Edit[T], 0, len(before)+len(after))
+	var i int
+
+	var emitDeletes = func(until int) {
+		for ; i < until; i++ {
+			if matchNew[i] < 0 {
+				script = append(script, Edit[T]{
+					Operation: EditDelete,
+					Old:       before[i],
+					OldIndex:  i,
+					NewIndex:  -1,
+				})
+			}
+		}
+	}
+
+	for j, item := range after {
+		var o = matchOld[j]
+
+		switch {
+		case o < 0:
+			script = append(script, Edit[T]{
+				Operation: EditInsert,
+				OldIndex:  -1,
+				New:       item,
+				NewIndex:  j,
+			})
+
+		case kept[j]:
+			emitDeletes(o)
+			i = o + 1
+
+			script = append(script, Edit[T]{
+				Operation: EditKeep,
+				Old:       before[o],
+				OldIndex:  o,
+				New:       item,
+				NewIndex:  j,
+				Modified:  !equal(before[o], item),
+			})
+
+		default:
+			script = append(script, Edit[T]{
+				Operation: EditMove,
+				Old:       before[o],
+				OldIndex:  o,
+				New:       item,
+				NewIndex:  j,
+				Modified:  !equal(before[o], item),
+			})
+		}
+	}
+
+	emitDeletes(len(before))
+
+	return script
+}
+
+// returns the positions of a longest strictly increasing subsequence of the non-negative values
+// in the given slice (negative values are ignored).
+func longestIncreasing(values []int) []int {
+	var tails []int                         // tails[l] is the position ending the best subsequence of length l+1
+	var previous = make([]int, len(values)) // the position preceding each position in its subsequence
+
+	for p, value := range values {
+		if value < 0 {
+			continue
+		}
+
+		var l = sort.Search(len(tails), func(t int) bool {
+			return values[tails[t]] >= value
+		})
+
+		if l > 0 {
+			previous[p] = tails[l-1]
+		} else {
+			previous[p] = -1
+		}
+
+		if l == len(tails) {
+			tails = append(tails, p)
+		} else {
+			tails[l] = p
+		}
+	}
+
+	var out = make([]int, len(tails))
+
+	if len(tails) > 0 {
+		for l, p := len(tails)-1, tails[len(tails)-1]; l >= 0; l, p = l-1, previous[p] {
+			out[l] = p
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,113 @@
+package sliceutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+type editTestRecord struct {
+	Name  string
+	Value string
+}
+
+func summarizeScript[T any](script []Edit[T], name func(T) string) string {
+	var parts []string
+
+	for _, edit := range script {
+		var part = edit.Operation.String() + `:`
+
+		switch edit.Operation {
+		case EditDelete:
+			part += name(edit.Old)
+		default:
+			part += name(edit.New)
+		}
+
+		if edit.Modified {
+			part += `*`
+		}
+
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, ` `)
+}
+
+func identity(s string) string {
+	return s
+}
+
+func TestEditScript(t *testing.T) {
+	assert := require.New(t)
+
+	var script = EditScript([]string{`a`, `b`, `c`, `d`, `e`}, []string{`a`, `c`, `x`, `e`, `b`}, identity, nil)
+
+	assert.Equal(`keep:a keep:c insert:x delete:d keep:e move:b`, summarizeScript(script, identity))
+
+	assert.Equal(Edit[string]{
+		Operation: EditMove,
+		Old:       `b`,
+		OldIndex:  1,
+		New:       `b`,
+		NewIndex:  4,
+	}, script[5])
+
+	assert.Equal(-1, script[2].OldIndex)
+	assert.Equal(-1, script[3].NewIndex)
+
+	assert.Equal(``, summarizeScript(EditScript([]string{}, []string{}, identity, nil), identity))
+	assert.Equal(`insert:a insert:b`, summarizeScript(EditScript(nil, []string{`a`, `b`}, identity, nil), identity))
+	assert.Equal(`delete:a delete:b`, summarizeScript(EditScript([]string{`a`, `b`}, nil, identity, nil), identity))
+	assert.Equal(`move:c keep:a keep:b`, summarizeScript(EditScript([]string{`a`, `b`, `c`}, []string{`c`, `a`, `b`}, identity, nil), identity))
+}
+
+func TestEditScriptDuplicateKeys(t *testing.T) {
+	assert := require.New(t)
+
+	var script = EditScript([]string{`a`, `b`, `a`}, []string{`a`, `a`, `b`, `a`}, identity, nil)
+
+	assert.Equal(`keep:a move:a keep:b insert:a`, summarizeScript(script, identity))
+}
+
+func TestEditScriptModifications(t *testing.T) {
+	assert := require.New(t)
+
+	var before = []editTestRecord{
+		{`www`, `1.2.3.4`},
+		{`mail`, `1.2.3.5`},
+		{`ftp`, `1.2.3.6`},
+	}
+
+	var after = []editTestRecord{
+		{`mail`, `1.2.3.5`},
+		{`www`, `5.6.7.8`},
+		{`api`, `1.2.3.7`},
+	}
+
+	var name = func(r editTestRecord) string {
+		return r.Name
+	}
+
+	var script = EditScript(before, after, name, nil)
+
+	assert.Equal(`move:mail keep:www* insert:api delete:ftp`, summarizeScript(script, name))
+	assert.Equal(`1.2.3.4`, script[1].Old.Value)
+	assert.Equal(`5.6.7.8`, script[1].New.Value)
+
+	// a custom equality function
+	script = EditScript(before, after, name, func(a editTestRecord, b editTestRecord) bool {
+		return true
+	})
+
+	assert.Equal(`move:mail keep:www insert:api delete:ftp`, summarizeScript(script, name))
+}
+
+func TestLongestIncreasing(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([]int{0, 2, 4, 5}, longestIncreasing([]int{1, 5, 2, -1, 3, 9}))
+	assert.Equal([]int{}, longestIncreasing([]int{-1, -1}))
+	assert.Equal([]int{2}, longestIncreasing([]int{3, 2, 1}))
+}
@@ -0,0 +1,215 @@
+package sliceutil
+
+// The functions in this file are type-safe equivalents of the reflection-based functions in this
+// package.  Where a name was already taken by an existing function, the generic version carries
+// an "Of" suffix (e.g. MapOf is the generic version of Map).
+//
+// Functions returning slices (including those in combinatorics.go) return an empty slice rather
+// than nil when there is nothing to return, so results marshal to JSON as [] rather than null.
+// The exceptions are error returns and Iterator.Value, which return nil.
+
+// Returns a new slice containing the result of calling fn on each element of the given slice.
+// This is the generic equivalent of Map.
+func MapOf[T any, U any](in []T, fn func(i int, value T) U) []U {
+	var out = make([]U, len(in))
+
+	for i, value := range in {
+		out[i] = fn(i, value)
+	}
+
+	return out
+}
+
+// Returns a new slice containing only the elements for which fn returns true.
+func Filter[T any](in []T, fn func(i int, value T) bool) []T {
+	var out = make([]T, 0)
+
+	for i, value := range in {
+		if fn(i, value) {
+			out = append(out, value)
+		}
+	}
+
+	return out
+}
+
+// Calls fn on each element of the given slice, passing the result of the previous call (or
+// initial, for the first element) as the accumulator.  Returns the result of the last call.
+func Reduce[T any, A any](in []T, initial A, fn func(accumulator A, i int, value T) A) A {
+	var acc = initial
+
+	for i, value := range in {
+		acc = fn(acc, i, value)
+	}
+
+	return acc
+}
+
+// Returns a new slice containing the first occurrence of each distinct element, in order.  This
+// is the generic equivalent of Unique.
+func UniqueOf[T comparable](in []T) []T {
+	return UniqueBy(in, func(value T) T {
+		return value
+	})
+}
+
+// Returns a new slice containing the first element for each distinct value returned by key, in
+// order.
+func UniqueBy[T any, K comparable](in []T, key func(value T) K) []T {
+	var seen = make(map[K]bool, len(in))
+	var out = make([]T, 0)
+
+	for _, value := range in {
+		if k := key(value); !seen[k] {
+			seen[k] = true
+			out = append(out, value)
+		}
+	}
+
+	return out
+}
+
+// Divides the given slice into chunks of (at most) the given length.  This is the generic
+// equivalent of Chunks.  The chunks share the underlying array of the input slice.
+func Chunk[T any](in []T, size int) [][]T {
+	if size <= 0 {
+		return make([][]T, 0)
+	}
+
+	var out = make([][]T, 0, (len(in)+size-1)/size)
+
+	for len(in) > size {
+		out = append(out, in[:size:size])
+		in = in[size:]
+	}
+
+	if len(in) > 0 {
+		out = append(out, in)
+	}
+
+	return out
+}
+
+// Concatenates the given slices into a single slice.  This is the generic equivalent of Flatten,
+// but only flattens a single level.
+func FlattenOf[T any](in [][]T) []T {
+	var n int
+
+	for _, inner := range in {
+		n += len(inner)
+	}
+
+	var out = make([]T, 0, n)
+
+	for _, inner := range in {
+		out = append(out, inner...)
+	}
+
+	return out
+}
+
+// Groups the elements of the given slice by the value returned by key.  Elements within each
+// group retain their original order.
+func GroupBy[T any, K comparable](in []T, key func(value T) K) map[K][]T {
+	var out = make(map[K][]T)
+
+	for _, value := range in {
+		var k = key(value)
+
+		out[k] = append(out[k], value)
+	}
+
+	return out
+}
+
+// Returns a map of the elements of the given slice, keyed on the value returned by key.  If
+// multiple elements have the same key, the last one wins.
+func KeyBy[T any, K comparable](in []T, key func(value T) K) map[K]T {
+	var out = make(map[K]T, len(in))
+
+	for _, value := range in {
+		out[key(value)] = value
+	}
+
+	return out
+}
+
+// Splits the given slice into the elements for which fn returns true and those for which it
+// returns false.
+func Partition[T any](in []T, fn func(i int, value T) bool) ([]T, []T) {
+	var matched = make([]T, 0)
+	var unmatched = make([]T, 0)
+
+	for i, value := range in {
+		if fn(i, value) {
+			matched = append(matched, value)
+		} else {
+			unmatched = append(unmatched, value)
+		}
+	}
+
+	return matched, unmatched
+}
+
+// Returns the first element for which fn returns true, and whether one was found.
+func Find[T any](in []T, fn func(i int, value T) bool) (T, bool) {
+	for i, value := range in {
+		if fn(i, value) {
+			return value, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
+
+// Returns the index of the first element equal to value, or -1 if it is not present.
+func IndexOf[T comparable](in []T, value T) int {
+	for i, v := range in {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Returns the distinct elements present in both slices, in the order they appear in the first.
+// This is the generic equivalent of Intersect.
+func IntersectOf[T comparable](a []T, b []T) []T {
+	var inB = make(map[T]bool, len(b))
+	var out = make([]T, 0)
+
+	for _, value := range b {
+		inB[value] = true
+	}
+
+	for _, value := range a {
+		if inB[value] {
+			out = append(out, value)
+			delete(inB, value)
+		}
+	}
+
+	return out
+}
+
+// Returns the elements of the first slice that are not present in the second.  This is the
+// generic equivalent of Difference.
+func DifferenceOf[T comparable](first []T, second []T) []T {
+	var inSecond = make(map[T]bool, len(second))
+	var out = make([]T, 0)
+
+	for _, value := range second {
+		inSecond[value] = true
+	}
+
+	for _, value := range first {
+		if !inSecond[value] {
+			out = append(out, value)
+		}
+	}
+
+	return out
+}
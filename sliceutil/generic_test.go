@@ -0,0 +1,230 @@
+package sliceutil
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+type genericTestUser struct {
+	Name  string
+	Group string
+	Age   int
+}
+
+var genericTestUsers = []genericTestUser{
+	{`alice`, `admin`, 31},
+	{`bob`, `user`, 25},
+	{`carol`, `admin`, 42},
+	{`dave`, `user`, 25},
+}
+
+func TestMapOf(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([]string{`0:a`, `1:b`}, MapOf([]string{`a`, `b`}, func(i int, v string) string {
+		return strconv.Itoa(i) + `:` + v
+	}))
+
+	assert.Equal([]int{2, 4, 6}, MapOf([]int{1, 2, 3}, func(_ int, v int) int {
+		return v * 2
+	}))
+
+	assert.Equal([]int{}, MapOf[int, int](nil, nil))
+	assert.Equal([]int{}, MapOf([]int{}, func(_ int, v int) int { return v }))
+}
+
+func TestFilterReducePartitionFind(t *testing.T) {
+	assert := require.New(t)
+
+	var even = func(_ int, v int) bool {
+		return v%2 == 0
+	}
+
+	assert.Equal([]int{2, 4}, Filter([]int{1, 2, 3, 4, 5}, even))
+	assert.Equal([]int{}, Filter([]int{1, 3}, even))
+
+	assert.Equal(15, Reduce([]int{1, 2, 3, 4, 5}, 0, func(acc int, _ int, v int) int {
+		return acc + v
+	}))
+
+	assert.Equal(`abc`, Reduce([]string{`a`, `b`, `c`}, ``, func(acc string, _ int, v string) string {
+		return acc + v
+	}))
+
+	matched, unmatched := Partition([]int{1, 2, 3, 4, 5}, even)
+	assert.Equal([]int{2, 4}, matched)
+	assert.Equal([]int{1, 3, 5}, unmatched)
+
+	user, ok := Find(genericTestUsers, func(_ int, u genericTestUser) bool {
+		return u.Age > 40
+	})
+
+	assert.True(ok)
+	assert.Equal(`carol`, user.Name)
+
+	_, ok = Find(genericTestUsers, func(_ int, u genericTestUser) bool {
+		return u.Age > 100
+	})
+
+	assert.False(ok)
+
+	assert.Equal(2, IndexOf([]string{`a`, `b`, `c`}, `c`))
+	assert.Equal(-1, IndexOf([]string{`a`, `b`, `c`}, `d`))
+}
+
+func TestUniqueOf(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([]int{3, 1, 2}, UniqueOf([]int{3, 1, 3, 2, 1}))
+	assert.Equal([]string{}, UniqueOf([]string{}))
+
+	assert.Equal([]string{`alice`, `bob`}, MapOf(UniqueBy(genericTestUsers, func(u genericTestUser) string {
+		return u.Group
+	}), func(_ int, u genericTestUser) string {
+		return u.Name
+	}))
+}
+
+func TestChunkFlattenOf(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([][]int{{1, 2}, {3, 4}, {5}}, Chunk([]int{1, 2, 3, 4, 5}, 2))
+	assert.Equal([][]int{{1, 2, 3}}, Chunk([]int{1, 2, 3}, 5))
+	assert.Equal([][]int{}, Chunk([]int{}, 2))
+	assert.Equal([][]int{}, Chunk([]int{1}, 0))
+
+	// appending to a chunk must not clobber the next one
+	var chunks = Chunk([]int{1, 2, 3, 4}, 2)
+	_ = append(chunks[0], 99)
+	assert.Equal([]int{3, 4}, chunks[1])
+
+	assert.Equal([]int{1, 2, 3, 4, 5}, FlattenOf([][]int{{1, 2}, {}, {3, 4}, {5}}))
+	assert.Equal([]int{}, FlattenOf[int](nil))
+}
+
+func TestGroupByKeyBy(t *testing.T) {
+	assert := require.New(t)
+
+	var groups = GroupBy(genericTestUsers, func(u genericTestUser) string {
+		return u.Group
+	})
+
+	assert.Len(groups, 2)
+	assert.Equal([]genericTestUser{genericTestUsers[0], genericTestUsers[2]}, groups[`admin`])
+	assert.Equal([]genericTestUser{genericTestUsers[1], genericTestUsers[3]}, groups[`user`])
+
+	var byAge = KeyBy(genericTestUsers, func(u genericTestUser) int {
+		return u.Age
+	})
+
+	assert.Len(byAge, 3)
+	assert.Equal(`dave`, byAge[25].Name)
+}
+
+func TestIntersectDifferenceOf(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal([]string{`b`, `c`}, IntersectOf([]string{`a`, `b`, `c`, `b`}, []string{`c`, `b`, `d`}))
+	assert.Equal([]int{}, IntersectOf([]int{1, 2}, []int{3}))
+	assert.Equal([]int{1, 1, 4}, DifferenceOf([]int{1, 2, 1, 3, 4}, []int{2, 3}))
+	assert.Equal([]int{1, 2}, DifferenceOf([]int{1, 2}, nil))
+}
+
+var benchmarkInts = func() []int {
+	var out = make([]int, 1000)
+
+	for i := range out {
+		out[i] = i % 100
+	}
+
+	return out
+}()
+
+var benchmarkStrings = strings.Split(strings.Repeat(`alpha,beta,gamma,delta,`, 250), `,`)
+
+func BenchmarkMapReflection(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Map(benchmarkInts, func(_ int, v interface{}) interface{} {
+			return v.(int) * 2
+		})
+	}
+}
+
+func BenchmarkMapGeneric(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		MapOf(benchmarkInts, func(_ int, v int) int {
+			return v * 2
+		})
+	}
+}
+
+func BenchmarkUniqueReflection(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Unique(benchmarkInts)
+	}
+}
+
+func BenchmarkUniqueGeneric(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		UniqueOf(benchmarkInts)
+	}
+}
+
+func BenchmarkIntersectReflection(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Intersect(benchmarkStrings, benchmarkStrings[:10])
+	}
+}
+
+func BenchmarkIntersectGeneric(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		IntersectOf(benchmarkStrings, benchmarkStrings[:10])
+	}
+}
+
+func BenchmarkDifferenceReflection(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Difference(benchmarkInts, benchmarkInts[:50])
+	}
+}
+
+func BenchmarkDifferenceGeneric(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		DifferenceOf(benchmarkInts, benchmarkInts[:50])
+	}
+}
+
+func BenchmarkChunksReflection(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Chunks(benchmarkInts, 16)
+	}
+}
+
+func BenchmarkChunkGeneric(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Chunk(benchmarkInts, 16)
+	}
+}
+
+func BenchmarkFlattenReflection(b *testing.B) {
+	var nested = Chunks(benchmarkInts, 16)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		Flatten(nested)
+	}
+}
+
+func BenchmarkFlattenGeneric(b *testing.B) {
+	var nested = Chunk(benchmarkInts, 16)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		FlattenOf(nested)
+	}
+}
@@ -0,0 +1,166 @@
+package sliceutil
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ghetzel/go-stockutil/utils"
+)
+
+// Options that control how ParallelEach and ParallelMap process items.
+type ParallelOptions struct {
+	// The maximum number of items processed at once.  If zero or negative, runtime.GOMAXPROCS(0)
+	// is used.
+	Concurrency int
+
+	// If true, every item is processed regardless of errors, and all errors are returned together
+	// as a *multierror.Error (in the order of the items that caused them).  Otherwise, the first
+	// error cancels the context passed to the remaining items, items that have not started are
+	// skipped, and that error is returned.
+	CollectErrors bool
+}
+
+// Returned (or collected) when processing an item panics.
+type PanicError struct {
+	Index int
+	Value interface{}
+	Stack []byte
+}
+
+func (self *PanicError) Error() string {
+	return fmt.Sprintf("item %d: panic: %v", self.Index, self.Value)
+}
+
+// Calls fn for each element of the given slice, processing up to options.Concurrency elements at
+// once.  If fn returns Stop, no further elements are started and no error is returned.  Panics in
+// fn are recovered and returned as a *PanicError.  If the given context is cancelled, no further
+// elements are started and the context's error is returned (unless every element had already been
+// started).  If options are nil, the defaults
+// described in ParallelOptions are used.
+func ParallelEach[T any](ctx context.Context, in []T, options *ParallelOptions, fn func(ctx context.Context, i int, value T) error) error {
+	return parallel(ctx, len(in), options, func(ctx context.Context, i int) error {
+		return fn(ctx, i, in[i])
+	})
+}
+
+// Same as ParallelEach, but returns a slice of the results of each call to fn in the same order
+// as the input.  Results for elements that failed or were skipped are left as the zero value.
+func ParallelMap[T any, U any](ctx context.Context, in []T, options *ParallelOptions, fn func(ctx context.Context, i int, value T) (U, error)) ([]U, error) {
+	var out = make([]U, len(in))
+
+	var err = parallel(ctx, len(in), options, func(ctx context.Context, i int) error {
+		var result, err = fn(ctx, i, in[i])
+
+		if err == nil {
+			out[i] = result
+		}
+
+		return err
+	})
+
+	return out, err
+}
+
+func parallel(parent context.Context, n int, options *ParallelOptions, fn func(ctx context.Context, i int) error) error {
+	if options == nil {
+		options = new(ParallelOptions)
+	}
+
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	var ctx, cancel = context.WithCancel(parent)
+	defer cancel()
+
+	var workers = options.Concurrency
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > n {
+		workers = n
+	}
+
+	var errs = make([]error, n)
+	var next = int64(-1)
+	var skipped int32
+	var firstErr error
+	var firstOnce sync.Once
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				var i = int(atomic.AddInt64(&next, 1))
+
+				if i >= n {
+					return
+				} else if ctx.Err() != nil {
+					atomic.StoreInt32(&skipped, 1)
+					return
+				}
+
+				if err := callRecovered(ctx, i, fn); err == Stop {
+					cancel()
+				} else if err != nil {
+					errs[i] = err
+
+					if !options.CollectErrors {
+						firstOnce.Do(func() {
+							firstErr = err
+						})
+
+						cancel()
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// the parent's cancellation is only an error if it caused items to be skipped
+	var cancelled error
+
+	if atomic.LoadInt32(&skipped) != 0 {
+		cancelled = parent.Err()
+	}
+
+	if options.CollectErrors {
+		var merged error
+
+		for _, err := range errs {
+			merged = utils.AppendError(merged, err)
+		}
+
+		return utils.AppendError(merged, cancelled)
+	} else if firstErr != nil {
+		return firstErr
+	}
+
+	return cancelled
+}
+
+func callRecovered(ctx context.Context, i int, fn func(ctx context.Context, i int) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Index: i,
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+
+	return fn(ctx, i)
+}
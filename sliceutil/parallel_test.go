@@ -0,0 +1,214 @@
+package sliceutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ghetzel/testify/require"
+	multierror "github.com/hashicorp/go-multierror"
+)
+
+func TestParallelMap(t *testing.T) {
+	assert := require.New(t)
+
+	var input = make([]int, 100)
+
+	for i := range input {
+		input[i] = i
+	}
+
+	var running, peak int64
+
+	out, err := ParallelMap(context.Background(), input, &ParallelOptions{
+		Concurrency: 4,
+	}, func(_ context.Context, _ int, v int) (string, error) {
+		var now = atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+
+		for {
+			var p = atomic.LoadInt64(&peak)
+
+			if now <= p || atomic.CompareAndSwapInt64(&peak, p, now) {
+				break
+			}
+		}
+
+		// finish out of order
+		time.Sleep(time.Duration(100-v) * time.Microsecond)
+
+		return fmt.Sprintf("%d", v*v), nil
+	})
+
+	assert.NoError(err)
+	assert.Len(out, 100)
+	assert.Equal(`0`, out[0])
+	assert.Equal(`81`, out[9])
+	assert.Equal(`9801`, out[99])
+	assert.LessOrEqual(peak, int64(4))
+
+	out, err = ParallelMap(nil, []int{}, nil, func(_ context.Context, _ int, v int) (string, error) {
+		return ``, nil
+	})
+
+	assert.NoError(err)
+	assert.Empty(out)
+}
+
+func TestParallelEachFirstError(t *testing.T) {
+	assert := require.New(t)
+
+	var calls int64
+	var boom = errors.New(`boom`)
+
+	var err = ParallelEach(context.Background(), make([]int, 1000), &ParallelOptions{
+		Concurrency: 2,
+	}, func(ctx context.Context, i int, _ int) error {
+		atomic.AddInt64(&calls, 1)
+
+		if i == 5 {
+			return boom
+		}
+
+		return nil
+	})
+
+	assert.Equal(boom, err)
+	assert.Less(calls, int64(1000))
+}
+
+func TestParallelEachCollectErrors(t *testing.T) {
+	assert := require.New(t)
+
+	var calls int64
+
+	var err = ParallelEach(context.Background(), []string{`a`, `b`, `c`, `d`, `e`}, &ParallelOptions{
+		Concurrency:   3,
+		CollectErrors: true,
+	}, func(_ context.Context, i int, v string) error {
+		atomic.AddInt64(&calls, 1)
+
+		switch v {
+		case `b`, `d`:
+			return fmt.Errorf("bad %s", v)
+		case `e`:
+			panic(`oh no`)
+		}
+
+		return nil
+	})
+
+	assert.Equal(int64(5), calls)
+	assert.IsType(&multierror.Error{}, err)
+
+	var errs = err.(*multierror.Error).Errors
+
+	assert.Len(errs, 3)
+	assert.EqualError(errs[0], `bad b`)
+	assert.EqualError(errs[1], `bad d`)
+	assert.EqualError(errs[2], `item 4: panic: oh no`)
+
+	var panicErr *PanicError
+
+	assert.True(errors.As(errs[2], &panicErr))
+	assert.Equal(4, panicErr.Index)
+	assert.Equal(`oh no`, panicErr.Value)
+	assert.NotEmpty(panicErr.Stack)
+}
+
+func TestParallelEachPanic(t *testing.T) {
+	assert := require.New(t)
+
+	var err = ParallelEach(context.Background(), []int{1, 2, 3}, nil, func(_ context.Context, _ int, v int) error {
+		if v == 2 {
+			var m map[string]int
+			m[`x`] = 1
+		}
+
+		return nil
+	})
+
+	assert.Error(err)
+	assert.IsType(&PanicError{}, err)
+}
+
+func TestParallelEachStop(t *testing.T) {
+	assert := require.New(t)
+
+	var calls int64
+
+	var err = ParallelEach(context.Background(), make([]int, 1000), &ParallelOptions{
+		Concurrency: 1,
+	}, func(_ context.Context, i int, _ int) error {
+		atomic.AddInt64(&calls, 1)
+
+		if i == 9 {
+			return Stop
+		}
+
+		return nil
+	})
+
+	assert.NoError(err)
+	assert.Equal(int64(10), calls)
+}
+
+func TestParallelEachContextCancel(t *testing.T) {
+	assert := require.New(t)
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var calls int64
+
+	var err = ParallelEach(ctx, make([]int, 1000), &ParallelOptions{
+		Concurrency: 2,
+	}, func(ctx context.Context, i int, _ int) error {
+		if atomic.AddInt64(&calls, 1) == 10 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	assert.Equal(context.Canceled, err)
+	assert.Less(calls, int64(1000))
+}
+
+func TestParallelEachCancelAfterCompletion(t *testing.T) {
+	assert := require.New(t)
+
+	for _, collect := range []bool{false, true} {
+		var ctx, cancel = context.WithCancel(context.Background())
+		var calls int64
+
+		// the last item cancels the context, but nothing is skipped because of it
+		var err = ParallelEach(ctx, make([]int, 10), &ParallelOptions{
+			Concurrency:   1,
+			CollectErrors: collect,
+		}, func(ctx context.Context, i int, _ int) error {
+			if atomic.AddInt64(&calls, 1) == 10 {
+				cancel()
+			}
+
+			return nil
+		})
+
+		assert.NoError(err)
+		assert.Equal(int64(10), calls)
+
+		// a context that is already cancelled skips everything
+		err = ParallelEach(ctx, make([]int, 10), &ParallelOptions{
+			CollectErrors: collect,
+		}, func(ctx context.Context, i int, _ int) error {
+			return nil
+		})
+
+		if collect {
+			assert.True(errors.Is(err, context.Canceled))
+		} else {
+			assert.Equal(context.Canceled, err)
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package sliceutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// A Set is an unordered collection of distinct values.  The zero value is an empty set ready to
+// use.  Sets are not safe for concurrent modification.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// Returns a new Set containing the given items.  To create a set from a slice, use
+// NewSet(slice...).
+func NewSet[T comparable](items ...T) *Set[T] {
+	var set = &Set[T]{
+		items: make(map[T]struct{}, len(items)),
+	}
+
+	set.Add(items...)
+
+	return set
+}
+
+// Adds the given items to the set.
+func (self *Set[T]) Add(items ...T) {
+	if self.items == nil {
+		self.items = make(map[T]struct{}, len(items))
+	}
+
+	for _, item := range items {
+		self.items[item] = struct{}{}
+	}
+}
+
+// Removes the given items from the set.
+func (self *Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(self.items, item)
+	}
+}
+
+// Returns whether the given item is in the set.
+func (self *Set[T]) Has(item T) bool {
+	var _, ok = self.items[item]
+	return ok
+}
+
+// Returns the number of items in the set.
+func (self *Set[T]) Len() int {
+	return len(self.items)
+}
+
+// Removes all items from the set.
+func (self *Set[T]) Clear() {
+	self.items = nil
+}
+
+// Returns the items in the set as a slice, in no particular order.
+func (self *Set[T]) Values() []T {
+	var out = make([]T, 0, len(self.items))
+
+	for item := range self.items {
+		out = append(out, item)
+	}
+
+	return out
+}
+
+// Returns a copy of the set.
+func (self *Set[T]) Clone() *Set[T] {
+	var out = &Set[T]{
+		items: make(map[T]struct{}, len(self.items)),
+	}
+
+	for item := range self.items {
+		out.items[item] = struct{}{}
+	}
+
+	return out
+}
+
+// Returns a new set containing the items in either set.
+func (self *Set[T]) Union(other *Set[T]) *Set[T] {
+	var out = self.Clone()
+
+	for item := range other.items {
+		out.items[item] = struct{}{}
+	}
+
+	return out
+}
+
+// Returns a new set containing the items present in both sets.
+func (self *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	var out = NewSet[T]()
+
+	for item := range self.items {
+		if other.Has(item) {
+			out.items[item] = struct{}{}
+		}
+	}
+
+	return out
+}
+
+// Returns a new set containing the items in this set that are not in the other.
+func (self *Set[T]) Difference(other *Set[T]) *Set[T] {
+	var out = NewSet[T]()
+
+	for item := range self.items {
+		if !other.Has(item) {
+			out.items[item] = struct{}{}
+		}
+	}
+
+	return out
+}
+
+// Returns a new set containing the items that are in exactly one of the two sets.
+func (self *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	var out = self.Difference(other)
+
+	for item := range other.items {
+		if !self.Has(item) {
+			out.items[item] = struct{}{}
+		}
+	}
+
+	return out
+}
+
+// Returns whether every item in this set is also in the other.
+func (self *Set[T]) IsSubset(other *Set[T]) bool {
+	if self.Len() > other.Len() {
+		return false
+	}
+
+	for item := range self.items {
+		if !other.Has(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns whether every item in the other set is also in this one.
+func (self *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(self)
+}
+
+// Returns whether both sets contain exactly the same items.
+func (self *Set[T]) Equal(other *Set[T]) bool {
+	return self.Len() == other.Len() && self.IsSubset(other)
+}
+
+// Sets are marshaled as JSON arrays.  So that the output is deterministic, the elements are
+// sorted loosely (see typeutil.IsLessThan), with any ties ordered by their string representation.
+func (self Set[T]) MarshalJSON() ([]byte, error) {
+	var values = self.Values()
+
+	sort.SliceStable(values, func(i int, j int) bool {
+		if c := compareLoose(values[i], values[j], false); c != 0 {
+			return c < 0
+		}
+
+		return fmt.Sprintf("%#v", values[i]) < fmt.Sprintf("%#v", values[j])
+	})
+
+	return json.Marshal(values)
+}
+
+// Sets are unmarshaled from JSON arrays; duplicate values are discarded.
+func (self *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	self.Clear()
+	self.Add(items...)
+
+	return nil
+}
+
+// An OrderedSet is a collection of distinct values that remembers the order in which they were
+// first added.  The zero value is an empty set ready to use.  OrderedSets are not safe for
+// concurrent modification.
+type OrderedSet[T comparable] struct {
+	items []T
+	index map[T]int
+}
+
+// Returns a new OrderedSet containing the given items, in order.  To create a set from a slice,
+// use NewOrderedSet(slice...).
+func NewOrderedSet[T comparable](items ...T) *OrderedSet[T] {
+	var set = &OrderedSet[T]{
+		items: make([]T, 0, len(items)),
+		index: make(map[T]int, len(items)),
+	}
+
+	set.Add(items...)
+
+	return set
+}
+
+// Adds the given items to the end of the set.  Items already in the set keep their position.
+func (self *OrderedSet[T]) Add(items ...T) {
+	if self.index == nil {
+		self.index = make(map[T]int, len(items))
+	}
+
+	for _, item := range items {
+		if _, ok := self.index[item]; !ok {
+			self.index[item] = len(self.items)
+			self.items = append(self.items, item)
+		}
+	}
+}
+
+// Removes the given items from the set.
+func (self *OrderedSet[T]) Remove(items ...T) {
+	var removed bool
+
+	for _, item := range items {
+		if _, ok := self.index[item]; ok {
+			delete(self.index, item)
+			removed = true
+		}
+	}
+
+	if !removed {
+		return
+	}
+
+	// compact the remaining items and reindex them
+	var kept = self.items[:0]
+
+	for _, item := range self.items {
+		if _, ok := self.index[item]; ok {
+			self.index[item] = len(kept)
+			kept = append(kept, item)
+		}
+	}
+
+	self.items = kept
+}
+
+// Returns whether the given item is in the set.
+func (self *OrderedSet[T]) Has(item T) bool {
+	var _, ok = self.index[item]
+	return ok
+}
+
+// Returns the position of the given item in the set, or -1 if it is not present.
+func (self *OrderedSet[T]) IndexOf(item T) int {
+	if i, ok := self.index[item]; ok {
+		return i
+	}
+
+	return -1
+}
+
+// Returns the number of items in the set.
+func (self *OrderedSet[T]) Len() int {
+	return len(self.items)
+}
+
+// Removes all items from the set.
+func (self *OrderedSet[T]) Clear() {
+	self.items = nil
+	self.index = nil
+}
+
+// Returns a copy of the items in the set, in order.
+func (self *OrderedSet[T]) Values() []T {
+	var out = make([]T, len(self.items))
+
+	copy(out, self.items)
+
+	return out
+}
+
+// Returns a copy of the set.
+func (self *OrderedSet[T]) Clone() *OrderedSet[T] {
+	return NewOrderedSet(self.items...)
+}
+
+// Returns a new set containing the items in this set followed by those in the other.
+func (self *OrderedSet[T]) Union(other *OrderedSet[T]) *OrderedSet[T] {
+	var out = self.Clone()
+
+	out.Add(other.items...)
+
+	return out
+}
+
+// Returns a new set containing the items present in both sets, in this set's order.
+func (self *OrderedSet[T]) Intersection(other *OrderedSet[T]) *OrderedSet[T] {
+	var out = NewOrderedSet[T]()
+
+	for _, item := range self.items {
+		if other.Has(item) {
+			out.Add(item)
+		}
+	}
+
+	return out
+}
+
+// Returns a new set containing the items in this set that are not in the other, in this set's
+// order.
+func (self *OrderedSet[T]) Difference(other *OrderedSet[T]) *OrderedSet[T] {
+	var out = NewOrderedSet[T]()
+
+	for _, item := range self.items {
+		if !other.Has(item) {
+			out.Add(item)
+		}
+	}
+
+	return out
+}
+
+// Returns a new set containing the items that are in exactly one of the two sets: those unique
+// to this set (in order), followed by those unique to the other.
+func (self *OrderedSet[T]) SymmetricDifference(other *OrderedSet[T]) *OrderedSet[T] {
+	var out = self.Difference(other)
+
+	for _, item := range other.items {
+		if !self.Has(item) {
+			out.Add(item)
+		}
+	}
+
+	return out
+}
+
+// Returns whether every item in this set is also in the other.
+func (self *OrderedSet[T]) IsSubset(other *OrderedSet[T]) bool {
+	if self.Len() > other.Len() {
+		return false
+	}
+
+	for _, item := range self.items {
+		if !other.Has(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns whether every item in the other set is also in this one.
+func (self *OrderedSet[T]) IsSuperset(other *OrderedSet[T]) bool {
+	return other.IsSubset(self)
+}
+
+// Returns whether both sets contain exactly the same items, regardless of order.
+func (self *OrderedSet[T]) Equal(other *OrderedSet[T]) bool {
+	return self.Len() == other.Len() && self.IsSubset(other)
+}
+
+// OrderedSets are marshaled as JSON arrays in insertion order.
+func (self OrderedSet[T]) MarshalJSON() ([]byte, error) {
+	if self.items == nil {
+		return []byte(`[]`), nil
+	}
+
+	return json.Marshal(self.items)
+}
+
+// OrderedSets are unmarshaled from JSON arrays; duplicate values are discarded.
+func (self *OrderedSet[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	self.Clear()
+	self.Add(items...)
+
+	return nil
+}
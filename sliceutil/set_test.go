@@ -0,0 +1,142 @@
+package sliceutil
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func sortedSet(set *Set[string]) []string {
+	var values = set.Values()
+
+	sort.Strings(values)
+
+	return values
+}
+
+func TestSet(t *testing.T) {
+	assert := require.New(t)
+
+	var set Set[string]
+
+	assert.Equal(0, set.Len())
+	assert.False(set.Has(`a`))
+
+	set.Add(`a`, `b`, `a`)
+	assert.Equal(2, set.Len())
+	assert.True(set.Has(`a`))
+	assert.True(set.Has(`b`))
+
+	set.Remove(`a`, `z`)
+	assert.False(set.Has(`a`))
+	assert.Equal([]string{`b`}, set.Values())
+
+	set.Clear()
+	assert.Equal(0, set.Len())
+
+	var a = NewSet(`a`, `b`, `c`)
+	var b = NewSet([]string{`b`, `c`, `d`}...)
+
+	assert.Equal([]string{`a`, `b`, `c`, `d`}, sortedSet(a.Union(b)))
+	assert.Equal([]string{`b`, `c`}, sortedSet(a.Intersection(b)))
+	assert.Equal([]string{`a`}, sortedSet(a.Difference(b)))
+	assert.Equal([]string{`a`, `d`}, sortedSet(a.SymmetricDifference(b)))
+
+	// operations don't modify their operands
+	assert.Equal([]string{`a`, `b`, `c`}, sortedSet(a))
+
+	assert.True(NewSet(`b`, `c`).IsSubset(a))
+	assert.False(b.IsSubset(a))
+	assert.True(a.IsSuperset(NewSet(`a`)))
+	assert.True(NewSet[string]().IsSubset(a))
+	assert.True(a.Equal(NewSet(`c`, `b`, `a`)))
+	assert.False(a.Equal(b))
+
+	var clone = a.Clone()
+	clone.Add(`x`)
+	assert.False(a.Has(`x`))
+}
+
+func TestSetJSON(t *testing.T) {
+	assert := require.New(t)
+
+	data, err := json.Marshal(NewSet(3))
+	assert.NoError(err)
+	assert.Equal(`[3]`, string(data))
+
+	data, err = json.Marshal(Set[int]{})
+	assert.NoError(err)
+	assert.Equal(`[]`, string(data))
+
+	// elements are sorted so that output is deterministic
+	for i := 0; i < 10; i++ {
+		data, err = json.Marshal(NewSet(10, 2, 33, 1, -4))
+		assert.NoError(err)
+		assert.Equal(`[-4,1,2,10,33]`, string(data))
+
+		data, err = json.Marshal(NewSet(`b`, `01`, `a`, `1`, `c`))
+		assert.NoError(err)
+		assert.Equal(`["01","1","a","b","c"]`, string(data))
+	}
+
+	var set Set[int]
+
+	assert.NoError(json.Unmarshal([]byte(`[1, 2, 2, 3]`), &set))
+	assert.Equal(3, set.Len())
+	assert.True(set.Has(2))
+
+	assert.Error(json.Unmarshal([]byte(`{"a": 1}`), &set))
+
+	var wrapper struct {
+		Tags *Set[string] `json:"tags"`
+	}
+
+	assert.NoError(json.Unmarshal([]byte(`{"tags": ["x", "y", "x"]}`), &wrapper))
+	assert.Equal([]string{`x`, `y`}, sortedSet(wrapper.Tags))
+}
+
+func TestOrderedSet(t *testing.T) {
+	assert := require.New(t)
+
+	var set OrderedSet[string]
+
+	set.Add(`c`, `a`, `c`, `b`)
+	assert.Equal([]string{`c`, `a`, `b`}, set.Values())
+	assert.Equal(1, set.IndexOf(`a`))
+	assert.Equal(-1, set.IndexOf(`z`))
+
+	set.Remove(`c`)
+	assert.Equal([]string{`a`, `b`}, set.Values())
+	assert.Equal(0, set.IndexOf(`a`))
+	assert.Equal(1, set.IndexOf(`b`))
+
+	set.Add(`c`)
+	assert.Equal([]string{`a`, `b`, `c`}, set.Values())
+
+	var a = NewOrderedSet(`d`, `a`, `c`, `b`)
+	var b = NewOrderedSet(`e`, `c`, `a`)
+
+	assert.Equal([]string{`d`, `a`, `c`, `b`, `e`}, a.Union(b).Values())
+	assert.Equal([]string{`a`, `c`}, a.Intersection(b).Values())
+	assert.Equal([]string{`d`, `b`}, a.Difference(b).Values())
+	assert.Equal([]string{`d`, `b`, `e`}, a.SymmetricDifference(b).Values())
+	assert.True(NewOrderedSet(`c`, `a`).IsSubset(a))
+	assert.True(a.IsSuperset(NewOrderedSet(`b`)))
+	assert.False(a.IsSubset(b))
+	assert.True(a.Equal(NewOrderedSet(`a`, `b`, `c`, `d`)))
+
+	data, err := json.Marshal(a)
+	assert.NoError(err)
+	assert.Equal(`["d","a","c","b"]`, string(data))
+
+	data, err = json.Marshal(OrderedSet[int]{})
+	assert.NoError(err)
+	assert.Equal(`[]`, string(data))
+
+	var decoded OrderedSet[string]
+
+	assert.NoError(json.Unmarshal([]byte(`["z", "y", "z", "x"]`), &decoded))
+	assert.Equal([]string{`z`, `y`, `x`}, decoded.Values())
+}
@@ -426,14 +426,27 @@ func Map(in interface{}, fn MapFunc) []interface{} {
 	return out
 }
 
-// Same with Map. Accepts an in function.
+// Same as Map, but appends the results to the slice pointed to by out.  Each result is
+// converted to the element type of the output slice.
 func MapOut(in interface{}, out interface{}, fn MapFunc) {
+	outV := reflect.ValueOf(out)
+
+	if outV.Kind() != reflect.Ptr || outV.Elem().Kind() != reflect.Slice {
+		return
+	}
+
+	outV = outV.Elem()
+	elemT := outV.Type().Elem()
 
 	Each(in, func(i int, v interface{}) error {
-		out = append(out, fn(i, v))
+		if rV := reflect.ValueOf(fn(i, v)); rV.IsValid() && rV.Type().ConvertibleTo(elemT) {
+			outV.Set(reflect.Append(outV, rV.Convert(elemT)))
+		} else {
+			outV.Set(reflect.Append(outV, reflect.Zero(elemT)))
+		}
+
 		return nil
 	})
-	
 }
 
 // Returns a copy of the given slice with each element modified by the a given function, then
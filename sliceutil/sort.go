@@ -0,0 +1,149 @@
+package sliceutil
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghetzel/go-stockutil/stringutil"
+	"github.com/ghetzel/go-stockutil/typeutil"
+)
+
+// The separator between the parts of a key path given to SortBy.
+var SortKeySeparator = `.`
+
+// Options that control how SortWith orders records.
+type SortOptions struct {
+	// The key paths to sort by, in order of precedence.  Each key is a path into the record as
+	// resolved by typeutil.Variant.Get (e.g. "name", "address.city", or "tags.0"), separated by
+	// SortKeySeparator.  Keys prefixed with "-" sort in descending order.
+	Keys []string
+
+	// Compare strings in natural order (see stringutil.NaturalLess) instead of lexically.
+	Natural bool
+
+	// Sort records whose value for a key is nil or missing before all other records instead of
+	// after them.  This applies regardless of the direction of the key.
+	NilsFirst bool
+}
+
+// Sorts the given slice of maps or structs in place by the values at the given key paths.  Keys
+// prefixed with "-" sort in descending order.  Values are compared loosely (see
+// typeutil.IsLessThan), so numbers and numeric strings compare numerically, and times compare
+// temporally.  The sort is stable, and records with nil or missing values sort last.
+func SortBy[T any](records []T, keys ...string) {
+	SortWith(records, &SortOptions{
+		Keys: keys,
+	})
+}
+
+// Same as SortBy, but with the given options.
+func SortWith[T any](records []T, options *SortOptions) {
+	if options == nil || len(options.Keys) == 0 {
+		return
+	}
+
+	type sortKey struct {
+		path       []interface{}
+		descending bool
+	}
+
+	var keys = make([]sortKey, len(options.Keys))
+
+	for i, key := range options.Keys {
+		if strings.HasPrefix(key, `-`) {
+			keys[i].descending = true
+			key = key[1:]
+		}
+
+		for _, part := range strings.Split(key, SortKeySeparator) {
+			keys[i].path = append(keys[i].path, part)
+		}
+	}
+
+	// resolve every value once up front rather than on each comparison
+	var values = make([][]interface{}, len(records))
+
+	for r, record := range records {
+		values[r] = make([]interface{}, len(keys))
+
+		for k, key := range keys {
+			values[r][k] = sortValue(record, key.path)
+		}
+	}
+
+	var order = make([]int, len(records))
+
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(i int, j int) bool {
+		for k, key := range keys {
+			var a, b = values[order[i]][k], values[order[j]][k]
+
+			if a == nil || b == nil {
+				if (a == nil) == (b == nil) {
+					continue
+				}
+
+				return (a == nil) == options.NilsFirst
+			}
+
+			if c := compareLoose(a, b, options.Natural); c != 0 {
+				return (c < 0) != key.descending
+			}
+		}
+
+		return false
+	})
+
+	var sorted = make([]T, len(records))
+
+	for i, r := range order {
+		sorted[i] = records[r]
+	}
+
+	copy(records, sorted)
+}
+
+func compareLoose(a interface{}, b interface{}, natural bool) int {
+	if natural {
+		if sa, ok := a.(string); ok {
+			if sb, ok := b.(string); ok && !(typeutil.IsNumeric(sa) && typeutil.IsNumeric(sb)) {
+				if stringutil.NaturalLess(sa, sb) {
+					return -1
+				} else if stringutil.NaturalLess(sb, sa) {
+					return 1
+				}
+
+				return 0
+			}
+		}
+	}
+
+	if typeutil.IsLessThan(a, b) {
+		return -1
+	} else if typeutil.IsLessThan(b, a) {
+		return 1
+	}
+
+	return 0
+}
+
+// retrieves the value at the given path, returning nil if the path does not exist or holds a nil
+// pointer, map, slice, or interface.
+func sortValue(record interface{}, path []interface{}) interface{} {
+	var value = typeutil.V(record).Get(path...).Value
+
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,132 @@
+package sliceutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ghetzel/testify/require"
+)
+
+type sortTestAddress struct {
+	City string `json:"city"`
+}
+
+type sortTestRecord struct {
+	Name    string           `json:"name"`
+	Age     int              `json:"age"`
+	Address *sortTestAddress `json:"address"`
+}
+
+func sortedNames(records []map[string]interface{}) []interface{} {
+	return MapOf(records, func(_ int, r map[string]interface{}) interface{} {
+		return r[`name`]
+	})
+}
+
+func TestSortByMaps(t *testing.T) {
+	assert := require.New(t)
+
+	var records = []map[string]interface{}{
+		{`name`: `carol`, `age`: `42`, `group`: `b`},
+		{`name`: `alice`, `age`: 31, `group`: `a`},
+		{`name`: `bob`, `age`: 9, `group`: `b`},
+		{`name`: `dave`, `group`: `a`},
+		{`name`: `erin`, `age`: 31.0, `group`: `a`},
+	}
+
+	// numbers and numeric strings compare numerically; missing values sort last
+	SortBy(records, `age`)
+	assert.Equal([]interface{}{`bob`, `alice`, `erin`, `carol`, `dave`}, sortedNames(records))
+
+	// ties keep their existing order (stable)
+	SortBy(records, `-age`)
+	assert.Equal([]interface{}{`carol`, `alice`, `erin`, `bob`, `dave`}, sortedNames(records))
+
+	SortBy(records, `group`, `-name`)
+	assert.Equal([]interface{}{`erin`, `dave`, `alice`, `carol`, `bob`}, sortedNames(records))
+
+	SortWith(records, &SortOptions{
+		Keys:      []string{`age`},
+		NilsFirst: true,
+	})
+
+	assert.Equal([]interface{}{`dave`, `bob`, `erin`, `alice`, `carol`}, sortedNames(records))
+
+	// no keys is a no-op
+	SortBy(records)
+	assert.Equal(`dave`, records[0][`name`])
+
+	// keys are converted to the type of the map's keys
+	var byIndex = []map[int]string{
+		{0: `x`, 1: `c`},
+		{0: `y`, 1: `a`},
+		{0: `z`},
+		{0: `w`, 1: `b`},
+	}
+
+	SortBy(byIndex, `1`)
+	assert.Equal([]string{`y`, `w`, `x`, `z`}, MapOf(byIndex, func(_ int, r map[int]string) string {
+		return r[0]
+	}))
+}
+
+func TestSortByStructs(t *testing.T) {
+	assert := require.New(t)
+
+	var records = []*sortTestRecord{
+		{Name: `a`, Age: 3, Address: &sortTestAddress{City: `Toronto`}},
+		{Name: `b`, Age: 1},
+		{Name: `c`, Age: 2, Address: &sortTestAddress{City: `Boston`}},
+		nil,
+	}
+
+	SortBy(records, `address.city`)
+	assert.Equal(`c`, records[0].Name)
+	assert.Equal(`a`, records[1].Name)
+
+	// tagged fields are resolved by their json tag, the same as typeutil.Variant.Get
+	SortBy(records, `age`)
+	assert.Equal([]int{1, 2, 3}, []int{records[0].Age, records[1].Age, records[2].Age})
+	assert.Nil(records[3])
+
+	SortBy(records, `-age`)
+	assert.Equal(`a`, records[0].Name)
+}
+
+func TestSortByNaturalAndTimes(t *testing.T) {
+	assert := require.New(t)
+
+	var files = []map[string]interface{}{
+		{`name`: `file10`},
+		{`name`: `file2`},
+		{`name`: `File1`},
+	}
+
+	SortBy(files, `name`)
+	assert.Equal([]interface{}{`File1`, `file10`, `file2`}, sortedNames(files))
+
+	SortWith(files, &SortOptions{
+		Keys:    []string{`name`},
+		Natural: true,
+	})
+
+	assert.Equal([]interface{}{`File1`, `file2`, `file10`}, sortedNames(files))
+
+	var now = time.Now()
+	var events = []map[string]interface{}{
+		{`name`: `later`, `at`: now.Add(time.Hour)},
+		{`name`: `earlier`, `at`: now.Add(-time.Hour)},
+		{`name`: `now`, `at`: now},
+	}
+
+	SortBy(events, `at`)
+	assert.Equal([]interface{}{`earlier`, `now`, `later`}, sortedNames(events))
+
+	var nested = []map[string]interface{}{
+		{`name`: `x`, `tags`: []string{`b`}},
+		{`name`: `y`, `tags`: []string{`a`}},
+	}
+
+	SortBy(nested, `tags.0`)
+	assert.Equal([]interface{}{`y`, `x`}, sortedNames(nested))
+}
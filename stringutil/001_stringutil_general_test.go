@@ -167,6 +167,9 @@ func TestCamelize(t *testing.T) {
 		`TestValue`:   `TestValue`,
 		`testValue`:   `TestValue`,
 		`TeSt VaLue`:  `TeStVaLue`,
+		`user_id`:     `UserID`,
+		`userIDs`:     `UserIDs`,
+		`http_server`: `HTTPServer`,
 	}
 
 	for have, want := range tests {
@@ -178,14 +181,16 @@ func TestUnderscore(t *testing.T) {
 	assert := require.New(t)
 
 	tests := map[string]string{
-		`Test`:       `test`,
-		`test`:       `test`,
-		`test_value`: `test_value`,
-		`test-Value`: `test_value`,
-		`test value`: `test_value`,
-		`TestValue`:  `test_value`,
-		`testValue`:  `test_value`,
-		`TeSt VaLue`: `te_st_va_lue`,
+		`Test`:         `test`,
+		`test`:         `test`,
+		`test_value`:   `test_value`,
+		`test-Value`:   `test_value`,
+		`test value`:   `test_value`,
+		`TestValue`:    `test_value`,
+		`testValue`:    `test_value`,
+		`TeSt VaLue`:   `te_st_va_lue`,
+		`HTTPServerID`: `http_server_id`,
+		`userIDs`:      `user_ids`,
 	}
 
 	for have, want := range tests {
@@ -197,14 +202,16 @@ func TestHyphenate(t *testing.T) {
 	assert := require.New(t)
 
 	tests := map[string]string{
-		`Test`:       `test`,
-		`test`:       `test`,
-		`test_value`: `test-value`,
-		`test-Value`: `test-value`,
-		`test value`: `test-value`,
-		`TestValue`:  `test-value`,
-		`testValue`:  `test-value`,
-		`TeSt VaLue`: `te-st-va-lue`,
+		`Test`:         `test`,
+		`test`:         `test`,
+		`test_value`:   `test-value`,
+		`test-Value`:   `test-value`,
+		`test value`:   `test-value`,
+		`TestValue`:    `test-value`,
+		`testValue`:    `test-value`,
+		`TeSt VaLue`:   `te-st-va-lue`,
+		`HTTPServerID`: `http-server-id`,
+		`userIDs`:      `user-ids`,
 	}
 
 	for have, want := range tests {
@@ -216,14 +223,16 @@ func TestSnakeify(t *testing.T) {
 	assert := require.New(t)
 
 	tests := map[string]string{
-		`Test`:       `test`,
-		`test`:       `test`,
-		`test_value`: `test:value`,
-		`test-Value`: `test:value`,
-		`test value`: `test:value`,
-		`TestValue`:  `test:value`,
-		`testValue`:  `test:value`,
-		`TeSt VaLue`: `te:st:va:lue`,
+		`Test`:         `test`,
+		`test`:         `test`,
+		`test_value`:   `test:value`,
+		`test-Value`:   `test:value`,
+		`test value`:   `test:value`,
+		`TestValue`:    `test:value`,
+		`testValue`:    `test:value`,
+		`TeSt VaLue`:   `te:st:va:lue`,
+		`HTTPServerID`: `http:server:id`,
+		`userIDs`:      `user:ids`,
 	}
 
 	for have, want := range tests {
@@ -428,6 +437,13 @@ func TestElide(t *testing.T) {
 	assert.Equal(`hello there`, ElideRight(`hello there`, 100, `...`))
 	assert.Equal(`hello...`, Elide(`hello there`, 5, `...`))
 	assert.Equal(`...there`, ElideRight(`hello there`, 5, `...`))
+
+	// strings exactly as long as the limit are not elided
+	assert.Equal(`hello`, Elide(`hello`, 5, `...`))
+	assert.Equal(`hello`, ElideRight(`hello`, 5, `...`))
+	assert.Equal(`hell...`, Elide(`hello`, 4, `...`))
+	assert.Equal(`...ello`, ElideRight(`hello`, 4, `...`))
+	assert.Equal(``, Elide(``, 0, `...`))
 }
 
 func TestElideWords(t *testing.T) {
@@ -597,6 +613,17 @@ func TestPrefixLines(t *testing.T) {
 
 	assert.Equal("...1\n...2\n...3", PrefixLines("1\n2\n3", `...`))
 	assert.Equal("...1\n...2\n...3\n...", PrefixLines("1\n2\n3\n", `...`))
+
+	// colors carried across lines do not apply to the prefix
+	assert.Equal(
+		"> \x1b[31mone\n\x1b[0m> \x1b[31mtwo\x1b[0m\n> three",
+		PrefixLines("\x1b[31mone\ntwo\x1b[0m\nthree", `> `),
+	)
+
+	assert.Equal(
+		"> \x1b[0;31mone\n\x1b[0m> \x1b[0;31m\x1b[1mtwo",
+		PrefixLines("\x1b[0;31mone\n\x1b[1mtwo", `> `),
+	)
 }
 
 func TestSuffixLines(t *testing.T) {
@@ -604,6 +631,12 @@ func TestSuffixLines(t *testing.T) {
 
 	assert.Equal("1<<<\n2<<<\n3<<<", SuffixLines("1\n2\n3", `<<<`))
 	assert.Equal("1<<<\n2<<<\n3<<<\n<<<", SuffixLines("1\n2\n3\n", `<<<`))
+
+	// colors left active at the end of a line do not apply to the suffix
+	assert.Equal(
+		"\x1b[31mone\x1b[0m <\x1b[31m\ntwo\x1b[0m <\nthree <",
+		SuffixLines("\x1b[31mone\ntwo\x1b[0m\nthree", ` <`),
+	)
 }
 
 func TestSplitTrimSpace(t *testing.T) {
@@ -0,0 +1,485 @@
+package stringutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ghetzel/go-stockutil/typeutil"
+)
+
+// Represents a style of capitalizing and joining the words in an identifier or phrase.
+type Case int
+
+const (
+	CamelCase          Case = iota // camelCase
+	PascalCase                     // PascalCase
+	SnakeCase                      // snake_case
+	ScreamingSnakeCase             // SCREAMING_SNAKE_CASE
+	KebabCase                      // kebab-case
+	ScreamingKebabCase             // SCREAMING-KEBAB-CASE
+	TrainCase                      // Train-Case
+	DotCase                        // dot.case
+	PathCase                       // path/case
+	TitleCase                      // Title Case
+	SentenceCase                   // Sentence case
+	LowerCase                      // lower case
+	UpperCase                      // UPPER CASE
+)
+
+func (self Case) String() string {
+	switch self {
+	case CamelCase:
+		return `camelCase`
+	case PascalCase:
+		return `PascalCase`
+	case SnakeCase:
+		return `snake_case`
+	case ScreamingSnakeCase:
+		return `SCREAMING_SNAKE_CASE`
+	case KebabCase:
+		return `kebab-case`
+	case ScreamingKebabCase:
+		return `SCREAMING-KEBAB-CASE`
+	case TrainCase:
+		return `Train-Case`
+	case DotCase:
+		return `dot.case`
+	case PathCase:
+		return `path/case`
+	case TitleCase:
+		return `Title Case`
+	case SentenceCase:
+		return `Sentence case`
+	case LowerCase:
+		return `lower case`
+	case UpperCase:
+		return `UPPER CASE`
+	default:
+		return ``
+	}
+}
+
+// The initialisms recognized by the DefaultCaseConverter.  Initialisms are kept together when
+// tokenizing (e.g. "HTTPServerID" becomes "HTTP", "Server", "ID"), and are rendered in their
+// given form by the capitalizing styles (e.g. "user_id" becomes "UserID").
+var DefaultInitialisms = []string{
+	`ACL`, `API`, `ASCII`, `CPU`, `CSS`, `CSV`, `DNS`, `EOF`, `GUID`, `HTML`, `HTTP`, `HTTPS`, `ID`,
+	`iOS`, `IP`, `IPv4`, `IPv6`, `JSON`, `JWT`, `LHS`, `OAuth`, `QPS`, `RAM`, `RHS`, `RPC`, `SLA`,
+	`SMTP`, `SQL`, `SSH`, `TCP`, `TLS`, `TTL`, `UDP`, `UI`, `UID`, `URI`, `URL`, `UTF8`, `UUID`, `VM`,
+	`XML`, `XMPP`, `XSRF`, `XSS`, `YAML`,
+}
+
+// The CaseConverter used by ToCamelCase, ToSnakeCase, ConvertCase, and related functions.
+var DefaultCaseConverter = NewCaseConverter(DefaultInitialisms...)
+
+// A CaseConverter splits strings into words and reassembles them in a given Case.  A
+// CaseConverter should not be modified while it is being used from multiple goroutines.
+type CaseConverter struct {
+	// If true, transitions between letters and digits are treated as word boundaries (e.g.
+	// "version2" becomes "version", "2").  Otherwise, digits are joined to the preceding word.
+	SplitDigits bool
+
+	initialisms map[string]string
+	maxLength   int
+}
+
+// Returns a new CaseConverter that recognizes the given initialisms.
+func NewCaseConverter(initialisms ...string) *CaseConverter {
+	var converter = &CaseConverter{
+		initialisms: make(map[string]string),
+	}
+
+	converter.AddInitialisms(initialisms...)
+
+	return converter
+}
+
+// Registers one or more initialisms.  The form given is used when rendering the initialism
+// in capitalizing styles, so mixed-case forms like "OAuth" or "iOS" are supported.
+func (self *CaseConverter) AddInitialisms(initialisms ...string) {
+	for _, word := range initialisms {
+		if word == `` {
+			continue
+		}
+
+		self.initialisms[strings.ToUpper(word)] = word
+
+		if n := utf8.RuneCountInString(word); n > self.maxLength {
+			self.maxLength = n
+		}
+	}
+}
+
+// Removes one or more previously-registered initialisms.
+func (self *CaseConverter) RemoveInitialisms(initialisms ...string) {
+	for _, word := range initialisms {
+		delete(self.initialisms, strings.ToUpper(word))
+	}
+}
+
+// Returns whether the given word is a registered initialism (case-insensitive).
+func (self *CaseConverter) IsInitialism(word string) bool {
+	_, ok := self.initialisms[strings.ToUpper(word)]
+	return ok
+}
+
+// Splits the given string into words on separator characters, changes in letter case,
+// known initialisms, and (optionally) transitions between letters and digits.
+func (self *CaseConverter) Tokenize(in string) []string {
+	var words = make([]string, 0)
+
+	for _, chunk := range strings.FieldsFunc(in, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	}) {
+		words = append(words, self.tokenizeChunk([]rune(chunk))...)
+	}
+
+	return words
+}
+
+func (self *CaseConverter) tokenizeChunk(runes []rune) []string {
+	var words = make([]string, 0)
+	var n = len(runes)
+
+	for i := 0; i < n; {
+		var start = i
+
+		if m := self.matchInitialism(runes, i); m > 0 {
+			i += m
+
+			// digits stay attached to initialisms the same way they do to other words (e.g. "HTTP2")
+			if !self.SplitDigits {
+				i = consumeDigits(runes, i)
+			}
+
+			words = append(words, string(runes[start:i]))
+			continue
+		}
+
+		switch r := runes[i]; {
+		case unicode.IsUpper(r) || unicode.IsTitle(r):
+			i++
+
+			if i < n && isCaseLower(runes[i]) {
+				// Capitalized word
+				i = self.consumeLower(runes, i)
+			} else {
+				// run of capitals; the last capital starts a new word if it is followed by lowercase
+				for i < n && unicode.IsUpper(runes[i]) && !(i+1 < n && isCaseLower(runes[i+1])) {
+					i++
+				}
+
+				// a plural "s" after a run of capitals belongs to it (e.g. "PDFs")
+				if i+1 < n && unicode.IsUpper(runes[i]) && runes[i+1] == 's' && (i+2 == n || !isCaseLower(runes[i+2])) {
+					i += 2
+				}
+
+				i = self.consumeMarks(runes, i)
+
+				if !self.SplitDigits {
+					i = consumeDigits(runes, i)
+				}
+			}
+
+		case unicode.IsDigit(r):
+			i = consumeDigits(runes, i)
+
+			if !self.SplitDigits && i < n && isCaseLower(runes[i]) {
+				i = self.consumeLower(runes, i)
+			}
+
+		default:
+			i = self.consumeLower(runes, i)
+		}
+
+		if i == start {
+			i++
+		}
+
+		words = append(words, string(runes[start:i]))
+	}
+
+	return words
+}
+
+// consumes lowercase (or uncased) letters, marks, and (unless splitting digits) digits.
+func (self *CaseConverter) consumeLower(runes []rune, i int) int {
+	for i < len(runes) {
+		if r := runes[i]; isCaseLower(r) || unicode.IsMark(r) {
+			i++
+		} else if unicode.IsDigit(r) && !self.SplitDigits {
+			i++
+		} else {
+			break
+		}
+	}
+
+	return i
+}
+
+func (self *CaseConverter) consumeMarks(runes []rune, i int) int {
+	for i < len(runes) && unicode.IsMark(runes[i]) {
+		i++
+	}
+
+	return i
+}
+
+// returns the length of the longest initialism (in either its registered or all-caps form)
+// starting at position i that ends on a word boundary, including any plural "s".
+func (self *CaseConverter) matchInitialism(runes []rune, i int) int {
+	if !unicode.IsUpper(runes[i]) && !unicode.IsLower(runes[i]) {
+		return 0
+	}
+
+	for m := self.maxLength; m >= 2; m-- {
+		if i+m > len(runes) {
+			continue
+		}
+
+		var candidate = string(runes[i : i+m])
+		var canonical, ok = self.initialisms[strings.ToUpper(candidate)]
+
+		if !ok || (candidate != canonical && candidate != strings.ToUpper(candidate)) {
+			continue
+		}
+
+		// an all-lowercase candidate is just a regular word
+		if candidate == strings.ToLower(candidate) {
+			continue
+		}
+
+		var end = i + m
+
+		switch {
+		case end == len(runes):
+			return m
+		case unicode.IsUpper(runes[end]):
+			// only split a run of capitals if what follows is a capitalized word or another initialism
+			if (end+1 < len(runes) && isCaseLower(runes[end+1])) || self.matchInitialism(runes, end) > 0 {
+				return m
+			}
+		case runes[end] == 's' && (end+1 == len(runes) || !isCaseLower(runes[end+1])):
+			return m + 1
+		case !isCaseLower(runes[end]):
+			return m
+		}
+	}
+
+	return 0
+}
+
+// Converts the given string into the given Case.
+func (self *CaseConverter) Convert(in string, to Case) string {
+	var words = self.Tokenize(in)
+
+	switch to {
+	case CamelCase:
+		for i, word := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(word)
+			} else {
+				words[i] = self.capitalize(word)
+			}
+		}
+
+		return strings.Join(words, ``)
+	case PascalCase:
+		return self.join(words, ``, self.capitalize)
+	case SnakeCase:
+		return self.join(words, `_`, strings.ToLower)
+	case ScreamingSnakeCase:
+		return self.join(words, `_`, strings.ToUpper)
+	case KebabCase:
+		return self.join(words, `-`, strings.ToLower)
+	case ScreamingKebabCase:
+		return self.join(words, `-`, strings.ToUpper)
+	case TrainCase:
+		return self.join(words, `-`, self.capitalize)
+	case DotCase:
+		return self.join(words, `.`, strings.ToLower)
+	case PathCase:
+		return self.join(words, `/`, strings.ToLower)
+	case TitleCase:
+		return self.join(words, ` `, self.capitalize)
+	case SentenceCase:
+		for i, word := range words {
+			if i == 0 {
+				words[i] = self.capitalize(word)
+			} else if canonical := self.initialismForm(word); canonical != `` {
+				words[i] = canonical
+			} else {
+				words[i] = strings.ToLower(word)
+			}
+		}
+
+		return strings.Join(words, ` `)
+	case LowerCase:
+		return self.join(words, ` `, strings.ToLower)
+	case UpperCase:
+		return self.join(words, ` `, strings.ToUpper)
+	default:
+		return in
+	}
+}
+
+func (self *CaseConverter) Camel(in string) string {
+	return self.Convert(in, CamelCase)
+}
+
+func (self *CaseConverter) Pascal(in string) string {
+	return self.Convert(in, PascalCase)
+}
+
+func (self *CaseConverter) Snake(in string) string {
+	return self.Convert(in, SnakeCase)
+}
+
+func (self *CaseConverter) ScreamingSnake(in string) string {
+	return self.Convert(in, ScreamingSnakeCase)
+}
+
+func (self *CaseConverter) Kebab(in string) string {
+	return self.Convert(in, KebabCase)
+}
+
+func (self *CaseConverter) ScreamingKebab(in string) string {
+	return self.Convert(in, ScreamingKebabCase)
+}
+
+func (self *CaseConverter) Train(in string) string {
+	return self.Convert(in, TrainCase)
+}
+
+func (self *CaseConverter) Dot(in string) string {
+	return self.Convert(in, DotCase)
+}
+
+func (self *CaseConverter) Path(in string) string {
+	return self.Convert(in, PathCase)
+}
+
+func (self *CaseConverter) Title(in string) string {
+	return self.Convert(in, TitleCase)
+}
+
+func (self *CaseConverter) Sentence(in string) string {
+	return self.Convert(in, SentenceCase)
+}
+
+func (self *CaseConverter) join(words []string, separator string, fn func(string) string) string {
+	for i, word := range words {
+		words[i] = fn(word)
+	}
+
+	return strings.Join(words, separator)
+}
+
+// returns the registered form of the given word (or its plural) if it is an initialism.
+func (self *CaseConverter) initialismForm(word string) string {
+	var upper = strings.ToUpper(word)
+
+	if canonical, ok := self.initialisms[upper]; ok {
+		return canonical
+	} else if trimmed := strings.TrimRightFunc(word, unicode.IsDigit); trimmed != `` && trimmed != word {
+		// initialisms with digits attached (e.g. "http2" renders as "HTTP2")
+		if canonical := self.initialismForm(trimmed); canonical != `` {
+			return canonical + word[len(trimmed):]
+		}
+	} else if len(word) > 2 && (strings.HasSuffix(word, `s`) || strings.HasSuffix(word, `S`)) {
+		if canonical, ok := self.initialisms[upper[:len(upper)-1]]; ok {
+			return canonical + `s`
+		}
+	}
+
+	return ``
+}
+
+// uppercases the first letter and lowercases the rest, or renders initialisms in their registered form.
+func (self *CaseConverter) capitalize(word string) string {
+	if canonical := self.initialismForm(word); canonical != `` {
+		return canonical
+	}
+
+	var first, size = utf8.DecodeRuneInString(word)
+
+	return string(unicode.ToTitle(first)) + strings.ToLower(word[size:])
+}
+
+func isCaseLower(r rune) bool {
+	return unicode.IsLower(r) || (unicode.IsLetter(r) && !unicode.IsUpper(r) && !unicode.IsTitle(r))
+}
+
+func consumeDigits(runes []rune, i int) int {
+	for i < len(runes) && unicode.IsDigit(runes[i]) {
+		i++
+	}
+
+	return i
+}
+
+// Splits the given value into words using the DefaultCaseConverter.
+func SplitCaseWords(in interface{}) []string {
+	return DefaultCaseConverter.Tokenize(typeutil.String(in))
+}
+
+// Converts the given value into the given Case using the DefaultCaseConverter.
+func ConvertCase(in interface{}, to Case) string {
+	return DefaultCaseConverter.Convert(typeutil.String(in), to)
+}
+
+// Converts the given value to camelCase (e.g. "user_id" becomes "userID").
+func ToCamelCase(in interface{}) string {
+	return ConvertCase(in, CamelCase)
+}
+
+// Converts the given value to PascalCase (e.g. "user_id" becomes "UserID").
+func ToPascalCase(in interface{}) string {
+	return ConvertCase(in, PascalCase)
+}
+
+// Converts the given value to snake_case (e.g. "HTTPServerID" becomes "http_server_id").
+func ToSnakeCase(in interface{}) string {
+	return ConvertCase(in, SnakeCase)
+}
+
+// Converts the given value to SCREAMING_SNAKE_CASE (e.g. "userIDs" becomes "USER_IDS").
+func ToScreamingSnakeCase(in interface{}) string {
+	return ConvertCase(in, ScreamingSnakeCase)
+}
+
+// Converts the given value to kebab-case (e.g. "HTTPServerID" becomes "http-server-id").
+func ToKebabCase(in interface{}) string {
+	return ConvertCase(in, KebabCase)
+}
+
+// Converts the given value to SCREAMING-KEBAB-CASE (e.g. "userIDs" becomes "USER-IDS").
+func ToScreamingKebabCase(in interface{}) string {
+	return ConvertCase(in, ScreamingKebabCase)
+}
+
+// Converts the given value to Train-Case (e.g. "http_server_id" becomes "HTTP-Server-ID").
+func ToTrainCase(in interface{}) string {
+	return ConvertCase(in, TrainCase)
+}
+
+// Converts the given value to dot.case (e.g. "HTTPServerID" becomes "http.server.id").
+func ToDotCase(in interface{}) string {
+	return ConvertCase(in, DotCase)
+}
+
+// Converts the given value to path/case (e.g. "HTTPServerID" becomes "http/server/id").
+func ToPathCase(in interface{}) string {
+	return ConvertCase(in, PathCase)
+}
+
+// Converts the given value to Title Case (e.g. "http_server_id" becomes "HTTP Server ID").
+func ToTitleCase(in interface{}) string {
+	return ConvertCase(in, TitleCase)
+}
+
+// Converts the given value to Sentence case (e.g. "getHTTPResponse" becomes "Get HTTP response").
+func ToSentenceCase(in interface{}) string {
+	return ConvertCase(in, SentenceCase)
+}
@@ -0,0 +1,194 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestSplitCaseWords(t *testing.T) {
+	assert := require.New(t)
+
+	for in, out := range map[string][]string{
+		``:                    {},
+		`hello`:               {`hello`},
+		`helloWorld`:          {`hello`, `World`},
+		`HelloWorld`:          {`Hello`, `World`},
+		`hello_world-again`:   {`hello`, `world`, `again`},
+		`HTTPServerID`:        {`HTTP`, `Server`, `ID`},
+		`userIDs`:             {`user`, `IDs`},
+		`APIURLs`:             {`API`, `URLs`},
+		`JSONData`:            {`JSON`, `Data`},
+		`IDEA`:                {`IDEA`},
+		`ABCServer`:           {`ABC`, `Server`},
+		`version2Beta`:        {`version2`, `Beta`},
+		`utf8String`:          {`utf8`, `String`},
+		`Identity`:            {`Identity`},
+		`  spaced   out  `:    {`spaced`, `out`},
+		`straße_größe`:        {`straße`, `größe`},
+		`ÜberCool`:            {`Über`, `Cool`},
+		`日本語テキスト`:             {`日本語テキスト`},
+		`dots.and/slashes`:    {`dots`, `and`, `slashes`},
+		`TeSt VaLue`:          {`Te`, `St`, `Va`, `Lue`},
+		`getHTTPSConnection2`: {`get`, `HTTPS`, `Connection2`},
+	} {
+		assert.Equal(out, SplitCaseWords(in), in)
+	}
+
+	converter := NewCaseConverter(DefaultInitialisms...)
+	converter.SplitDigits = true
+
+	assert.Equal([]string{`version`, `2`, `Beta`}, converter.Tokenize(`version2Beta`))
+	assert.Equal([]string{`abc`, `123`, `def`}, converter.Tokenize(`abc123def`))
+}
+
+func TestConvertCase(t *testing.T) {
+	assert := require.New(t)
+
+	for in, out := range map[string]map[Case]string{
+		`HTTPServerID`: {
+			CamelCase:          `httpServerID`,
+			PascalCase:         `HTTPServerID`,
+			SnakeCase:          `http_server_id`,
+			ScreamingSnakeCase: `HTTP_SERVER_ID`,
+			KebabCase:          `http-server-id`,
+			ScreamingKebabCase: `HTTP-SERVER-ID`,
+			TrainCase:          `HTTP-Server-ID`,
+			DotCase:            `http.server.id`,
+			PathCase:           `http/server/id`,
+			TitleCase:          `HTTP Server ID`,
+			SentenceCase:       `HTTP server ID`,
+			LowerCase:          `http server id`,
+			UpperCase:          `HTTP SERVER ID`,
+		},
+		`user_ids`: {
+			CamelCase:  `userIDs`,
+			PascalCase: `UserIDs`,
+			SnakeCase:  `user_ids`,
+			TitleCase:  `User IDs`,
+		},
+		`the quick brown fox`: {
+			CamelCase:    `theQuickBrownFox`,
+			PascalCase:   `TheQuickBrownFox`,
+			SentenceCase: `The quick brown fox`,
+			TitleCase:    `The Quick Brown Fox`,
+		},
+		`über_cool`: {
+			PascalCase: `ÜberCool`,
+		},
+	} {
+		for to, want := range out {
+			assert.Equal(want, ConvertCase(in, to), "%v -> %v", in, to)
+		}
+	}
+
+	assert.Equal(`getHTTPResponse`, ToCamelCase(`get_http_response`))
+	assert.Equal(`GetHTTPResponse`, ToPascalCase(`get-http-response`))
+	assert.Equal(`get_http_response`, ToSnakeCase(`getHTTPResponse`))
+	assert.Equal(`USER_IDS`, ToScreamingSnakeCase(`userIDs`))
+	assert.Equal(`user-ids`, ToKebabCase(`UserIDs`))
+	assert.Equal(`USER-IDS`, ToScreamingKebabCase(`userIDs`))
+	assert.Equal(`Content-Type`, ToTrainCase(`content_type`))
+	assert.Equal(`a.b.c`, ToDotCase(`A B C`))
+	assert.Equal(`a/bc`, ToPathCase(`aBC`))
+	assert.Equal(`Get HTTP Response`, ToTitleCase(`getHTTPResponse`))
+	assert.Equal(`Get HTTP response`, ToSentenceCase(`getHTTPResponse`))
+	assert.Equal(`Utf8Test`, NewCaseConverter().Pascal(`utf8_test`))
+	assert.Equal(`UTF8Test`, ToPascalCase(`utf8_test`))
+}
+
+func TestCaseConverterInitialisms(t *testing.T) {
+	assert := require.New(t)
+
+	converter := NewCaseConverter(`ID`, `OAuth`, `iOS`)
+
+	assert.True(converter.IsInitialism(`id`))
+	assert.False(converter.IsInitialism(`HTTP`))
+
+	assert.Equal(`OAuthToken`, converter.Pascal(`oauth_token`))
+	assert.Equal([]string{`OAuth`, `Token`}, converter.Tokenize(`OAuthToken`))
+	assert.Equal(`oauth_token`, converter.Snake(`OAuthToken`))
+	assert.Equal(`iOSAppID`, converter.Pascal(`ios app id`))
+	assert.Equal(`HttpServerID`, converter.Pascal(`http_server_id`))
+
+	converter.AddInitialisms(`HTTP`)
+	assert.Equal(`HTTPServerID`, converter.Pascal(`http_server_id`))
+
+	converter.RemoveInitialisms(`id`)
+	assert.Equal(`HTTPServerId`, converter.Pascal(`http_server_id`))
+	assert.Equal(`Sentence case`, SentenceCase.String())
+}
+
+func TestCaseConverterInitialismDigits(t *testing.T) {
+	assert := require.New(t)
+
+	for in, out := range map[string][]string{
+		`IPv4Address`:    {`IPv4`, `Address`},
+		`ipv6Enabled`:    {`ipv6`, `Enabled`},
+		`remoteIPv6`:     {`remote`, `IPv6`},
+		`IPV4_ADDRESS`:   {`IPV4`, `ADDRESS`},
+		`HTTP2Server`:    {`HTTP2`, `Server`},
+		`version2Beta`:   {`version2`, `Beta`},
+		`OAuth2Token`:    {`OAuth2`, `Token`},
+		`getOAuth2Token`: {`get`, `OAuth2`, `Token`},
+		`UTF8String`:     {`UTF8`, `String`},
+		`UTF16String`:    {`UTF16`, `String`},
+		`PDFs`:           {`PDFs`},
+		`PDFsAndDOCs`:    {`PDFs`, `And`, `DOCs`},
+		`ABs`:            {`ABs`},
+		`PDFsearch`:      {`PD`, `Fsearch`},
+		`iOS`:            {`iOS`},
+		`iOSAppIDs`:      {`iOS`, `App`, `IDs`},
+		`getIOSVersion`:  {`get`, `IOS`, `Version`},
+		`iPhone`:         {`i`, `Phone`},
+	} {
+		assert.Equal(out, SplitCaseWords(in), in)
+	}
+
+	for in, out := range map[string]map[Case]string{
+		`IPv4Address`: {
+			SnakeCase:  `ipv4_address`,
+			PascalCase: `IPv4Address`,
+			CamelCase:  `ipv4Address`,
+		},
+		`HTTP2Server`: {
+			SnakeCase:  `http2_server`,
+			PascalCase: `HTTP2Server`,
+			KebabCase:  `http2-server`,
+		},
+		`version2Beta`: {
+			SnakeCase:  `version2_beta`,
+			PascalCase: `Version2Beta`,
+		},
+		`PDFs`: {
+			SnakeCase:  `pdfs`,
+			PascalCase: `Pdfs`,
+		},
+		`iOS`: {
+			SnakeCase:  `ios`,
+			PascalCase: `iOS`,
+			CamelCase:  `ios`,
+		},
+		`userIDs`: {
+			SnakeCase:  `user_ids`,
+			PascalCase: `UserIDs`,
+		},
+		`oauth2_token`: {
+			SnakeCase:  `oauth2_token`,
+			PascalCase: `OAuth2Token`,
+			TitleCase:  `OAuth2 Token`,
+		},
+	} {
+		for c, expected := range out {
+			assert.Equal(expected, ConvertCase(in, c), in+` `+c.String())
+		}
+	}
+
+	converter := NewCaseConverter(DefaultInitialisms...)
+	converter.SplitDigits = true
+
+	assert.Equal([]string{`IPv4`, `Address`}, converter.Tokenize(`IPv4Address`))
+	assert.Equal([]string{`HTTP`, `2`, `Server`}, converter.Tokenize(`HTTP2Server`))
+	assert.Equal([]string{`version`, `2`, `Beta`}, converter.Tokenize(`version2Beta`))
+	assert.Equal([]string{`OAuth`, `2`, `Token`}, converter.Tokenize(`OAuth2Token`))
+}
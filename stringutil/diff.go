@@ -0,0 +1,602 @@
+package stringutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mgutz/ansi"
+)
+
+// Specifies the units that Diff compares text in.
+type DiffGranularity int
+
+const (
+	DiffLines DiffGranularity = iota // compare whole lines
+	DiffWords                        // compare words, runs of whitespace, and punctuation
+	DiffChars                        // compare characters (grapheme clusters)
+)
+
+// Represents what happened to a run of text between the old and new versions.
+type DiffOperation int
+
+const (
+	DiffEqual DiffOperation = iota
+	DiffDelete
+	DiffInsert
+)
+
+func (self DiffOperation) String() string {
+	switch self {
+	case DiffEqual:
+		return `equal`
+	case DiffDelete:
+		return `delete`
+	case DiffInsert:
+		return `insert`
+	default:
+		return ``
+	}
+}
+
+// The ANSI color expressions used by TextDiff.Inline to highlight deleted and inserted text.
+var DiffDeleteStyle = `red`
+var DiffInsertStyle = `green`
+
+// A run of consecutive tokens that were all kept, deleted, or inserted.
+type DiffEdit struct {
+	Operation DiffOperation
+
+	// The tokens (lines, words, or characters) in this run.
+	Tokens []string
+
+	// The index of the first token of this run in the old and new token lists.  For insertions,
+	// OldIndex is where the tokens were inserted; for deletions, NewIndex is where they were removed.
+	OldIndex int
+	NewIndex int
+}
+
+// Returns the text of all tokens in this run.
+func (self DiffEdit) Text() string {
+	return strings.Join(self.Tokens, ``)
+}
+
+// A group of nearby changes and the unchanged tokens surrounding them, as shown in a unified diff.
+// Starting positions are zero-based token indices.
+type DiffHunk struct {
+	OldStart int
+	OldCount int
+	NewStart int
+	NewCount int
+	Edits    []DiffEdit
+}
+
+// Returns the hunk header in unified diff format (e.g. "@@ -1,4 +1,5 @@").
+func (self DiffHunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", unifiedRange(self.OldStart, self.OldCount), unifiedRange(self.NewStart, self.NewCount))
+}
+
+// The result of comparing two strings.
+type TextDiff struct {
+	Granularity DiffGranularity
+
+	// The edit script that transforms the old text into the new text.  Concatenating the text of
+	// all non-insert edits yields the old text, and of all non-delete edits the new text.
+	Edits []DiffEdit
+
+	old string
+	new string
+}
+
+// Options that control how TextDiff.Unified renders a diff.
+type UnifiedDiffOptions struct {
+	// The number of unchanged lines to show around each change.
+	Context int
+
+	// The file names shown in the "---" and "+++" header lines.  If both are empty, the header
+	// lines are omitted.
+	OldName string
+	NewName string
+}
+
+// Compares two strings using Myers' algorithm, returning the shortest edit script that turns a
+// into b at the given granularity.
+func Diff(a string, b string, granularity DiffGranularity) *TextDiff {
+	var oldTokens = tokenizeDiff(a, granularity)
+	var newTokens = tokenizeDiff(b, granularity)
+
+	// intern tokens so the algorithm compares integers rather than strings
+	var ids = make(map[string]int)
+	var intern = func(tokens []string) []int {
+		var out = make([]int, len(tokens))
+
+		for i, token := range tokens {
+			if id, ok := ids[token]; ok {
+				out[i] = id
+			} else {
+				ids[token] = len(ids)
+				out[i] = len(ids) - 1
+			}
+		}
+
+		return out
+	}
+
+	var d = &myersDiff{}
+
+	d.diff(intern(oldTokens), intern(newTokens))
+
+	var result = &TextDiff{
+		Granularity: granularity,
+		old:         a,
+		new:         b,
+	}
+
+	var i, j int
+
+	for _, op := range d.ops {
+		var token string
+
+		switch op {
+		case DiffDelete:
+			token = oldTokens[i]
+		default:
+			token = newTokens[j]
+		}
+
+		if n := len(result.Edits); n > 0 && result.Edits[n-1].Operation == op {
+			result.Edits[n-1].Tokens = append(result.Edits[n-1].Tokens, token)
+		} else {
+			result.Edits = append(result.Edits, DiffEdit{
+				Operation: op,
+				Tokens:    []string{token},
+				OldIndex:  i,
+				NewIndex:  j,
+			})
+		}
+
+		if op != DiffInsert {
+			i++
+		}
+
+		if op != DiffDelete {
+			j++
+		}
+	}
+
+	return result
+}
+
+// Returns true if the compared strings were identical.
+func (self *TextDiff) Equal() bool {
+	for _, edit := range self.Edits {
+		if edit.Operation != DiffEqual {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Groups the changes in this diff into hunks, each surrounded by up to context unchanged tokens.
+// Changes separated by no more than twice the context are merged into a single hunk.
+func (self *TextDiff) Hunks(context int) []DiffHunk {
+	if context < 0 {
+		context = 0
+	}
+
+	var hunks []DiffHunk
+	var current *DiffHunk
+
+	var appendEdit = func(edit DiffEdit) {
+		if len(edit.Tokens) == 0 {
+			return
+		}
+
+		current.Edits = append(current.Edits, edit)
+
+		if edit.Operation != DiffInsert {
+			current.OldCount += len(edit.Tokens)
+		}
+
+		if edit.Operation != DiffDelete {
+			current.NewCount += len(edit.Tokens)
+		}
+	}
+
+	for e, edit := range self.Edits {
+		if edit.Operation != DiffEqual {
+			if current == nil {
+				current = &DiffHunk{
+					OldStart: edit.OldIndex,
+					NewStart: edit.NewIndex,
+				}
+			}
+
+			appendEdit(edit)
+			continue
+		}
+
+		var n = len(edit.Tokens)
+		var last = e == len(self.Edits)-1
+
+		if current == nil {
+			// leading context for the first change
+			if !last {
+				var lead = minInt(context, n)
+
+				current = &DiffHunk{
+					OldStart: edit.OldIndex + n - lead,
+					NewStart: edit.NewIndex + n - lead,
+				}
+
+				appendEdit(sliceEdit(edit, n-lead, n))
+			}
+		} else if !last && n <= 2*context {
+			// close enough to the next change to keep the hunk going
+			appendEdit(edit)
+		} else {
+			appendEdit(sliceEdit(edit, 0, minInt(context, n)))
+			hunks = append(hunks, *current)
+			current = nil
+
+			if !last {
+				var lead = minInt(context, n)
+
+				current = &DiffHunk{
+					OldStart: edit.OldIndex + n - lead,
+					NewStart: edit.NewIndex + n - lead,
+				}
+
+				appendEdit(sliceEdit(edit, n-lead, n))
+			}
+		}
+	}
+
+	if current != nil {
+		hunks = append(hunks, *current)
+	}
+
+	return hunks
+}
+
+// Renders the diff in unified format.  Unified diffs are line-oriented, so if this diff was made
+// at a finer granularity, the original strings are compared again by line.  If options are not
+// given, 3 lines of context are shown and header lines are omitted.
+func (self *TextDiff) Unified(options *UnifiedDiffOptions) string {
+	if options == nil {
+		options = &UnifiedDiffOptions{
+			Context: 3,
+		}
+	}
+
+	var lines = self
+
+	if self.Granularity != DiffLines {
+		lines = Diff(self.old, self.new, DiffLines)
+	}
+
+	var out strings.Builder
+	var hunks = lines.Hunks(options.Context)
+
+	if len(hunks) == 0 {
+		return ``
+	}
+
+	if options.OldName != `` || options.NewName != `` {
+		out.WriteString(`--- ` + options.OldName + "\n")
+		out.WriteString(`+++ ` + options.NewName + "\n")
+	}
+
+	for _, hunk := range hunks {
+		out.WriteString(hunk.Header() + "\n")
+
+		for _, edit := range hunk.Edits {
+			var prefix = ` `
+
+			switch edit.Operation {
+			case DiffDelete:
+				prefix = `-`
+			case DiffInsert:
+				prefix = `+`
+			}
+
+			for _, line := range edit.Tokens {
+				out.WriteString(prefix + line)
+
+				if !strings.HasSuffix(line, "\n") {
+					out.WriteString("\n\\ No newline at end of file\n")
+				}
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// Renders the new text with deletions and insertions highlighted inline using the ANSI color
+// expressions in DiffDeleteStyle and DiffInsertStyle.
+func (self *TextDiff) Inline() string {
+	var out strings.Builder
+
+	for _, edit := range self.Edits {
+		switch edit.Operation {
+		case DiffDelete:
+			out.WriteString(colorizeDiff(edit.Text(), DiffDeleteStyle))
+		case DiffInsert:
+			out.WriteString(colorizeDiff(edit.Text(), DiffInsertStyle))
+		default:
+			out.WriteString(edit.Text())
+		}
+	}
+
+	return out.String()
+}
+
+// colorizes each line separately so that the styling doesn't bleed across line breaks.
+func colorizeDiff(text string, style string) string {
+	var code = ansi.ColorCode(style)
+	var lines = strings.SplitAfter(text, "\n")
+
+	for i, line := range lines {
+		if body := strings.TrimSuffix(line, "\n"); body != `` {
+			lines[i] = code + body + ansi.Reset + line[len(body):]
+		}
+	}
+
+	return strings.Join(lines, ``)
+}
+
+func sliceEdit(edit DiffEdit, from int, to int) DiffEdit {
+	var out = edit
+
+	out.Tokens = edit.Tokens[from:to]
+	out.OldIndex += from
+	out.NewIndex += from
+
+	return out
+}
+
+// formats a zero-based range the way unified diffs do: one-based, with the count omitted when it is
+// one, and empty ranges referring to the line before them.
+func unifiedRange(start int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+func tokenizeDiff(in string, granularity DiffGranularity) []string {
+	var tokens []string
+
+	switch granularity {
+	case DiffLines:
+		for _, line := range strings.SplitAfter(in, "\n") {
+			if line != `` {
+				tokens = append(tokens, line)
+			}
+		}
+
+	case DiffWords:
+		for len(in) > 0 {
+			var r, n = utf8.DecodeRuneInString(in)
+			var class = diffRuneClass(r)
+
+			// words and whitespace are grouped; everything else stands alone
+			if class != 0 {
+				for n < len(in) {
+					var next, size = utf8.DecodeRuneInString(in[n:])
+
+					if diffRuneClass(next) != class {
+						break
+					}
+
+					n += size
+				}
+			}
+
+			tokens = append(tokens, in[:n])
+			in = in[n:]
+		}
+
+	default:
+		for len(in) > 0 {
+			var n, _ = graphemeLength(in)
+
+			if n <= 0 {
+				_, n = utf8.DecodeRuneInString(in)
+			}
+
+			tokens = append(tokens, in[:n])
+			in = in[n:]
+		}
+	}
+
+	return tokens
+}
+
+func diffRuneClass(r rune) int {
+	switch {
+	case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_':
+		return 1
+	case unicode.IsSpace(r):
+		return 2
+	default:
+		return 0
+	}
+}
+
+// computes an edit script using the linear-space variant of Myers' algorithm, which recursively
+// splits the problem at the middle of an optimal path.
+type myersDiff struct {
+	ops []DiffOperation
+}
+
+func (self *myersDiff) emit(op DiffOperation, count int) {
+	for i := 0; i < count; i++ {
+		self.ops = append(self.ops, op)
+	}
+}
+
+func (self *myersDiff) diff(a []int, b []int) {
+	// strip the common prefix and suffix
+	var prefix, suffix int
+
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	self.emit(DiffEqual, prefix)
+
+	var x, y = a[prefix : len(a)-suffix], b[prefix : len(b)-suffix]
+
+	switch {
+	case len(x) == 0:
+		self.emit(DiffInsert, len(y))
+	case len(y) == 0:
+		self.emit(DiffDelete, len(x))
+	case len(x) == 1 || len(y) == 1:
+		self.single(x, y)
+	default:
+		self.bisect(x, y)
+	}
+
+	self.emit(DiffEqual, suffix)
+}
+
+// handles the case where one side is a single token, which is either somewhere in the other side
+// or not present at all.
+func (self *myersDiff) single(a []int, b []int) {
+	if len(a) == 1 {
+		for j, token := range b {
+			if token == a[0] {
+				self.emit(DiffInsert, j)
+				self.emit(DiffEqual, 1)
+				self.emit(DiffInsert, len(b)-j-1)
+				return
+			}
+		}
+	} else {
+		for i, token := range a {
+			if token == b[0] {
+				self.emit(DiffDelete, i)
+				self.emit(DiffEqual, 1)
+				self.emit(DiffDelete, len(a)-i-1)
+				return
+			}
+		}
+	}
+
+	self.emit(DiffDelete, len(a))
+	self.emit(DiffInsert, len(b))
+}
+
+// finds the middle snake of an optimal path by searching forward from the start and backward
+// from the end simultaneously, then diffs each side of it.
+func (self *myersDiff) bisect(a []int, b []int) {
+	var n, m = len(a), len(b)
+	var maxD = (n + m + 1) / 2
+	var offset = maxD
+	var size = 2*maxD + 2
+	var forward = make([]int, size)
+	var backward = make([]int, size)
+
+	for i := range forward {
+		forward[i] = -1
+		backward[i] = -1
+	}
+
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	var delta = n - m
+	var odd = delta%2 != 0
+	var kStart1, kEnd1, kStart2, kEnd2 int
+
+	for d := 0; d < maxD; d++ {
+		for k := -d + kStart1; k <= d-kEnd1; k += 2 {
+			var i = offset + k
+			var x int
+
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x = forward[i+1]
+			} else {
+				x = forward[i-1] + 1
+			}
+
+			var y = x - k
+
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			forward[i] = x
+
+			if x > n {
+				kEnd1 += 2
+			} else if y > m {
+				kStart1 += 2
+			} else if odd {
+				if j := offset + delta - k; j >= 0 && j < size && backward[j] != -1 && x >= n-backward[j] {
+					self.split(a, b, x, y)
+					return
+				}
+			}
+		}
+
+		for k := -d + kStart2; k <= d-kEnd2; k += 2 {
+			var i = offset + k
+			var x int
+
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x = backward[i+1]
+			} else {
+				x = backward[i-1] + 1
+			}
+
+			var y = x - k
+
+			for x < n && y < m && a[n-x-1] == b[m-y-1] {
+				x++
+				y++
+			}
+
+			backward[i] = x
+
+			if x > n {
+				kEnd2 += 2
+			} else if y > m {
+				kStart2 += 2
+			} else if !odd {
+				if j := offset + delta - k; j >= 0 && j < size && forward[j] != -1 {
+					var fx = forward[j]
+					var fy = offset + fx - j
+
+					if fx >= n-x {
+						self.split(a, b, fx, fy)
+						return
+					}
+				}
+			}
+		}
+	}
+
+	// the sequences have nothing in common
+	self.emit(DiffDelete, n)
+	self.emit(DiffInsert, m)
+}
+
+func (self *myersDiff) split(a []int, b []int, x int, y int) {
+	self.diff(a[:x], b[:y])
+	self.diff(a[x:], b[y:])
+}
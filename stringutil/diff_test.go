@@ -0,0 +1,190 @@
+package stringutil
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+	"github.com/mgutz/ansi"
+)
+
+func diffSides(diff *TextDiff) (string, string) {
+	var a, b strings.Builder
+
+	for _, edit := range diff.Edits {
+		if edit.Operation != DiffInsert {
+			a.WriteString(edit.Text())
+		}
+
+		if edit.Operation != DiffDelete {
+			b.WriteString(edit.Text())
+		}
+	}
+
+	return a.String(), b.String()
+}
+
+func diffCost(diff *TextDiff) int {
+	var cost int
+
+	for _, edit := range diff.Edits {
+		if edit.Operation != DiffEqual {
+			cost += len(edit.Tokens)
+		}
+	}
+
+	return cost
+}
+
+// the minimum number of insertions and deletions, computed via the longest common subsequence.
+func lcsCost(a []string, b []string) int {
+	var table = make([][]int, len(a)+1)
+
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else {
+				table[i][j] = maxInt(table[i+1][j], table[i][j+1])
+			}
+		}
+	}
+
+	return len(a) + len(b) - 2*table[0][0]
+}
+
+func TestDiffChars(t *testing.T) {
+	assert := require.New(t)
+
+	var diff = Diff(`kitten`, `sitting`, DiffChars)
+
+	assert.False(diff.Equal())
+	assert.Equal(5, diffCost(diff))
+
+	a, b := diffSides(diff)
+	assert.Equal(`kitten`, a)
+	assert.Equal(`sitting`, b)
+
+	assert.True(Diff(`same`, `same`, DiffChars).Equal())
+	assert.Empty(Diff(``, ``, DiffChars).Edits)
+
+	// combining marks stay with the character they modify
+	diff = Diff(`cafe`, "cafe\u0301", DiffChars)
+	assert.Len(diff.Edits, 3)
+	assert.Equal([]string{`c`, `a`, `f`}, diff.Edits[0].Tokens)
+	assert.Equal([]string{`e`}, diff.Edits[1].Tokens)
+	assert.Equal([]string{"e\u0301"}, diff.Edits[2].Tokens)
+}
+
+func TestDiffWords(t *testing.T) {
+	assert := require.New(t)
+
+	var diff = Diff(`the quick brown fox.`, `the slow brown fox!`, DiffWords)
+
+	assert.Equal([]DiffEdit{
+		{Operation: DiffEqual, Tokens: []string{`the`, ` `}, OldIndex: 0, NewIndex: 0},
+		{Operation: DiffDelete, Tokens: []string{`quick`}, OldIndex: 2, NewIndex: 2},
+		{Operation: DiffInsert, Tokens: []string{`slow`}, OldIndex: 3, NewIndex: 2},
+		{Operation: DiffEqual, Tokens: []string{` `, `brown`, ` `, `fox`}, OldIndex: 3, NewIndex: 3},
+		{Operation: DiffDelete, Tokens: []string{`.`}, OldIndex: 7, NewIndex: 7},
+		{Operation: DiffInsert, Tokens: []string{`!`}, OldIndex: 8, NewIndex: 7},
+	}, diff.Edits)
+
+	assert.Equal(
+		`the `+ansi.ColorCode(`red`)+`quick`+ansi.Reset+ansi.ColorCode(`green`)+`slow`+ansi.Reset+
+			` brown fox`+ansi.ColorCode(`red`)+`.`+ansi.Reset+ansi.ColorCode(`green`)+`!`+ansi.Reset,
+		diff.Inline(),
+	)
+}
+
+func TestDiffMinimal(t *testing.T) {
+	assert := require.New(t)
+
+	var rng = rand.New(rand.NewSource(42))
+	var random = func() string {
+		var out = make([]byte, rng.Intn(30))
+
+		for i := range out {
+			out[i] = "abcd\n"[rng.Intn(5)]
+		}
+
+		return string(out)
+	}
+
+	for i := 0; i < 500; i++ {
+		var a, b = random(), random()
+
+		for _, granularity := range []DiffGranularity{DiffChars, DiffLines} {
+			var diff = Diff(a, b, granularity)
+			var oldSide, newSide = diffSides(diff)
+
+			assert.Equal(a, oldSide)
+			assert.Equal(b, newSide)
+			assert.Equal(
+				lcsCost(tokenizeDiff(a, granularity), tokenizeDiff(b, granularity)),
+				diffCost(diff),
+				"%q -> %q",
+				a,
+				b,
+			)
+		}
+	}
+}
+
+func TestDiffUnified(t *testing.T) {
+	assert := require.New(t)
+
+	var a = "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	var b = "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\n16\n"
+	var diff = Diff(a, b, DiffLines)
+
+	assert.Equal(""+
+		"--- a.txt\n"+
+		"+++ b.txt\n"+
+		"@@ -1,6 +1,6 @@\n"+
+		" 1\n"+
+		" 2\n"+
+		"-3\n"+
+		"+three\n"+
+		" 4\n"+
+		" 5\n"+
+		" 6\n"+
+		"@@ -11,5 +11,5 @@\n"+
+		" 11\n"+
+		" 12\n"+
+		" 13\n"+
+		"-14\n"+
+		" 15\n"+
+		"+16\n", diff.Unified(&UnifiedDiffOptions{
+		Context: 3,
+		OldName: `a.txt`,
+		NewName: `b.txt`,
+	}))
+
+	assert.Equal(""+
+		"@@ -3 +3 @@\n"+
+		"-3\n"+
+		"+three\n"+
+		"@@ -14 +13,0 @@\n"+
+		"-14\n"+
+		"@@ -15,0 +15 @@\n"+
+		"+16\n", diff.Unified(&UnifiedDiffOptions{}))
+
+	// hunks merge when the gap is within twice the context
+	assert.Len(diff.Hunks(6), 1)
+	assert.Len(diff.Hunks(3), 2)
+
+	assert.Equal(""+
+		"@@ -1 +1 @@\n"+
+		"-a\n"+
+		"\\ No newline at end of file\n"+
+		"+b\n"+
+		"\\ No newline at end of file\n", Diff(`a`, `b`, DiffWords).Unified(nil))
+
+	assert.Equal(``, Diff("x\n", "x\n", DiffLines).Unified(nil))
+}
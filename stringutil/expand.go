@@ -0,0 +1,537 @@
+package stringutil
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var rxEnvLegacySuffix = regexp.MustCompile(`(?s)^(?:\|(?P<fallback>.*?))?(?::(?P<fmt>%[^\}]+))?$`) // |fallback, :%04s, |fallback:%04s
+
+// A function that returns the value of the named variable, and whether it is set.
+type LookupFunc func(name string) (string, bool)
+
+// Options that control how variables are resolved and expanded by ExpandOptions.Expand.
+type ExpandOptions struct {
+	// Used to resolve variable values. Defaults to os.LookupEnv.
+	Lookup LookupFunc
+
+	// Used to assign values with the ${VAR=word} and ${VAR:=word} forms.  If not set, values are
+	// assigned with os.Setenv when Lookup is also unset; otherwise assignments are only visible for
+	// the remainder of the expansion.
+	Assign func(name string, value string) error
+
+	// If true, referencing a variable that is not set (and that has no default) is an error.
+	Strict bool
+
+	// If true, ${...} sequences that would expand to an empty string are left as-is.
+	PreserveIfEmpty bool
+}
+
+// Return the given string with environment variable substitution sequences
+// expanded and (optionally) formatted.  This function operates similarly to
+// os.ExpandEnv, but accepts custom fmt.Printf formatting directives.
+//
+// In addition to ${ENV}, ${ENV|fallback}, ${ENV:%fmt}, and ${ENV|fallback:%fmt}, the POSIX shell
+// parameter expansion forms are supported (see ExpandOptions.Expand).  Errors (such as from
+// ${ENV:?message}) are ignored; use ExpandOptions to detect them.
+func ExpandEnv(in string) string {
+	out, _ := ExpandOptions{
+		PreserveIfEmpty: ExpandEnvPreserveIfEmpty,
+	}.Expand(in)
+
+	return out
+}
+
+// Expands ${...} sequences in the given string.  The following forms are supported:
+//
+//	${VAR}            the value of VAR
+//	${VAR|fallback}   the value of VAR, or fallback if VAR is empty
+//	${VAR:%fmt}       the value of VAR, formatted with fmt.Sprintf
+//	${VAR:-word}      the value of VAR, or word if VAR is unset or empty
+//	${VAR:=word}      same as :-, but also assigns word to VAR
+//	${VAR:?message}   the value of VAR, or an error with the given message if VAR is unset or empty
+//	${VAR:+word}      word if VAR is set and non-empty, otherwise nothing
+//	${#VAR}           the length of the value of VAR
+//	${VAR:offset}     the value of VAR starting at offset (negative offsets count from the end)
+//	${VAR:offset:len} len characters of the value of VAR starting at offset
+//	${VAR#pattern}    the value of VAR with the shortest prefix matching pattern removed (## for longest)
+//	${VAR%pattern}    the value of VAR with the shortest suffix matching pattern removed (%% for longest)
+//	${VAR/pat/repl}   the value of VAR with the first match of pat replaced (// for all, /# and /% to anchor)
+//
+// As with the shell, omitting the colon in the :-, :=, :?, and :+ forms only tests whether VAR is
+// set, and patterns may use *, ?, and [...] wildcards.  Words may themselves contain ${...}
+// sequences.  The first three forms retain the behavior of earlier versions of ExpandEnv: values
+// are passed through Autotype before being formatted, and a fallback applies if VAR is empty.
+//
+// Sequences that fail to expand are removed from the output, and the first such error is returned
+// along with the rest of the expanded string.
+func (self ExpandOptions) Expand(in string) (string, error) {
+	var exp = &expander{
+		ExpandOptions: self,
+		assigned:      make(map[string]string),
+	}
+
+	var out = exp.expand(in)
+
+	if exp.err == nil && len(exp.unset) > 0 {
+		exp.err = fmt.Errorf("unset variables: %s", strings.Join(exp.unset, `, `))
+	}
+
+	return out, exp.err
+}
+
+type expander struct {
+	ExpandOptions
+	assigned map[string]string
+	unset    []string
+	err      error
+}
+
+// expands all sequences in the given string.  Sequences that fail to expand are removed, and the
+// first error encountered is retained.
+func (self *expander) expand(in string) string {
+	var out strings.Builder
+
+	for {
+		var start = strings.Index(in, `${`)
+
+		if start < 0 {
+			out.WriteString(in)
+			break
+		}
+
+		out.WriteString(in[:start])
+		in = in[start:]
+
+		var end = closingBrace(in)
+
+		if end < 0 {
+			out.WriteString(in)
+			break
+		}
+
+		var seq = in[:end+1]
+
+		in = in[end+1:]
+
+		if value, err := self.expandSequence(seq); err != nil {
+			if self.err == nil {
+				self.err = err
+			}
+		} else {
+			out.WriteString(value)
+		}
+	}
+
+	return out.String()
+}
+
+// returns the index of the brace that closes the ${ at the start of the given string, accounting
+// for nested sequences and backslash escapes.
+func closingBrace(in string) int {
+	var depth int
+
+	for i := 0; i < len(in); i++ {
+		switch in[i] {
+		case '\\':
+			i++
+		case '$':
+			if i+1 < len(in) && in[i+1] == '{' {
+				depth++
+				i++
+			}
+		case '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func (self *expander) lookup(name string) (string, bool) {
+	if value, ok := self.assigned[name]; ok {
+		return value, true
+	} else if self.Lookup != nil {
+		return self.Lookup(name)
+	} else {
+		return os.LookupEnv(name)
+	}
+}
+
+func (self *expander) assign(name string, value string) error {
+	if self.Assign != nil {
+		return self.Assign(name, value)
+	} else if self.Lookup == nil {
+		return os.Setenv(name, value)
+	}
+
+	self.assigned[name] = value
+	return nil
+}
+
+func (self *expander) markUnset(name string) {
+	for _, n := range self.unset {
+		if n == name {
+			return
+		}
+	}
+
+	self.unset = append(self.unset, name)
+}
+
+func (self *expander) expandSequence(seq string) (string, error) {
+	var body = seq[2 : len(seq)-1]
+
+	// ${#VAR}
+	if len(body) > 1 && body[0] == '#' && isVariableName(body[1:]) {
+		var name = body[1:]
+		var value, ok = self.lookup(name)
+
+		if !ok && self.Strict {
+			self.markUnset(name)
+		}
+
+		return strconv.Itoa(utf8.RuneCountInString(value)), nil
+	}
+
+	var nameLen int
+
+	for nameLen < len(body) && isVariableNameByte(body[nameLen]) {
+		nameLen++
+	}
+
+	// leave anything we don't understand (e.g. shell special parameters) as-is
+	if nameLen == 0 {
+		return seq, nil
+	}
+
+	var name, op = body[:nameLen], body[nameLen:]
+	var value, isSet = self.lookup(name)
+	var result string
+
+	if match := rxEnvLegacySuffix.FindStringSubmatch(op); match != nil {
+		return self.expandLegacy(name, value, isSet, match[1], match[2])
+	}
+
+	// the colon forms treat empty values the same as unset ones
+	var colon = strings.HasPrefix(op, `:`) && len(op) > 1 && strings.ContainsRune(`-=?+`, rune(op[1]))
+	var present = isSet
+
+	if colon {
+		op = op[1:]
+		present = isSet && value != ``
+	}
+
+	// words are only expanded if they are used
+	var word = op[1:]
+
+	switch op[0] {
+	case '-':
+		result = value
+
+		if !present {
+			result = self.expand(word)
+		}
+	case '=':
+		result = value
+
+		if !present {
+			result = self.expand(word)
+
+			if err := self.assign(name, result); err != nil {
+				return ``, err
+			}
+		}
+	case '?':
+		if !present {
+			var message = self.expand(word)
+
+			if message == `` {
+				message = `parameter null or not set`
+			}
+
+			return ``, fmt.Errorf("%s: %s", name, message)
+		}
+
+		result = value
+	case '+':
+		if present {
+			result = self.expand(word)
+		}
+	default:
+		if !isSet && self.Strict {
+			self.markUnset(name)
+		}
+
+		var err error
+
+		switch op[0] {
+		case ':':
+			result, err = substring(value, op[1:])
+		case '#', '%':
+			result, err = self.removePattern(value, op)
+		case '/':
+			result, err = self.replacePattern(value, op[1:])
+		default:
+			return seq, nil
+		}
+
+		if err != nil {
+			return ``, err
+		}
+	}
+
+	if result == `` && self.PreserveIfEmpty {
+		return seq, nil
+	}
+
+	return result, nil
+}
+
+// handles the ${VAR}, ${VAR|fallback}, and ${VAR:%fmt} forms.
+func (self *expander) expandLegacy(name string, value string, isSet bool, fallback string, format string) (string, error) {
+	if format == `` {
+		format = `%v`
+	}
+
+	var typed interface{}
+
+	if value != `` {
+		typed = Autotype(value)
+	} else {
+		if !isSet && fallback == `` && self.Strict {
+			self.markUnset(name)
+		}
+
+		typed = Autotype(self.expand(fallback))
+	}
+
+	if typed != nil {
+		return fmt.Sprintf(format, typed), nil
+	} else if self.PreserveIfEmpty {
+		return fmt.Sprintf(format, `${`+name+`}`), nil
+	} else {
+		return fmt.Sprintf(format, ``), nil
+	}
+}
+
+// implements ${VAR:offset} and ${VAR:offset:length}.
+func substring(value string, spec string) (string, error) {
+	var runes = []rune(value)
+	var parts = strings.SplitN(spec, `:`, 2)
+	var offset, length = 0, len(runes)
+
+	// unlike the length, the offset may not be omitted
+	if strings.TrimSpace(parts[0]) == `` {
+		return ``, fmt.Errorf("missing substring offset")
+	}
+
+	if v, err := parseSubstringInt(parts[0]); err == nil {
+		offset = v
+	} else {
+		return ``, err
+	}
+
+	if offset < 0 {
+		offset += len(runes)
+	}
+
+	if offset < 0 || offset > len(runes) {
+		return ``, nil
+	}
+
+	if len(parts) > 1 {
+		if v, err := parseSubstringInt(parts[1]); err == nil {
+			length = v
+		} else {
+			return ``, err
+		}
+
+		// negative lengths are an offset from the end of the value
+		if length < 0 {
+			length = len(runes) + length - offset
+
+			if length < 0 {
+				return ``, fmt.Errorf("%s: substring expression < 0", parts[1])
+			}
+		}
+	}
+
+	if offset+length > len(runes) {
+		length = len(runes) - offset
+	}
+
+	return string(runes[offset : offset+length]), nil
+}
+
+func parseSubstringInt(in string) (int, error) {
+	in = strings.TrimSpace(in)
+
+	if strings.HasPrefix(in, `(`) && strings.HasSuffix(in, `)`) {
+		in = strings.TrimSpace(in[1 : len(in)-1])
+	}
+
+	if in == `` {
+		return 0, nil
+	} else if v, err := strconv.Atoi(in); err == nil {
+		return v, nil
+	} else {
+		return 0, fmt.Errorf("invalid substring expression %q", in)
+	}
+}
+
+// implements ${VAR#pattern}, ${VAR##pattern}, ${VAR%pattern}, and ${VAR%%pattern}.
+func (self *expander) removePattern(value string, op string) (string, error) {
+	var suffix = op[0] == '%'
+	var longest = len(op) > 1 && op[1] == op[0]
+
+	if longest {
+		op = op[2:]
+	} else {
+		op = op[1:]
+	}
+
+	rx, err := globToRegexp(self.expand(op), true, true)
+
+	if err != nil {
+		return ``, err
+	}
+
+	var runes = []rune(value)
+
+	for n := 0; n <= len(runes); n++ {
+		var size = n
+
+		if longest {
+			size = len(runes) - n
+		}
+
+		if suffix {
+			if rx.MatchString(string(runes[len(runes)-size:])) {
+				return string(runes[:len(runes)-size]), nil
+			}
+		} else if rx.MatchString(string(runes[:size])) {
+			return string(runes[size:]), nil
+		}
+	}
+
+	return value, nil
+}
+
+// implements ${VAR/pattern/replacement} and the //, /#, and /% variants.
+func (self *expander) replacePattern(value string, op string) (string, error) {
+	var all, anchorStart, anchorEnd bool
+
+	if op != `` {
+		switch op[0] {
+		case '/':
+			all = true
+			op = op[1:]
+		case '#':
+			anchorStart = true
+			op = op[1:]
+		case '%':
+			anchorEnd = true
+			op = op[1:]
+		}
+	}
+
+	var pattern, replacement = op, ``
+
+	for i := 0; i < len(op); i++ {
+		if op[i] == '\\' {
+			i++
+		} else if op[i] == '/' {
+			pattern, replacement = op[:i], op[i+1:]
+			break
+		}
+	}
+
+	pattern, replacement = self.expand(pattern), self.expand(replacement)
+
+	if pattern == `` {
+		return value, nil
+	}
+
+	rx, err := globToRegexp(pattern, anchorStart, anchorEnd)
+
+	if err != nil {
+		return ``, err
+	}
+
+	if all {
+		return rx.ReplaceAllLiteralString(value, replacement), nil
+	} else if loc := rx.FindStringIndex(value); loc != nil {
+		return value[:loc[0]] + replacement + value[loc[1]:], nil
+	}
+
+	return value, nil
+}
+
+// converts a shell wildcard pattern into a regular expression.
+func globToRegexp(pattern string, anchorStart bool, anchorEnd bool) (*regexp.Regexp, error) {
+	var rx strings.Builder
+
+	rx.WriteString(`(?s)`)
+
+	if anchorStart {
+		rx.WriteString(`^`)
+	}
+
+	var runes = []rune(pattern)
+
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			rx.WriteString(`.*`)
+		case '?':
+			rx.WriteString(`.`)
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				rx.WriteString(regexp.QuoteMeta(string(runes[i])))
+			}
+		case '[':
+			if end := strings.IndexRune(string(runes[i+1:]), ']'); end >= 0 {
+				var class = string(runes[i+1:])[:end]
+
+				if strings.HasPrefix(class, `!`) {
+					class = `^` + class[1:]
+				}
+
+				rx.WriteString(`[` + strings.ReplaceAll(class, `\`, `\\`) + `]`)
+				i += utf8.RuneCountInString(class) + 1
+			} else {
+				rx.WriteString(`\[`)
+			}
+		default:
+			rx.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if anchorEnd {
+		rx.WriteString(`$`)
+	}
+
+	return regexp.Compile(rx.String())
+}
+
+func isVariableName(in string) bool {
+	for i := 0; i < len(in); i++ {
+		if !isVariableNameByte(in[i]) {
+			return false
+		}
+	}
+
+	return in != ``
+}
+
+func isVariableNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,163 @@
+package stringutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestExpandOptions(t *testing.T) {
+	assert := require.New(t)
+
+	vars := map[string]string{
+		`NAME`:  `world`,
+		`EMPTY`: ``,
+		`PATH`:  `/usr/local/bin/tool.tar.gz`,
+		`NUM`:   `7`,
+	}
+
+	opts := ExpandOptions{
+		Lookup: func(name string) (string, bool) {
+			v, ok := vars[name]
+			return v, ok
+		},
+	}
+
+	for in, out := range map[string]string{
+		`hello ${NAME}`:             `hello world`,
+		`${UNSET:-default}`:         `default`,
+		`${EMPTY:-default}`:         `default`,
+		`${EMPTY-default}`:          ``,
+		`${UNSET-default}`:          `default`,
+		`${NAME:-default}`:          `world`,
+		`${UNSET:-${NAME}}`:         `world`,
+		`${UNSET:-${ALSO:-nested}}`: `nested`,
+		`${NAME:+alt}`:              `alt`,
+		`${EMPTY:+alt}`:             ``,
+		`${EMPTY+alt}`:              `alt`,
+		`${UNSET+alt}`:              ``,
+		`${#NAME}`:                  `5`,
+		`${#UNSET}`:                 `0`,
+		`${NAME:1}`:                 `orld`,
+		`${NAME:1:3}`:               `orl`,
+		`${NAME: -3}`:               `rld`,
+		`${NAME:(-3):2}`:            `rl`,
+		`${NAME:1:-1}`:              `orl`,
+		`${NAME:10}`:                ``,
+		`${PATH#*/}`:                `usr/local/bin/tool.tar.gz`,
+		`${PATH##*/}`:               `tool.tar.gz`,
+		`${PATH%.*}`:                `/usr/local/bin/tool.tar`,
+		`${PATH%%.*}`:               `/usr/local/bin/tool`,
+		`${PATH%.zip}`:              `/usr/local/bin/tool.tar.gz`,
+		`${PATH/local/share}`:       `/usr/share/bin/tool.tar.gz`,
+		`${PATH//\//:}`:             `:usr:local:bin:tool.tar.gz`,
+		`${PATH/#\/usr/~}`:          `~/local/bin/tool.tar.gz`,
+		`${PATH/%gz/bz2}`:           `/usr/local/bin/tool.tar.bz2`,
+		`${PATH/[lb]*\//}`:          `/usr/tool.tar.gz`,
+		`${NAME/o}`:                 `wrld`,
+
+		// legacy forms still autotype and format values
+		`${NUM}`:              `7`,
+		`${NUM:%03d}`:         `007`,
+		`${EMPTY|fallback}`:   `fallback`,
+		`${UNSET|1.5:%.2f}`:   `1.50`,
+		`${UNSET|${NAME}}`:    `world`,
+		`cost: ${@} ${!x} ${`: `cost: ${@} ${!x} ${`,
+	} {
+		actual, err := opts.Expand(in)
+		assert.NoError(err, in)
+		assert.Equal(out, actual, in)
+	}
+}
+
+func TestExpandOptionsAssign(t *testing.T) {
+	assert := require.New(t)
+
+	vars := map[string]string{}
+	opts := ExpandOptions{
+		Lookup: func(name string) (string, bool) {
+			v, ok := vars[name]
+			return v, ok
+		},
+	}
+
+	out, err := opts.Expand(`${X:=first} ${X:=second} ${X}`)
+	assert.NoError(err)
+	assert.Equal(`first first first`, out)
+	assert.Empty(vars)
+
+	opts.Assign = func(name string, value string) error {
+		vars[name] = value
+		return nil
+	}
+
+	out, err = opts.Expand(`${X:=assigned}`)
+	assert.NoError(err)
+	assert.Equal(`assigned`, out)
+	assert.Equal(`assigned`, vars[`X`])
+
+	// with no lookup function, the environment is updated
+	os.Unsetenv(`GOSTOCKUTIL_TEST_ASSIGN`)
+	defer os.Unsetenv(`GOSTOCKUTIL_TEST_ASSIGN`)
+
+	out, err = ExpandOptions{}.Expand(`${GOSTOCKUTIL_TEST_ASSIGN:=yes}`)
+	assert.NoError(err)
+	assert.Equal(`yes`, out)
+	assert.Equal(`yes`, os.Getenv(`GOSTOCKUTIL_TEST_ASSIGN`))
+}
+
+func TestExpandOptionsErrors(t *testing.T) {
+	assert := require.New(t)
+
+	opts := ExpandOptions{
+		Lookup: func(name string) (string, bool) {
+			if name == `SET` {
+				return `value`, true
+			}
+
+			return ``, false
+		},
+	}
+
+	out, err := opts.Expand(`a ${UNSET:?must be set} b`)
+	assert.EqualError(err, `UNSET: must be set`)
+	assert.Equal(`a  b`, out)
+
+	_, err = opts.Expand(`${UNSET?}`)
+	assert.EqualError(err, `UNSET: parameter null or not set`)
+
+	// the substring offset is required, but the length may be empty
+	_, err = opts.Expand(`${SET:}`)
+	assert.EqualError(err, `missing substring offset`)
+
+	_, err = opts.Expand(`${SET: :2}`)
+	assert.EqualError(err, `missing substring offset`)
+
+	out, err = opts.Expand(`[${SET:1:}]`)
+	assert.NoError(err)
+	assert.Equal(`[]`, out)
+
+	// the word is only expanded when it's needed
+	out, err = opts.Expand(`${SET:-${UNSET:?nope}}`)
+	assert.NoError(err)
+	assert.Equal(`value`, out)
+
+	out, err = opts.Expand(`${SET} ${UNSET} ${UNSET:-ok} ${OTHER:2} ${UNSET}`)
+	assert.NoError(err)
+	assert.Equal(`value  ok  `, out)
+
+	opts.Strict = true
+
+	out, err = opts.Expand(`${SET} ${UNSET} ${UNSET:-ok} ${OTHER:2} ${UNSET}`)
+	assert.EqualError(err, `unset variables: UNSET, OTHER`)
+	assert.Equal(`value  ok  `, out)
+
+	// preserving empty sequences
+	opts.Strict = false
+	opts.PreserveIfEmpty = true
+
+	out, err = opts.Expand(`${SET} ${UNSET} ${UNSET:%5s} ${UNSET#x} ${UNSET:-ok}`)
+	assert.NoError(err)
+	assert.Equal(`value ${UNSET} ${UNSET} ${UNSET#x} ok`, out)
+}
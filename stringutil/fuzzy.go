@@ -0,0 +1,506 @@
+package stringutil
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Returns the Levenshtein edit distance between two strings: the minimum number of single-character
+// insertions, deletions, and substitutions required to change one into the other.
+func Levenshtein(a, b string) int {
+	var ra, rb = []rune(a), []rune(b)
+
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	var prev = make([]int, len(rb)+1)
+	var cur = make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			var cost = 1
+
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// Returns the Damerau-Levenshtein edit distance between two strings.  This is the same as the
+// Levenshtein distance, except that transpositions of two adjacent characters count as a single
+// edit.
+func DamerauLevenshtein(a, b string) int {
+	var ra, rb = []rune(a), []rune(b)
+	var inf = len(ra) + len(rb)
+	var lastRow = make(map[rune]int)
+	var d = make([][]int, len(ra)+2)
+
+	for i := range d {
+		d[i] = make([]int, len(rb)+2)
+		d[i][0] = inf
+
+		if i > 0 {
+			d[i][1] = i - 1
+		}
+	}
+
+	for j := 1; j < len(rb)+2; j++ {
+		d[0][j] = inf
+		d[1][j] = j - 1
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		var lastCol = 0
+
+		for j := 1; j <= len(rb); j++ {
+			var k, l = lastRow[rb[j-1]], lastCol
+			var cost = 1
+
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+				lastCol = j
+			}
+
+			d[i+1][j+1] = minInt(
+				d[i][j]+cost,
+				d[i+1][j]+1,
+				d[i][j+1]+1,
+				d[k][l]+(i-k-1)+1+(j-l-1),
+			)
+		}
+
+		lastRow[ra[i-1]] = i
+	}
+
+	return d[len(ra)+1][len(rb)+1]
+}
+
+// Returns the Jaro similarity between two strings, from 0.0 (no similarity) to 1.0 (identical).
+func Jaro(a, b string) float64 {
+	var ra, rb = []rune(a), []rune(b)
+
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	} else if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	var window = maxInt(len(ra), len(rb))/2 - 1
+
+	if window < 0 {
+		window = 0
+	}
+
+	var matchedA = make([]bool, len(ra))
+	var matchedB = make([]bool, len(rb))
+	var matches int
+
+	for i := range ra {
+		var lo, hi = maxInt(0, i-window), minInt(len(rb)-1, i+window)
+
+		for j := lo; j <= hi; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i] = true
+				matchedB[j] = true
+				matches++
+				break
+			}
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	var transpositions, j int
+
+	for i := range ra {
+		if matchedA[i] {
+			for !matchedB[j] {
+				j++
+			}
+
+			if ra[i] != rb[j] {
+				transpositions++
+			}
+
+			j++
+		}
+	}
+
+	var m = float64(matches)
+
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// Returns the Jaro-Winkler similarity between two strings, from 0.0 (no similarity) to
+// 1.0 (identical).  This is the Jaro similarity, adjusted upwards for strings that share a
+// common prefix of up to four characters.
+func JaroWinkler(a, b string) float64 {
+	var sim = Jaro(a, b)
+	var ra, rb = []rune(a), []rune(b)
+	var prefix int
+
+	for prefix < 4 && prefix < len(ra) && prefix < len(rb) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return sim + float64(prefix)*0.1*(1-sim)
+}
+
+// Returns the set of case-insensitive three-character sequences in the given string.  Each word
+// is padded with two leading spaces and one trailing space, so "cat" yields "  c", " ca", "cat",
+// and "at ".
+func Trigrams(in string) map[string]bool {
+	var trigrams = make(map[string]bool)
+
+	for _, word := range strings.FieldsFunc(strings.ToLower(in), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		var runes = []rune(`  ` + word + ` `)
+
+		for i := 0; i+3 <= len(runes); i++ {
+			trigrams[string(runes[i:i+3])] = true
+		}
+	}
+
+	return trigrams
+}
+
+// Returns the similarity of two strings from 0.0 to 1.0 based on the number of trigrams they share
+// (the Jaccard index of their trigram sets).
+func TrigramSimilarity(a, b string) float64 {
+	var ta, tb = Trigrams(a), Trigrams(b)
+	var shared int
+
+	if len(ta) == 0 && len(tb) == 0 {
+		return 0
+	}
+
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// Options that control how FuzzyFind matches and ranks candidates.
+type FuzzyOptions struct {
+	// Match characters case-sensitively.
+	CaseSensitive bool
+
+	// Only return matches with at least this score.
+	MinScore float64
+
+	// Return at most this many matches (0 for all).
+	Limit int
+
+	// If greater than zero, candidates that don't contain the query characters in order may
+	// still match if they are within this many edits (including transpositions) of the query.
+	MaxTypos int
+}
+
+// A candidate that matched a FuzzyFind query.
+type FuzzyMatch struct {
+	// The matched candidate string.
+	Value string
+
+	// The index of the candidate in the list that was searched.
+	Index int
+
+	// How well the candidate matched, from 0.0 to 1.0.
+	Score float64
+
+	// The (rune) offsets of the characters in Value that matched the query, suitable for
+	// highlighting.
+	Positions []int
+}
+
+// relative weights used when scoring fuzzy matches
+const (
+	fuzzyMatchScore       = 20
+	fuzzyBoundaryBonus    = 16
+	fuzzyConsecutiveBonus = 12
+	fuzzyGapPenalty       = 1
+)
+
+// Searches the given candidates for those that fuzzily match the query, returning matches ordered
+// from best to worst.  A candidate matches if it contains all of the characters of the query in
+// order; matches that occur at the start of words, and runs of consecutive characters, score
+// higher.  If opts.MaxTypos is set, candidates that are a few edits away from the query
+// (e.g. misspellings) are also returned, with lower scores.
+func FuzzyFind(query string, candidates []string, opts *FuzzyOptions) []FuzzyMatch {
+	if opts == nil {
+		opts = new(FuzzyOptions)
+	}
+
+	var q = []rune(query)
+	var matches = make([]FuzzyMatch, 0)
+	var buf []rune
+
+	if !opts.CaseSensitive {
+		for i, r := range q {
+			q[i] = unicode.ToLower(r)
+		}
+	}
+
+	for index, candidate := range candidates {
+		buf = buf[:0]
+
+		for _, r := range candidate {
+			buf = append(buf, r)
+		}
+
+		var score, positions = fuzzySubsequence(q, buf, opts.CaseSensitive)
+
+		if positions == nil && opts.MaxTypos > 0 {
+			score, positions = fuzzyTypos(q, buf, opts.CaseSensitive, opts.MaxTypos)
+		}
+
+		if positions != nil && score >= opts.MinScore {
+			matches = append(matches, FuzzyMatch{
+				Value:     candidate,
+				Index:     index,
+				Score:     score,
+				Positions: positions,
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+
+		return len(matches[i].Value) < len(matches[j].Value)
+	})
+
+	if opts.Limit > 0 && len(matches) > opts.Limit {
+		matches = matches[:opts.Limit]
+	}
+
+	return matches
+}
+
+func fuzzyRuneEqual(q rune, c rune, caseSensitive bool) bool {
+	if caseSensitive || c < 0x80 && (c < 'A' || c > 'Z') {
+		return q == c
+	}
+
+	return q == unicode.ToLower(c)
+}
+
+// scores the candidate if it contains the query as a subsequence.  A forward scan finds the
+// earliest point at which the whole query has matched, and a backward scan from there finds the
+// tightest window containing it; positions are then chosen within that window.
+func fuzzySubsequence(q []rune, c []rune, caseSensitive bool) (float64, []int) {
+	if len(q) == 0 {
+		return 1, []int{}
+	} else if len(q) > len(c) {
+		return 0, nil
+	}
+
+	var qi, end = 0, -1
+
+	for ci, r := range c {
+		if fuzzyRuneEqual(q[qi], r, caseSensitive) {
+			qi++
+
+			if qi == len(q) {
+				end = ci
+				break
+			}
+		}
+	}
+
+	if end < 0 {
+		return 0, nil
+	}
+
+	var start = end
+
+	for qi = len(q) - 1; start >= 0; start-- {
+		if fuzzyRuneEqual(q[qi], c[start], caseSensitive) {
+			if qi--; qi < 0 {
+				break
+			}
+		}
+	}
+
+	var positions = make([]int, 0, len(q))
+
+	qi = 0
+
+	for ci := start; ci <= end && qi < len(q); ci++ {
+		if fuzzyRuneEqual(q[qi], c[ci], caseSensitive) {
+			positions = append(positions, ci)
+			qi++
+		}
+	}
+
+	return fuzzyScore(q, c, positions), positions
+}
+
+// scores the candidate by its edit distance from the query, if that is within maxTypos.
+func fuzzyTypos(q []rune, c []rune, caseSensitive bool, maxTypos int) (float64, []int) {
+	if d := len(c) - len(q); d > maxTypos || -d > maxTypos {
+		return 0, nil
+	}
+
+	var eq = func(i, j int) bool {
+		return fuzzyRuneEqual(q[i], c[j], caseSensitive)
+	}
+
+	// optimal string alignment distance, keeping the full matrix for backtracking
+	var d = make([][]int, len(q)+1)
+
+	for i := range d {
+		d[i] = make([]int, len(c)+1)
+		d[i][0] = i
+	}
+
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(q); i++ {
+		for j := 1; j <= len(c); j++ {
+			var cost = 1
+
+			if eq(i-1, j-1) {
+				cost = 0
+			}
+
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && eq(i-1, j-2) && eq(i-2, j-1) {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	var distance = d[len(q)][len(c)]
+
+	if distance > maxTypos || distance >= len(q) {
+		return 0, nil
+	}
+
+	var positions = make([]int, 0, len(q))
+
+	for i, j := len(q), len(c); i > 0 && j > 0; {
+		switch {
+		case eq(i-1, j-1) && d[i][j] == d[i-1][j-1]:
+			positions = append(positions, j-1)
+			i, j = i-1, j-1
+		case i > 1 && j > 1 && eq(i-1, j-2) && eq(i-2, j-1) && d[i][j] == d[i-2][j-2]+1:
+			i, j = i-2, j-2
+		case d[i][j] == d[i-1][j-1]+1:
+			i, j = i-1, j-1
+		case d[i][j] == d[i-1][j]+1:
+			i--
+		default:
+			j--
+		}
+	}
+
+	sort.Ints(positions)
+
+	// typo matches always rank below subsequence matches of the same strength
+	return 0.5 * fuzzyScore(q, c, positions) * (1 - float64(distance)/float64(len(q))), positions
+}
+
+// scores a set of matched positions: each matched character is worth a point, with bonuses for
+// matching at the start of a word or immediately after the previous match, and a penalty for
+// each unmatched character in between.  The result is normalized to 0.0-1.0 and scaled down
+// slightly for candidates much longer than the query.
+func fuzzyScore(q []rune, c []rune, positions []int) float64 {
+	if len(positions) == 0 {
+		return 0
+	}
+
+	var raw int
+
+	for i, p := range positions {
+		raw += fuzzyMatchScore
+
+		if isWordBoundary(c, p) {
+			raw += fuzzyBoundaryBonus
+		}
+
+		if i > 0 && positions[i-1] == p-1 {
+			raw += fuzzyConsecutiveBonus
+		}
+	}
+
+	raw -= fuzzyGapPenalty * (positions[len(positions)-1] - positions[0] + 1 - len(positions))
+
+	if raw < 0 {
+		return 0
+	}
+
+	// the best case is the query matching as a single run at the start of a word
+	var max = len(q)*fuzzyMatchScore + fuzzyBoundaryBonus + (len(q)-1)*fuzzyConsecutiveBonus
+	var score = float64(raw) / float64(max)
+
+	if score > 1 {
+		score = 1
+	}
+
+	return score * (0.75 + 0.25*float64(len(q))/float64(len(c)))
+}
+
+func isWordBoundary(c []rune, p int) bool {
+	if p == 0 {
+		return true
+	}
+
+	var prev, cur = c[p-1], c[p]
+
+	switch {
+	case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+		return unicode.IsLetter(cur) || unicode.IsDigit(cur)
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return true
+	case unicode.IsLetter(prev) && unicode.IsDigit(cur):
+		return true
+	}
+
+	return false
+}
+
+func minInt(first int, rest ...int) int {
+	for _, v := range rest {
+		if v < first {
+			first = v
+		}
+	}
+
+	return first
+}
+
+func maxInt(first int, rest ...int) int {
+	for _, v := range rest {
+		if v > first {
+			first = v
+		}
+	}
+
+	return first
+}
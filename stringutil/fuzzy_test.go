@@ -0,0 +1,118 @@
+package stringutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestEditDistances(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(0, Levenshtein(``, ``))
+	assert.Equal(3, Levenshtein(`abc`, ``))
+	assert.Equal(3, Levenshtein(`kitten`, `sitting`))
+	assert.Equal(2, Levenshtein(`status`, `stauts`))
+	assert.Equal(1, Levenshtein(`naïve`, `naive`))
+
+	assert.Equal(3, DamerauLevenshtein(`kitten`, `sitting`))
+	assert.Equal(1, DamerauLevenshtein(`status`, `stauts`))
+	assert.Equal(2, DamerauLevenshtein(`ca`, `abc`))
+	assert.Equal(0, DamerauLevenshtein(`same`, `same`))
+	assert.Equal(4, DamerauLevenshtein(``, `four`))
+
+	assert.InDelta(0.944, Jaro(`MARTHA`, `MARHTA`), 0.001)
+	assert.InDelta(0.961, JaroWinkler(`MARTHA`, `MARHTA`), 0.001)
+	assert.InDelta(0.813, JaroWinkler(`DIXON`, `DICKSONX`), 0.001)
+	assert.Equal(1.0, JaroWinkler(`same`, `same`))
+	assert.Equal(0.0, JaroWinkler(`abc`, `xyz`))
+
+	assert.Equal(map[string]bool{
+		`  c`: true,
+		` ca`: true,
+		`cat`: true,
+		`at `: true,
+	}, Trigrams(`Cat`))
+
+	assert.Equal(1.0, TrigramSimilarity(`word`, `WORD`))
+	assert.Equal(0.0, TrigramSimilarity(`abc`, `xyz`))
+	assert.InDelta(0.364, TrigramSimilarity(`word`, `two words`), 0.001)
+}
+
+func TestFuzzyFind(t *testing.T) {
+	assert := require.New(t)
+
+	candidates := []string{
+		`status`,
+		`stash`,
+		`git-status`,
+		`submodule-status`,
+		`commit`,
+		`show-branch`,
+		`cherry-pick`,
+	}
+
+	matches := FuzzyFind(`st`, candidates, nil)
+	assert.Len(matches, 4)
+	assert.Equal(`stash`, matches[0].Value)
+	assert.Equal(`status`, matches[1].Value)
+	assert.Equal([]int{0, 1}, matches[0].Positions)
+	assert.Equal(`git-status`, matches[2].Value)
+	assert.Equal([]int{4, 5}, matches[2].Positions)
+	assert.Equal(2, matches[2].Index)
+
+	// exact matches score highest
+	matches = FuzzyFind(`STATUS`, candidates, nil)
+	assert.Equal(`status`, matches[0].Value)
+	assert.Equal(1.0, matches[0].Score)
+
+	// word-initial matches are preferred over scattered ones
+	matches = FuzzyFind(`sb`, candidates, nil)
+	assert.Equal(`show-branch`, matches[0].Value)
+	assert.Equal([]int{0, 5}, matches[0].Positions)
+
+	assert.Empty(FuzzyFind(`STATUS`, candidates, &FuzzyOptions{
+		CaseSensitive: true,
+	}))
+
+	assert.Len(FuzzyFind(`s`, candidates, &FuzzyOptions{
+		Limit: 2,
+	}), 2)
+
+	// typos are only considered when asked for
+	assert.Empty(FuzzyFind(`stauts`, candidates, nil))
+
+	matches = FuzzyFind(`stauts`, candidates, &FuzzyOptions{
+		MaxTypos: 1,
+	})
+
+	assert.Len(matches, 1)
+	assert.Equal(`status`, matches[0].Value)
+	assert.Equal([]int{0, 1, 2, 5}, matches[0].Positions)
+	assert.True(matches[0].Score < 0.5)
+
+	matches = FuzzyFind(`comit`, candidates, &FuzzyOptions{
+		MaxTypos: 2,
+	})
+
+	assert.Equal(`commit`, matches[0].Value)
+	assert.Equal([]int{0, 1, 2, 4, 5}, matches[0].Positions)
+}
+
+func BenchmarkFuzzyFind(b *testing.B) {
+	candidates := make([]string, 50000)
+
+	for i := range candidates {
+		candidates[i] = fmt.Sprintf("config.section%d.someLongerKeyName%d", i%100, i)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		FuzzyFind(`sec42key`, candidates, &FuzzyOptions{
+			MaxTypos: 1,
+			Limit:    10,
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package stringutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ghetzel/go-stockutil/utils"
+)
+
+// The suffixes used by CompactNumber for each successive power of 1000.
+var CompactNumberSuffixes = []string{``, `k`, `M`, `B`, `T`}
+
+var smallNumberWords = []string{
+	`zero`, `one`, `two`, `three`, `four`, `five`, `six`, `seven`, `eight`, `nine`, `ten`,
+	`eleven`, `twelve`, `thirteen`, `fourteen`, `fifteen`, `sixteen`, `seventeen`, `eighteen`, `nineteen`,
+}
+
+var tensWords = []string{
+	``, ``, `twenty`, `thirty`, `forty`, `fifty`, `sixty`, `seventy`, `eighty`, `ninety`,
+}
+
+var scaleWords = []string{
+	``, `thousand`, `million`, `billion`, `trillion`, `quadrillion`, `quintillion`,
+}
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, `M`}, {900, `CM`}, {500, `D`}, {400, `CD`}, {100, `C`}, {90, `XC`},
+	{50, `L`}, {40, `XL`}, {10, `X`}, {9, `IX`}, {5, `V`}, {4, `IV`}, {1, `I`},
+}
+
+// Formats the given number in compact notation (e.g.: 1.2k, 3.4M, 5.6B) with at most precision
+// digits after the decimal point.  Trailing zeros are removed.  Values that are not numeric are
+// returned as a string unchanged.
+func CompactNumber(in interface{}, precision int) string {
+	var v, err = ConvertToFloat(in)
+
+	if err != nil {
+		return MustString(in, ``)
+	}
+
+	var sign = ``
+
+	if v < 0 {
+		sign = `-`
+		v = -v
+	}
+
+	var i int
+
+	for i < len(CompactNumberSuffixes)-1 && v >= 1000 {
+		v /= 1000
+		i++
+	}
+
+	// rounding may carry us into the next suffix (e.g.: 999,950 -> 1000.0k -> 1M)
+	if rounded := roundTo(v, precision); rounded >= 1000 && i < len(CompactNumberSuffixes)-1 {
+		v = rounded / 1000
+		i++
+	}
+
+	return sign + trimDecimal(strconv.FormatFloat(roundTo(v, precision), 'f', precision, 64)) + CompactNumberSuffixes[i]
+}
+
+// Parses a number in compact notation (e.g.: "2.5k" or "3 million") and returns its value.
+// Set utils.AutotypeCompactNumbers to have Autotype recognize these values as well.
+func ParseCompactNumber(in string) (float64, error) {
+	return utils.ParseCompactNumber(in)
+}
+
+// Returns the given integer with its English ordinal suffix (e.g.: 1st, 22nd, 113th).
+func Ordinal(in interface{}) string {
+	var n, err = ConvertToInteger(in)
+
+	if err != nil {
+		return MustString(in, ``)
+	}
+
+	var abs = n
+
+	if abs < 0 {
+		abs = -abs
+	}
+
+	switch {
+	case abs%100 >= 11 && abs%100 <= 13:
+		return fmt.Sprintf("%dth", n)
+	case abs%10 == 1:
+		return fmt.Sprintf("%dst", n)
+	case abs%10 == 2:
+		return fmt.Sprintf("%dnd", n)
+	case abs%10 == 3:
+		return fmt.Sprintf("%drd", n)
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
+
+// Returns the given number written out in English words (e.g.: 1200 becomes "one thousand two
+// hundred", and 3.14 becomes "three point one four").
+func SpellNumber(in interface{}) string {
+	var str = strings.TrimSpace(MustString(in, ``))
+
+	if !IsNumeric(str) {
+		return str
+	}
+
+	var words []string
+
+	if strings.HasPrefix(str, `-`) {
+		words = append(words, `negative`)
+		str = str[1:]
+	}
+
+	var whole, fraction = str, ``
+
+	if strings.ContainsAny(str, `.eE`) {
+		// normalize exponents and other float notation
+		if v, err := strconv.ParseFloat(str, 64); err == nil {
+			str = strconv.FormatFloat(v, 'f', -1, 64)
+			whole, fraction = str, ``
+
+			if i := strings.Index(str, `.`); i >= 0 {
+				whole, fraction = str[:i], str[i+1:]
+			}
+		}
+	}
+
+	if n, err := strconv.ParseUint(whole, 10, 64); err == nil {
+		words = append(words, spellInteger(n)...)
+	} else {
+		return str
+	}
+
+	if fraction != `` {
+		words = append(words, `point`)
+
+		for _, digit := range fraction {
+			words = append(words, smallNumberWords[digit-'0'])
+		}
+	}
+
+	return strings.Join(words, ` `)
+}
+
+func spellInteger(n uint64) []string {
+	if n == 0 {
+		return []string{`zero`}
+	}
+
+	var groups []string
+
+	for scale := 0; n > 0; scale++ {
+		if chunk := n % 1000; chunk > 0 {
+			var words = spellHundreds(int(chunk))
+
+			if scaleWords[scale] != `` {
+				words = append(words, scaleWords[scale])
+			}
+
+			groups = append(strings.Fields(strings.Join(words, ` `)), groups...)
+		}
+
+		n /= 1000
+	}
+
+	return groups
+}
+
+func spellHundreds(n int) []string {
+	var words []string
+
+	if n >= 100 {
+		words = append(words, smallNumberWords[n/100], `hundred`)
+		n %= 100
+	}
+
+	if n >= 20 {
+		if n%10 > 0 {
+			words = append(words, tensWords[n/10]+`-`+smallNumberWords[n%10])
+		} else {
+			words = append(words, tensWords[n/10])
+		}
+	} else if n > 0 {
+		words = append(words, smallNumberWords[n])
+	}
+
+	return words
+}
+
+// Returns the given integer (from 1 to 3999) as a Roman numeral.
+func RomanNumeral(in interface{}) (string, error) {
+	var n, err = ConvertToInteger(in)
+
+	if err != nil {
+		return ``, err
+	} else if n < 1 || n > 3999 {
+		return ``, fmt.Errorf("Roman numerals must be between 1 and 3999, got %d", n)
+	}
+
+	var out strings.Builder
+
+	for _, numeral := range romanNumerals {
+		for int(n) >= numeral.value {
+			out.WriteString(numeral.symbol)
+			n -= int64(numeral.value)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// Parses the given Roman numeral (case-insensitive) and returns its value.
+func ParseRomanNumeral(in string) (int, error) {
+	var upper = strings.ToUpper(strings.TrimSpace(in))
+	var rest = upper
+	var total int
+
+	for _, numeral := range romanNumerals {
+		for strings.HasPrefix(rest, numeral.symbol) {
+			total += numeral.value
+			rest = rest[len(numeral.symbol):]
+		}
+	}
+
+	// round-trip to reject non-canonical forms like "IIII" or "VX"
+	if rest != `` || total == 0 {
+		return 0, fmt.Errorf("Invalid Roman numeral '%s'", in)
+	} else if canonical, _ := RomanNumeral(total); canonical != upper {
+		return 0, fmt.Errorf("Invalid Roman numeral '%s'", in)
+	}
+
+	return total, nil
+}
+
+// Formats the given ratio as a percentage with the given number of decimal places (e.g.: 0.256
+// becomes "25.6%").  Values that are not numeric are returned as a string unchanged.
+func Percent(in interface{}, precision int) string {
+	if v, err := ConvertToFloat(in); err == nil {
+		return strconv.FormatFloat(roundTo(v*100, precision), 'f', precision, 64) + `%`
+	} else {
+		return MustString(in, ``)
+	}
+}
+
+func roundTo(v float64, precision int) float64 {
+	var scale = math.Pow(10, float64(precision))
+
+	return math.Round(v*scale) / scale
+}
+
+func trimDecimal(in string) string {
+	if strings.Contains(in, `.`) {
+		in = strings.TrimRight(strings.TrimRight(in, `0`), `.`)
+	}
+
+	return in
+}
@@ -0,0 +1,132 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestCompactNumber(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`0`, CompactNumber(0, 1))
+	assert.Equal(`999`, CompactNumber(999, 1))
+	assert.Equal(`1.2k`, CompactNumber(1234, 1))
+	assert.Equal(`1.23k`, CompactNumber(1234, 2))
+	assert.Equal(`1k`, CompactNumber(1000, 1))
+	assert.Equal(`3.4M`, CompactNumber(3400000, 1))
+	assert.Equal(`5.6B`, CompactNumber(`5600000000`, 1))
+	assert.Equal(`7T`, CompactNumber(7e12, 2))
+	assert.Equal(`7000T`, CompactNumber(7e15, 2))
+	assert.Equal(`1M`, CompactNumber(999950, 1))
+	assert.Equal(`-2.5k`, CompactNumber(-2500, 1))
+	assert.Equal(`2k`, CompactNumber(2499, 0))
+	assert.Equal(`potato`, CompactNumber(`potato`, 1))
+}
+
+func TestParseCompactNumberWrapper(t *testing.T) {
+	assert := require.New(t)
+
+	v, err := ParseCompactNumber(`2.5k`)
+	assert.NoError(err)
+	assert.Equal(float64(2500), v)
+}
+
+func TestOrdinal(t *testing.T) {
+	assert := require.New(t)
+
+	for in, want := range map[int]string{
+		0:   `0th`,
+		1:   `1st`,
+		2:   `2nd`,
+		3:   `3rd`,
+		4:   `4th`,
+		11:  `11th`,
+		12:  `12th`,
+		13:  `13th`,
+		21:  `21st`,
+		22:  `22nd`,
+		101: `101st`,
+		111: `111th`,
+		113: `113th`,
+		-1:  `-1st`,
+	} {
+		assert.Equal(want, Ordinal(in))
+	}
+
+	assert.Equal(`23rd`, Ordinal(`23`))
+	assert.Equal(`nope`, Ordinal(`nope`))
+}
+
+func TestSpellNumber(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`zero`, SpellNumber(0))
+	assert.Equal(`seven`, SpellNumber(7))
+	assert.Equal(`fifteen`, SpellNumber(15))
+	assert.Equal(`twenty`, SpellNumber(20))
+	assert.Equal(`twenty-one`, SpellNumber(21))
+	assert.Equal(`one hundred`, SpellNumber(100))
+	assert.Equal(`one hundred five`, SpellNumber(105))
+	assert.Equal(`one thousand two hundred`, SpellNumber(1200))
+	assert.Equal(`one million one`, SpellNumber(1000001))
+	assert.Equal(
+		`nine hundred ninety-nine thousand nine hundred ninety-nine`,
+		SpellNumber(999999),
+	)
+	assert.Equal(`two billion three hundred forty-five million`, SpellNumber(`2345000000`))
+	assert.Equal(`negative forty-two`, SpellNumber(-42))
+	assert.Equal(`three point one four`, SpellNumber(3.14))
+	assert.Equal(`zero point five`, SpellNumber(`0.5`))
+	assert.Equal(`potato`, SpellNumber(`potato`))
+}
+
+func TestRomanNumeral(t *testing.T) {
+	assert := require.New(t)
+
+	for in, want := range map[int]string{
+		1:    `I`,
+		4:    `IV`,
+		9:    `IX`,
+		14:   `XIV`,
+		40:   `XL`,
+		90:   `XC`,
+		400:  `CD`,
+		1994: `MCMXCIV`,
+		2024: `MMXXIV`,
+		3999: `MMMCMXCIX`,
+	} {
+		out, err := RomanNumeral(in)
+		assert.NoError(err)
+		assert.Equal(want, out)
+
+		n, err := ParseRomanNumeral(want)
+		assert.NoError(err)
+		assert.Equal(in, n)
+	}
+
+	_, err := RomanNumeral(0)
+	assert.Error(err)
+
+	_, err = RomanNumeral(4000)
+	assert.Error(err)
+
+	n, err := ParseRomanNumeral(`mcmxciv`)
+	assert.NoError(err)
+	assert.Equal(1994, n)
+
+	for _, bad := range []string{``, `IIII`, `VX`, `IC`, `ABC`, `MMMM`} {
+		_, err := ParseRomanNumeral(bad)
+		assert.Error(err, bad)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`25.6%`, Percent(0.256, 1))
+	assert.Equal(`26%`, Percent(0.256, 0))
+	assert.Equal(`100.00%`, Percent(1, 2))
+	assert.Equal(`-5.0%`, Percent(`-0.05`, 1))
+	assert.Equal(`n/a`, Percent(`n/a`, 1))
+}
@@ -0,0 +1,323 @@
+package stringutil
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+type inflectionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// An Inflector converts English words between their singular and plural forms using a set of
+// suffix rules, irregular words, and uncountable words.  Rules added later take precedence over
+// those added earlier.  An Inflector is safe for concurrent use.
+type Inflector struct {
+	plurals     []inflectionRule
+	singulars   []inflectionRule
+	irregular   map[string]string
+	irregularOf map[string]string
+	uncountable map[string]bool
+	lock        sync.RWMutex
+}
+
+// The Inflector used by Pluralize, Singularize, and PluralizeCount.
+var DefaultInflector = NewInflector()
+
+// Returns a new Inflector populated with rules for common English words.
+func NewInflector() *Inflector {
+	var inflector = NewEmptyInflector()
+
+	for _, rule := range [][2]string{
+		{`$`, `s`},
+		{`s$`, `s`},
+		{`^(ax|test)is$`, `${1}es`},
+		{`(octop|vir)us$`, `${1}i`},
+		{`(octop|vir)i$`, `${1}i`},
+		{`(alias|status|campus)$`, `${1}es`},
+		{`(bu)s$`, `${1}ses`},
+		{`(buffal|tomat|potat|her|ech|torped|vet)o$`, `${1}oes`},
+		{`([ti])um$`, `${1}a`},
+		{`([ti])a$`, `${1}a`},
+		{`sis$`, `ses`},
+		{`(?:([^f])fe|([lr])f)$`, `${1}${2}ves`},
+		{`(hive)$`, `${1}s`},
+		{`([^aeiouy]|qu)y$`, `${1}ies`},
+		{`(x|ch|ss|sh|zz)$`, `${1}es`},
+		{`(matr|vert|ind)(?:ix|ex)$`, `${1}ices`},
+		{`^(m|l)ouse$`, `${1}ice`},
+		{`^(m|l)ice$`, `${1}ice`},
+		{`^(ox)$`, `${1}en`},
+		{`^(oxen)$`, `${1}`},
+		{`(quiz)$`, `${1}zes`},
+	} {
+		inflector.mustAdd(&inflector.plurals, rule[0], rule[1])
+	}
+
+	for _, rule := range [][2]string{
+		{`s$`, ``},
+		{`(ss)$`, `${1}`},
+		{`(n)ews$`, `${1}ews`},
+		{`([ti])a$`, `${1}um`},
+		{`((a)naly|(b)a|(d)iagno|(p)arenthe|(p)rogno|(s)ynop|(t)he)(sis|ses)$`, `${1}sis`},
+		{`(^analy)(sis|ses)$`, `${1}sis`},
+		{`([^f])ves$`, `${1}fe`},
+		{`(hive)s$`, `${1}`},
+		{`(tive)s$`, `${1}`},
+		{`([lr])ves$`, `${1}f`},
+		{`([^aeiouy]|qu)ies$`, `${1}y`},
+		{`(s)eries$`, `${1}eries`},
+		{`(m)ovies$`, `${1}ovie`},
+		{`(x|ch|ss|sh|zz)es$`, `${1}`},
+		{`^(m|l)ice$`, `${1}ouse`},
+		{`(bus)(es)?$`, `${1}`},
+		{`(o)es$`, `${1}`},
+		{`(shoe)s$`, `${1}`},
+		{`(cris|test)(is|es)$`, `${1}is`},
+		{`^(a)x[ie]s$`, `${1}xis`},
+		{`(octop|vir)(us|i)$`, `${1}us`},
+		{`(alias|status|campus)(es)?$`, `${1}`},
+		{`^(ox)en`, `${1}`},
+		{`(vert|ind)ices$`, `${1}ex`},
+		{`(matr)ices$`, `${1}ix`},
+		{`(quiz)zes$`, `${1}`},
+		{`(database)s$`, `${1}`},
+	} {
+		inflector.mustAdd(&inflector.singulars, rule[0], rule[1])
+	}
+
+	for _, pair := range [][2]string{
+		{`person`, `people`},
+		{`man`, `men`},
+		{`woman`, `women`},
+		{`child`, `children`},
+		{`foot`, `feet`},
+		{`tooth`, `teeth`},
+		{`goose`, `geese`},
+		{`sex`, `sexes`},
+		{`move`, `moves`},
+		{`zombie`, `zombies`},
+		{`cactus`, `cacti`},
+		{`criterion`, `criteria`},
+		{`phenomenon`, `phenomena`},
+	} {
+		inflector.AddIrregular(pair[0], pair[1])
+	}
+
+	inflector.AddUncountable(
+		`equipment`, `information`, `rice`, `money`, `species`, `series`, `fish`, `sheep`, `deer`,
+		`moose`, `jeans`, `police`, `news`, `metadata`, `software`, `hardware`, `firmware`,
+		`feedback`, `traffic`, `advice`, `luggage`, `furniture`, `music`,
+	)
+
+	return inflector
+}
+
+// Returns a new Inflector with no rules.  Without rules, words are returned unchanged.
+func NewEmptyInflector() *Inflector {
+	return &Inflector{
+		irregular:   make(map[string]string),
+		irregularOf: make(map[string]string),
+		uncountable: make(map[string]bool),
+	}
+}
+
+// Registers a rule for forming plurals.  The pattern is a case-insensitive regular expression
+// matched against the end of a word, and the replacement may refer to capture groups (e.g.
+// "${1}").
+func (self *Inflector) AddPluralRule(pattern string, replacement string) error {
+	return self.add(&self.plurals, pattern, replacement)
+}
+
+// Registers a rule for forming singulars.  The pattern is a case-insensitive regular expression
+// matched against the end of a word, and the replacement may refer to capture groups (e.g.
+// "${1}").
+func (self *Inflector) AddSingularRule(pattern string, replacement string) error {
+	return self.add(&self.singulars, pattern, replacement)
+}
+
+// Registers a word whose plural does not follow any rule (e.g. "person" and "people").
+func (self *Inflector) AddIrregular(singular string, plural string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	singular = strings.ToLower(singular)
+	plural = strings.ToLower(plural)
+
+	delete(self.uncountable, singular)
+	delete(self.uncountable, plural)
+
+	self.irregular[singular] = plural
+	self.irregularOf[plural] = singular
+}
+
+// Registers one or more words that have the same singular and plural forms (e.g. "sheep").
+func (self *Inflector) AddUncountable(words ...string) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	for _, word := range words {
+		self.uncountable[strings.ToLower(word)] = true
+	}
+}
+
+// Returns the plural form of the given word.  If the input is a phrase or identifier, only the
+// final word is changed (e.g. "blog post" becomes "blog posts").
+func (self *Inflector) Pluralize(word string) string {
+	return self.inflect(word, self.plurals, self.irregular, self.irregularOf)
+}
+
+// Returns the singular form of the given word.  If the input is a phrase or identifier, only the
+// final word is changed (e.g. "user_accounts" becomes "user_account").
+func (self *Inflector) Singularize(word string) string {
+	return self.inflect(word, self.singulars, self.irregularOf, self.irregular)
+}
+
+func (self *Inflector) inflect(word string, rules []inflectionRule, irregular map[string]string, inflected map[string]string) string {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	// split off the final word, which is the only one that gets inflected
+	var start = strings.LastIndexFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) + 1
+
+	var prefix, last = word[:start], word[start:]
+	var lower = strings.ToLower(last)
+
+	if last == `` || self.uncountable[lower] {
+		return word
+	} else if replacement, ok := irregular[lower]; ok {
+		return prefix + matchCase(last, replacement)
+	} else if _, ok := inflected[lower]; ok {
+		// already in the desired form
+		return word
+	}
+
+	// rules are tried most-recently-added first
+	for i := len(rules) - 1; i >= 0; i-- {
+		if rule := rules[i]; rule.pattern.MatchString(last) {
+			return prefix + matchCase(last, rule.pattern.ReplaceAllString(last, rule.replacement))
+		}
+	}
+
+	return word
+}
+
+func (self *Inflector) add(rules *[]inflectionRule, pattern string, replacement string) error {
+	if rx, err := regexp.Compile(`(?i)` + pattern); err == nil {
+		self.lock.Lock()
+		defer self.lock.Unlock()
+
+		*rules = append(*rules, inflectionRule{
+			pattern:     rx,
+			replacement: replacement,
+		})
+
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (self *Inflector) mustAdd(rules *[]inflectionRule, pattern string, replacement string) {
+	if err := self.add(rules, pattern, replacement); err != nil {
+		panic(err.Error())
+	}
+}
+
+// makes the inflected word follow the capitalization of the original: all-uppercase words stay
+// uppercase, and capitalized words stay capitalized.
+func matchCase(original string, inflected string) string {
+	if utf8.RuneCountInString(original) > 1 && strings.ToUpper(original) == original {
+		return strings.ToUpper(inflected)
+	} else if first, _ := utf8.DecodeRuneInString(original); unicode.IsUpper(first) {
+		var r, n = utf8.DecodeRuneInString(inflected)
+
+		return string(unicode.ToUpper(r)) + inflected[n:]
+	}
+
+	return inflected
+}
+
+// Returns the plural form of the given word using the DefaultInflector.
+func Pluralize(word string) string {
+	return DefaultInflector.Pluralize(word)
+}
+
+// Returns the singular form of the given word using the DefaultInflector.
+func Singularize(word string) string {
+	return DefaultInflector.Singularize(word)
+}
+
+// Returns the count followed by the singular or plural form of the given word, as appropriate
+// (e.g. "1 file", "3 files").
+func PluralizeCount(count int, word string) string {
+	if count == 1 || count == -1 {
+		return fmt.Sprintf("%d %s", count, Singularize(word))
+	} else {
+		return fmt.Sprintf("%d %s", count, Pluralize(word))
+	}
+}
+
+// Specifies how ToSentenceList joins items together.
+type SentenceListOptions struct {
+	// The separator placed between items (default: ", ").
+	Separator string
+
+	// The word placed before the final item (default: "and").
+	Conjunction string
+
+	// Whether to include the separator before the conjunction in lists of three or more
+	// items (e.g. "a, b, and c" instead of "a, b and c").
+	OxfordComma bool
+}
+
+// The options used by ToSentenceList when none are given.
+var DefaultSentenceListOptions = SentenceListOptions{
+	Separator:   `, `,
+	Conjunction: `and`,
+	OxfordComma: true,
+}
+
+// Joins the given items into an English list (e.g. ["a", "b", "c"] becomes "a, b, and c").  If
+// options are not given, DefaultSentenceListOptions is used.
+func ToSentenceList(items []string, options *SentenceListOptions) string {
+	if options == nil {
+		options = &DefaultSentenceListOptions
+	}
+
+	var sep = options.Separator
+	var conj = options.Conjunction
+
+	if sep == `` {
+		sep = DefaultSentenceListOptions.Separator
+	}
+
+	if conj == `` {
+		conj = DefaultSentenceListOptions.Conjunction
+	}
+
+	switch len(items) {
+	case 0:
+		return ``
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + ` ` + conj + ` ` + items[1]
+	}
+
+	var last = len(items) - 1
+	var out = strings.Join(items[:last], sep)
+
+	if options.OxfordComma {
+		out += strings.TrimRight(sep, ` `)
+	}
+
+	return out + ` ` + conj + ` ` + items[last]
+}
@@ -0,0 +1,137 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+var inflectionPairs = map[string]string{
+	`file`:        `files`,
+	`box`:         `boxes`,
+	`church`:      `churches`,
+	`class`:       `classes`,
+	`wish`:        `wishes`,
+	`query`:       `queries`,
+	`day`:         `days`,
+	`knife`:       `knives`,
+	`wolf`:        `wolves`,
+	`life`:        `lives`,
+	`hive`:        `hives`,
+	`potato`:      `potatoes`,
+	`photo`:       `photos`,
+	`analysis`:    `analyses`,
+	`crisis`:      `crises`,
+	`axis`:        `axes`,
+	`datum`:       `data`,
+	`medium`:      `media`,
+	`matrix`:      `matrices`,
+	`vertex`:      `vertices`,
+	`index`:       `indices`,
+	`octopus`:     `octopi`,
+	`status`:      `statuses`,
+	`alias`:       `aliases`,
+	`bus`:         `buses`,
+	`quiz`:        `quizzes`,
+	`mouse`:       `mice`,
+	`ox`:          `oxen`,
+	`movie`:       `movies`,
+	`database`:    `databases`,
+	`person`:      `people`,
+	`man`:         `men`,
+	`woman`:       `women`,
+	`human`:       `humans`,
+	`child`:       `children`,
+	`foot`:        `feet`,
+	`goose`:       `geese`,
+	`zombie`:      `zombies`,
+	`move`:        `moves`,
+	`criterion`:   `criteria`,
+	`sheep`:       `sheep`,
+	`fish`:        `fish`,
+	`news`:        `news`,
+	`series`:      `series`,
+	`species`:     `species`,
+	`equipment`:   `equipment`,
+	`blog post`:   `blog posts`,
+	`user_entry`:  `user_entries`,
+	`salesperson`: `salespersons`,
+}
+
+func TestPluralizeSingularize(t *testing.T) {
+	assert := require.New(t)
+
+	for singular, plural := range inflectionPairs {
+		assert.Equal(plural, Pluralize(singular), singular)
+		assert.Equal(singular, Singularize(plural), plural)
+
+		// already in the target form
+		assert.Equal(plural, Pluralize(plural), plural)
+		assert.Equal(singular, Singularize(singular), singular)
+	}
+
+	assert.Equal(``, Pluralize(``))
+	assert.Equal(`People`, Pluralize(`Person`))
+	assert.Equal(`USERS`, Pluralize(`USER`))
+	assert.Equal(`Categories`, Pluralize(`Category`))
+	assert.Equal(`BlogPosts`, Pluralize(`BlogPost`))
+	assert.Equal(`Child`, Singularize(`Children`))
+	assert.Equal(`api/v1/user`, Singularize(`api/v1/users`))
+}
+
+func TestInflectorCustomRules(t *testing.T) {
+	assert := require.New(t)
+
+	var inflector = NewInflector()
+
+	assert.Equal(`cows`, inflector.Pluralize(`cow`))
+	inflector.AddIrregular(`cow`, `kine`)
+	assert.Equal(`kine`, inflector.Pluralize(`cow`))
+	assert.Equal(`cow`, inflector.Singularize(`kine`))
+
+	inflector.AddUncountable(`aircraft`)
+	assert.Equal(`aircraft`, inflector.Pluralize(`aircraft`))
+
+	assert.NoError(inflector.AddPluralRule(`(vir)us$`, `${1}uses`))
+	assert.NoError(inflector.AddSingularRule(`(vir)uses$`, `${1}us`))
+	assert.Equal(`viruses`, inflector.Pluralize(`virus`))
+	assert.Equal(`virus`, inflector.Singularize(`viruses`))
+	assert.Error(inflector.AddPluralRule(`(unclosed$`, ``))
+
+	// the default inflector is unaffected
+	assert.Equal(`cows`, Pluralize(`cow`))
+	assert.Equal(`virus`, Singularize(`viri`))
+
+	var empty = NewEmptyInflector()
+	assert.Equal(`word`, empty.Pluralize(`word`))
+}
+
+func TestPluralizeCount(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`0 files`, PluralizeCount(0, `file`))
+	assert.Equal(`1 file`, PluralizeCount(1, `file`))
+	assert.Equal(`1 file`, PluralizeCount(1, `files`))
+	assert.Equal(`3 files`, PluralizeCount(3, `file`))
+	assert.Equal(`2 people`, PluralizeCount(2, `person`))
+	assert.Equal(`-1 degree`, PluralizeCount(-1, `degree`))
+}
+
+func TestToSentenceList(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(``, ToSentenceList(nil, nil))
+	assert.Equal(`a`, ToSentenceList([]string{`a`}, nil))
+	assert.Equal(`a and b`, ToSentenceList([]string{`a`, `b`}, nil))
+	assert.Equal(`a, b, and c`, ToSentenceList([]string{`a`, `b`, `c`}, nil))
+	assert.Equal(`a, b and c`, ToSentenceList([]string{`a`, `b`, `c`}, &SentenceListOptions{}))
+	assert.Equal(`a, b, or c`, ToSentenceList([]string{`a`, `b`, `c`}, &SentenceListOptions{
+		Conjunction: `or`,
+		OxfordComma: true,
+	}))
+	assert.Equal(`a; b; or c`, ToSentenceList([]string{`a`, `b`, `c`}, &SentenceListOptions{
+		Separator:   `; `,
+		Conjunction: `or`,
+		OxfordComma: true,
+	}))
+}
@@ -0,0 +1,200 @@
+package stringutil
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The epoch used by KSUID timestamps (2014-05-13T16:53:20Z), in Unix seconds.
+const KsuidEpoch = 1400000000
+
+const base62Alphabet = `0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz`
+
+var ksuidState = struct {
+	sync.Mutex
+	lastSec uint32
+	payload [16]byte
+}{}
+
+// A K-Sortable Unique Identifier: a 32-bit timestamp (seconds since KsuidEpoch) followed by 128
+// random bits, represented as a 27-character Base62 string.
+type Ksuid [20]byte
+
+// Generate a new KSUID using the current time.  KSUIDs generated within the same second are
+// guaranteed to increase.
+func KSUID() *Ksuid {
+	ksuidState.Lock()
+	defer ksuidState.Unlock()
+
+	var sec = uint32(time.Now().Unix() - KsuidEpoch)
+
+	if sec > ksuidState.lastSec {
+		ksuidState.lastSec = sec
+		randomBytes(ksuidState.payload[:])
+	} else if !incrementBytes(ksuidState.payload[:]) {
+		ksuidState.lastSec++
+		randomBytes(ksuidState.payload[:])
+	}
+
+	var out = new(Ksuid)
+
+	binary.BigEndian.PutUint32(out[0:4], ksuidState.lastSec)
+	copy(out[4:], ksuidState.payload[:])
+
+	return out
+}
+
+// Generate a KSUID for the given time.  Unlike KSUID, successive calls with the same time are not
+// guaranteed to be ordered.
+func KSUIDAt(t time.Time) *Ksuid {
+	var out = new(Ksuid)
+
+	binary.BigEndian.PutUint32(out[0:4], uint32(t.Unix()-KsuidEpoch))
+	randomBytes(out[4:])
+
+	return out
+}
+
+// Parse a KSUID from its 27-character Base62 string representation.
+func ParseKSUID(in string) (*Ksuid, error) {
+	if len(in) != 27 {
+		return nil, fmt.Errorf("invalid KSUID length %d", len(in))
+	}
+
+	var n = new(big.Int)
+	var base = big.NewInt(62)
+
+	for i := 0; i < len(in); i++ {
+		var v = strings.IndexByte(base62Alphabet, in[i])
+
+		if v < 0 {
+			return nil, fmt.Errorf("invalid KSUID %q: bad character %q", in, in[i])
+		}
+
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	if n.BitLen() > 160 {
+		return nil, fmt.Errorf("invalid KSUID %q: value out of range", in)
+	}
+
+	var out = new(Ksuid)
+
+	n.FillBytes(out[:])
+
+	return out, nil
+}
+
+// Same as ParseKSUID, but panics if the given string is not a valid KSUID.
+func MustKSUID(in string) *Ksuid {
+	if id, err := ParseKSUID(in); err == nil {
+		return id
+	} else {
+		panic(err)
+	}
+}
+
+// Return a KSUID from its 20-byte binary representation.
+func KsuidFromBytes(b []byte) (*Ksuid, error) {
+	if len(b) != 20 {
+		return nil, fmt.Errorf("invalid KSUID length %d", len(b))
+	}
+
+	var out = new(Ksuid)
+	copy(out[:], b)
+
+	return out, nil
+}
+
+// Return the KSUID as a 27-character Base62 string.
+func (self Ksuid) String() string {
+	var out = []byte(new(big.Int).SetBytes(self[:]).Text(62))
+
+	// big.Int uses a different ordering of letters for base 62
+	for i, c := range out {
+		switch {
+		case c >= 'a' && c <= 'z':
+			out[i] = c - 'a' + 'A'
+		case c >= 'A' && c <= 'Z':
+			out[i] = c - 'A' + 'a'
+		}
+	}
+
+	return strings.Repeat(`0`, 27-len(out)) + string(out)
+}
+
+// Return the 20-byte binary representation of the KSUID.
+func (self Ksuid) Bytes() []byte {
+	return self[:]
+}
+
+// Return the 16-byte random payload of the KSUID.
+func (self Ksuid) Payload() []byte {
+	return self[4:]
+}
+
+// Return the time embedded in the KSUID.
+func (self Ksuid) Timestamp() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(self[0:4]))+KsuidEpoch, 0)
+}
+
+func (self Ksuid) MarshalText() ([]byte, error) {
+	return []byte(self.String()), nil
+}
+
+func (self *Ksuid) UnmarshalText(data []byte) error {
+	if id, err := ParseKSUID(string(data)); err == nil {
+		*self = *id
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (self Ksuid) MarshalBinary() ([]byte, error) {
+	return self.Bytes(), nil
+}
+
+func (self *Ksuid) UnmarshalBinary(data []byte) error {
+	if id, err := KsuidFromBytes(data); err == nil {
+		*self = *id
+		return nil
+	} else {
+		return err
+	}
+}
+
+// Implements sql.Scanner, accepting either the string or 20-byte binary representation.
+func (self *Ksuid) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		if src == `` {
+			return nil
+		}
+
+		return self.UnmarshalText([]byte(src))
+	case []byte:
+		if len(src) == 0 {
+			return nil
+		} else if len(src) == 20 {
+			return self.UnmarshalBinary(src)
+		}
+
+		return self.UnmarshalText(src)
+	default:
+		return fmt.Errorf("Scan: unable to scan type %T into KSUID", src)
+	}
+}
+
+// Implements driver.Valuer; KSUIDs are stored as strings.
+func (self Ksuid) Value() (driver.Value, error) {
+	return self.String(), nil
+}
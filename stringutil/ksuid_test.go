@@ -0,0 +1,64 @@
+package stringutil
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestKSUID(t *testing.T) {
+	assert := require.New(t)
+
+	id, err := ParseKSUID(`0ujtsYcgvSTl8PAuAdqWYSMnLOv`)
+	assert.NoError(err)
+	assert.Equal(`0ujtsYcgvSTl8PAuAdqWYSMnLOv`, id.String())
+	assert.Equal(`2017-10-10T04:00:47Z`, id.Timestamp().UTC().Format(time.RFC3339))
+	assert.Equal(`b5a1cd34b5f99d1154fb6853345c9735`, hex.EncodeToString(id.Payload()))
+
+	assert.Equal(`000000000000000000000000000`, Ksuid{}.String())
+	assert.Equal(`aWgEPTl1tmebfsQzFP4bxwgy80V`, MustKSUID(`aWgEPTl1tmebfsQzFP4bxwgy80V`).String())
+
+	_, err = ParseKSUID(`0ujtsYcgvSTl8PAuAdqWYSMnLO`)
+	assert.Error(err)
+	_, err = ParseKSUID(`0ujtsYcgvSTl8PAuAdqWYSMnLO!`)
+	assert.Error(err)
+	_, err = ParseKSUID(`aWgEPTl1tmebfsQzFP4bxwgy80W`)
+	assert.Error(err)
+
+	// generation
+	now := time.Now()
+	var last string
+
+	for i := 0; i < 10000; i++ {
+		next := KSUID().String()
+		assert.True(next > last, "%s should sort after %s", next, last)
+		last = next
+	}
+
+	assert.WithinDuration(now, MustKSUID(last).Timestamp(), 2*time.Second)
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.True(at.Equal(KSUIDAt(at).Timestamp()))
+
+	// marshaling
+	data, err := json.Marshal(id)
+	assert.NoError(err)
+	assert.Equal(`"0ujtsYcgvSTl8PAuAdqWYSMnLOv"`, string(data))
+
+	var decoded Ksuid
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(*id, decoded)
+
+	var scanned Ksuid
+	assert.NoError(scanned.Scan(id.Bytes()))
+	assert.Equal(*id, scanned)
+	assert.NoError(scanned.Scan([]byte(`0ujtsYcgvSTl8PAuAdqWYSMnLOv`)))
+	assert.Equal(*id, scanned)
+
+	value, err := id.Value()
+	assert.NoError(err)
+	assert.Equal(`0ujtsYcgvSTl8PAuAdqWYSMnLOv`, value)
+}
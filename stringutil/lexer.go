@@ -0,0 +1,347 @@
+package stringutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options that control how Lex splits a string into tokens.
+type LexerOptions struct {
+	// Reports whether the given rune separates tokens.  If nil, whitespace separates tokens.
+	IsSeparator func(r rune) bool
+
+	// Characters that begin and end a quoted section.  Separators within a quoted section do not
+	// split tokens, and the quote characters themselves are removed.
+	Quotes string
+
+	// Quote characters within which escape sequences are not processed (e.g. shell-style single
+	// quotes).  These must also be present in Quotes.
+	LiteralQuotes string
+
+	// The character that causes the character following it to be taken literally.  If zero,
+	// escapes are not processed.
+	Escape rune
+
+	// Pairs of opening and closing brackets (e.g. "()[]{}").  Separators within brackets do not
+	// split tokens, and bracketed sections are returned verbatim (including the brackets, quotes,
+	// and escapes) so they can be lexed again.  Brackets must be balanced.
+	Brackets string
+
+	// Return tokens exactly as they appear in the input instead of removing quotes and escapes.
+	KeepQuotes bool
+}
+
+// The options used by Lex and SplitQuoted when none are given: whitespace-separated tokens with
+// single and double quotes, backslash escapes, and (), [] and {} brackets.
+var DefaultLexerOptions = LexerOptions{
+	Quotes:        `"'`,
+	LiteralQuotes: `'`,
+	Escape:        '\\',
+	Brackets:      `()[]{}`,
+}
+
+// A single token produced by Lex.
+type LexToken struct {
+	// The token with quotes and escapes removed (unless KeepQuotes is set).
+	Text string
+
+	// The token as it appeared in the input.
+	Raw string
+
+	// The byte offset of the start of the token in the input.
+	Offset int
+
+	// Whether any part of the token was quoted.
+	Quoted bool
+}
+
+func (self LexToken) String() string {
+	return self.Text
+}
+
+// An error encountered while lexing, such as an unterminated quote or unbalanced bracket.
+type LexError struct {
+	Message string
+	Offset  int
+}
+
+func (self *LexError) Error() string {
+	return fmt.Sprintf("%s at position %d", self.Message, self.Offset)
+}
+
+// Splits the given string into tokens using the given options (or DefaultLexerOptions if nil).
+func Lex(in string, options *LexerOptions) ([]LexToken, error) {
+	if options == nil {
+		options = &DefaultLexerOptions
+	}
+
+	var isSeparator = options.IsSeparator
+
+	if isSeparator == nil {
+		isSeparator = unicode.IsSpace
+	}
+
+	var tokens []LexToken
+	var text strings.Builder
+	var start = -1
+	var quoted bool
+	var quote rune
+	var quoteAt int
+	var brackets []rune
+	var bracketsAt []int
+
+	var flush = func(end int) {
+		if start >= 0 {
+			var token = LexToken{
+				Text:   text.String(),
+				Raw:    in[start:end],
+				Offset: start,
+				Quoted: quoted,
+			}
+
+			if options.KeepQuotes {
+				token.Text = token.Raw
+			}
+
+			tokens = append(tokens, token)
+		}
+
+		text.Reset()
+		start = -1
+		quoted = false
+	}
+
+	for i := 0; i < len(in); {
+		var r, n = utf8.DecodeRuneInString(in[i:])
+		var verbatim = len(brackets) > 0
+
+		switch {
+		case quote != 0 && r == quote:
+			// closing quote
+			quote = 0
+
+			if verbatim {
+				text.WriteRune(r)
+			}
+
+		case r == options.Escape && options.Escape != 0 && (quote == 0 || !strings.ContainsRune(options.LiteralQuotes, quote)):
+			if i+n >= len(in) {
+				return nil, &LexError{
+					Message: `trailing escape character`,
+					Offset:  i,
+				}
+			}
+
+			if start < 0 {
+				start = i
+			}
+
+			var next, size = utf8.DecodeRuneInString(in[i+n:])
+
+			if verbatim {
+				text.WriteRune(r)
+			}
+
+			text.WriteRune(next)
+			n += size
+
+		case quote != 0:
+			text.WriteRune(r)
+
+		case strings.ContainsRune(options.Quotes, r):
+			// opening quote
+			if start < 0 {
+				start = i
+			}
+
+			quote = r
+			quoteAt = i
+			quoted = true
+
+			if verbatim {
+				text.WriteRune(r)
+			}
+
+		case isOpeningBracket(options.Brackets, r):
+			if start < 0 {
+				start = i
+			}
+
+			brackets = append(brackets, closingBracket(options.Brackets, r))
+			bracketsAt = append(bracketsAt, i)
+			text.WriteRune(r)
+
+		case isClosingBracket(options.Brackets, r):
+			if len(brackets) == 0 || brackets[len(brackets)-1] != r {
+				return nil, &LexError{
+					Message: fmt.Sprintf("unexpected %q", r),
+					Offset:  i,
+				}
+			}
+
+			brackets = brackets[:len(brackets)-1]
+			bracketsAt = bracketsAt[:len(bracketsAt)-1]
+			text.WriteRune(r)
+
+		case !verbatim && isSeparator(r):
+			flush(i)
+
+		default:
+			if start < 0 {
+				start = i
+			}
+
+			text.WriteRune(r)
+		}
+
+		i += n
+	}
+
+	if quote != 0 {
+		return nil, &LexError{
+			Message: fmt.Sprintf("unterminated %c quote", quote),
+			Offset:  quoteAt,
+		}
+	} else if len(brackets) > 0 {
+		return nil, &LexError{
+			Message: fmt.Sprintf("missing %q", brackets[len(brackets)-1]),
+			Offset:  bracketsAt[len(bracketsAt)-1],
+		}
+	}
+
+	flush(len(in))
+
+	return tokens, nil
+}
+
+// Splits the given string into tokens using DefaultLexerOptions, honoring quotes, escapes, and
+// brackets (e.g. `name:"John Smith" and (a or b)` becomes "name:John Smith", "and", "(a or b)").
+func SplitQuoted(in string) ([]string, error) {
+	if tokens, err := Lex(in, nil); err == nil {
+		var out []string
+
+		for _, token := range tokens {
+			out = append(out, token.Text)
+		}
+
+		return out, nil
+	} else {
+		return nil, err
+	}
+}
+
+func isOpeningBracket(pairs string, r rune) bool {
+	for i, b := range []rune(pairs) {
+		if i%2 == 0 && b == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isClosingBracket(pairs string, r rune) bool {
+	for i, b := range []rune(pairs) {
+		if i%2 == 1 && b == r {
+			return true
+		}
+	}
+
+	return false
+}
+
+func closingBracket(pairs string, open rune) rune {
+	var runes = []rune(pairs)
+
+	for i := 0; i+1 < len(runes); i += 2 {
+		if runes[i] == open {
+			return runes[i+1]
+		}
+	}
+
+	return 0
+}
+
+// Returns whether a sorts before b in "natural" order, where runs of digits are compared by their
+// numeric value (so "file2" comes before "file10") and letters are compared case-insensitively.
+// Strings that differ only in case or leading zeros are ordered consistently, with uppercase
+// letters and shorter numbers first.
+func NaturalLess(a string, b string) bool {
+	return naturalCompare(a, b) < 0
+}
+
+// Sorts the given strings in place in natural order (see NaturalLess).
+func NaturalSort(in []string) {
+	sort.SliceStable(in, func(i int, j int) bool {
+		return NaturalLess(in[i], in[j])
+	})
+}
+
+func naturalCompare(a string, b string) int {
+	var i, j int
+	var tiebreak int
+
+	for i < len(a) && j < len(b) {
+		if isASCIIDigit(a[i]) && isASCIIDigit(b[j]) {
+			var si, sj = i, j
+
+			for i < len(a) && isASCIIDigit(a[i]) {
+				i++
+			}
+
+			for j < len(b) && isASCIIDigit(b[j]) {
+				j++
+			}
+
+			var runA, runB = a[si:i], b[sj:j]
+			var numA, numB = strings.TrimLeft(runA, `0`), strings.TrimLeft(runB, `0`)
+
+			// longer numbers (without leading zeros) are larger; equal lengths compare by digit
+			if len(numA) != len(numB) {
+				return compareInts(len(numA), len(numB))
+			} else if c := strings.Compare(numA, numB); c != 0 {
+				return c
+			} else if tiebreak == 0 {
+				tiebreak = compareInts(len(runA), len(runB))
+			}
+
+			continue
+		}
+
+		var ra, na = utf8.DecodeRuneInString(a[i:])
+		var rb, nb = utf8.DecodeRuneInString(b[j:])
+
+		if la, lb := unicode.ToLower(ra), unicode.ToLower(rb); la != lb {
+			return compareInts(int(la), int(lb))
+		} else if tiebreak == 0 && ra != rb {
+			tiebreak = compareInts(int(ra), int(rb))
+		}
+
+		i += na
+		j += nb
+	}
+
+	if c := compareInts(len(a)-i, len(b)-j); c != 0 {
+		return c
+	}
+
+	return tiebreak
+}
+
+func isASCIIDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func compareInts(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,133 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestSplitQuoted(t *testing.T) {
+	assert := require.New(t)
+
+	for in, want := range map[string][]string{
+		``:                      nil,
+		`   `:                   nil,
+		`a b  c`:                {`a`, `b`, `c`},
+		`"hello world" foo`:     {`hello world`, `foo`},
+		`name:"John Smith"`:     {`name:John Smith`},
+		`'single \n quoted' x`:  {`single \n quoted`, `x`},
+		`"esc \"aped\"" y`:      {`esc "aped"`, `y`},
+		`back\ slash`:           {`back slash`},
+		`""  ''`:                {``, ``},
+		`it's" "fine'`:          {`its" "fine`},
+		`a and (b or "c d")`:    {`a`, `and`, `(b or "c d")`},
+		`f(x, [1, 2]) {k: v}`:   {`f(x, [1, 2])`, `{k: v}`},
+		`(")")`:                 {`(")")`},
+		`(a\) b)`:               {`(a\) b)`},
+		"tab\tand\nnewline":     {`tab`, `and`, `newline`},
+		`"unicode: 日本" ünïcödé`: {`unicode: 日本`, `ünïcödé`},
+	} {
+		out, err := SplitQuoted(in)
+		assert.NoError(err, in)
+		assert.Equal(want, out, in)
+	}
+}
+
+func TestSplitQuotedErrors(t *testing.T) {
+	assert := require.New(t)
+
+	for in, want := range map[string]string{
+		`"open`:     `unterminated " quote at position 0`,
+		`x 'open`:   `unterminated ' quote at position 2`,
+		`trailing\`: `trailing escape character at position 8`,
+		`(a b`:      `missing ')' at position 0`,
+		`(a]`:       `unexpected ']' at position 2`,
+		`a)`:        `unexpected ')' at position 1`,
+	} {
+		_, err := SplitQuoted(in)
+		assert.EqualError(err, want, in)
+
+		assert.IsType(&LexError{}, err)
+	}
+}
+
+func TestLexOptions(t *testing.T) {
+	assert := require.New(t)
+
+	tokens, err := Lex(`key="a b",other='c'`, &LexerOptions{
+		IsSeparator: func(r rune) bool {
+			return r == ','
+		},
+		Quotes: `"'`,
+		Escape: '\\',
+	})
+
+	assert.NoError(err)
+	assert.Equal([]LexToken{
+		{Text: `key=a b`, Raw: `key="a b"`, Offset: 0, Quoted: true},
+		{Text: `other=c`, Raw: `other='c'`, Offset: 10, Quoted: true},
+	}, tokens)
+
+	tokens, err = Lex(`a "b c" (d)`, &LexerOptions{
+		Quotes:     `"`,
+		KeepQuotes: true,
+	})
+
+	assert.NoError(err)
+	assert.Len(tokens, 3)
+	assert.Equal(`"b c"`, tokens[1].Text)
+	assert.Equal(`(d)`, tokens[2].Text)
+
+	// without escapes, backslashes are literal
+	out, err := Lex(`a\ b`, &LexerOptions{})
+	assert.NoError(err)
+	assert.Len(out, 2)
+	assert.Equal(`a\`, out[0].Text)
+}
+
+func TestNaturalLess(t *testing.T) {
+	assert := require.New(t)
+
+	assert.True(NaturalLess(`file2`, `file10`))
+	assert.False(NaturalLess(`file10`, `file2`))
+	assert.True(NaturalLess(`a`, `B`))
+	assert.True(NaturalLess(`B`, `c`))
+	assert.True(NaturalLess(`A`, `a`))
+	assert.False(NaturalLess(`a`, `A`))
+	assert.True(NaturalLess(`x1`, `x01`))
+	assert.True(NaturalLess(`x01`, `x2`))
+	assert.True(NaturalLess(`abc`, `abcd`))
+	assert.True(NaturalLess(`1.9`, `1.10`))
+	assert.True(NaturalLess(`99999999999999999999999`, `100000000000000000000000`))
+	assert.False(NaturalLess(`same`, `same`))
+
+	var files = []string{
+		`file10.txt`,
+		`File1.txt`,
+		`file2.txt`,
+		`file01.txt`,
+		`file1.txt`,
+		`file.txt`,
+		`img12b`,
+		`img12a`,
+		`img2`,
+		`v1.10.0`,
+		`v1.9.2`,
+	}
+
+	NaturalSort(files)
+
+	assert.Equal([]string{
+		`file.txt`,
+		`File1.txt`,
+		`file1.txt`,
+		`file01.txt`,
+		`file2.txt`,
+		`file10.txt`,
+		`img2`,
+		`img12a`,
+		`img12b`,
+		`v1.9.2`,
+		`v1.10.0`,
+	}, files)
+}
@@ -2,57 +2,169 @@ package stringutil
 
 import (
 	"bufio"
-	"bytes"
+	"regexp"
+	"regexp/syntax"
+	"unicode/utf8"
 )
 
+// The default number of bytes retained for matching regular expression intercepts across reads.
+var DefaultInterceptWindow = 4096
+
 type InterceptFunc func(seq []byte)
 
+// A handler for regular expression intercepts, which receives the matched bytes and the values
+// of any capture groups (nil for groups that did not participate in the match).
+type InterceptMatchFunc func(match []byte, groups [][]byte)
+
+// An Intercept represents a single sequence or pattern registered with a ScanInterceptor.
+type Intercept struct {
+	sequence     string
+	pattern      *regexp.Regexp
+	maxLength    int
+	contextual   bool
+	handler      InterceptFunc
+	matchHandler InterceptMatchFunc
+	limit        int64
+	count        int64
+	since        int64
+	lastEnd      int64
+}
+
+// Limit the number of times this intercept will fire.  A limit of zero means no limit.
+func (self *Intercept) Limit(n int) *Intercept {
+	self.limit = int64(n)
+	return self
+}
+
+// Only fire this intercept the first time it is seen.
+func (self *Intercept) Once() *Intercept {
+	return self.Limit(1)
+}
+
+// Return the number of times this intercept has fired.
+func (self *Intercept) Count() int64 {
+	return self.count
+}
+
+// Return the literal sequence or regular expression this intercept matches.
+func (self *Intercept) String() string {
+	if self.pattern != nil {
+		return self.pattern.String()
+	}
+
+	return self.sequence
+}
+
+// fires the handler for a match spanning the given absolute stream offsets, unless it ended before
+// the intercept was registered, overlaps the previous match, or the intercept's limit has been
+// reached.
+func (self *Intercept) fire(start int64, end int64, match []byte, groups [][]byte) {
+	if end <= self.since || start < self.lastEnd || (self.limit > 0 && self.count >= self.limit) {
+		return
+	}
+
+	self.lastEnd = end
+	self.count++
+
+	if self.matchHandler != nil {
+		self.matchHandler(match, groups)
+	} else if self.handler != nil {
+		self.handler(match)
+	}
+}
+
 // A ScanInterceptor is used as a SplitFunc on a bufio.Scanner.  It will look at the stream of bytes being scanned for
 // specific substrings.  The registered handler function associated with a substring will be called whenever it is seen
 // in the stream.  The passthrough SplitFunc is called as normal.  This allows for a stream to be
 // split and processed while also being inspected for specific content, allowing the user to react to that content
 // as it comes by.
+//
+// Intercepts are matched against the raw stream (including any delimiters the passthrough SplitFunc strips out), and
+// fire as soon as the data is read, even before it has been returned as a token.  This allows for reacting to prompts
+// that are not followed by a newline.  Literal sequences are matched using an Aho-Corasick automaton, so any number
+// of them can be matched in a single pass.  Regular expressions are matched against a sliding window of the most
+// recent RegexpWindow bytes, so memory use is bounded regardless of the length of the stream.  Intercepts added
+// mid-stream will also match data that began within that window before they were added, as long as the match
+// ends after.
 type ScanInterceptor struct {
-	Disabled           bool
-	accumulator        *bytes.Buffer
-	subsequences       map[string]InterceptFunc
-	interceptStats     map[string]int64
-	longestSubsequence int
-	totalWritten       int64
-	totalAdvanced      int64
-	highWaterMark      map[string]int64
-	passthrough        bufio.SplitFunc
+	Disabled      bool
+	RegexpWindow  int
+	literals      map[string]*Intercept
+	patterns      []*Intercept
+	automaton     *ahoCorasick
+	state         int
+	window        []byte
+	windowStart   int64
+	totalWritten  int64
+	totalAdvanced int64
+	totalScanned  int64
+	passthrough   bufio.SplitFunc
 }
 
 func NewScanInterceptor(passthrough bufio.SplitFunc, intercepts ...map[string]InterceptFunc) *ScanInterceptor {
-	var intercept map[string]InterceptFunc
-
-	if len(intercepts) == 0 {
-		intercept = make(map[string]InterceptFunc)
-	} else {
-		intercept = intercepts[0]
+	// return a new, empty interceptor
+	var interceptor = &ScanInterceptor{
+		RegexpWindow: DefaultInterceptWindow,
+		passthrough:  passthrough,
+		literals:     make(map[string]*Intercept),
 	}
 
-	// return a new, empty interceptor
-	return &ScanInterceptor{
-		passthrough:    passthrough,
-		accumulator:    bytes.NewBuffer(nil),
-		subsequences:   intercept,
-		highWaterMark:  make(map[string]int64),
-		interceptStats: make(map[string]int64),
+	for _, intercept := range intercepts {
+		for sequence, handler := range intercept {
+			interceptor.Intercept(sequence, handler)
+		}
 	}
+
+	return interceptor
 }
 
 // Add an intercept sequence and handler.  If the sequence is already registered, its handler
 // function will be replaced with this one.
-func (self *ScanInterceptor) Intercept(sequence string, handler InterceptFunc) {
-	self.subsequences[sequence] = handler
+func (self *ScanInterceptor) Intercept(sequence string, handler InterceptFunc) *Intercept {
+	if existing, ok := self.literals[sequence]; ok {
+		existing.handler = handler
+		return existing
+	}
 
-	for k, _ := range self.subsequences {
-		if len(k) > self.longestSubsequence {
-			self.longestSubsequence = len(k)
-		}
+	var intercept = &Intercept{
+		sequence: sequence,
+		handler:  handler,
+		since:    self.totalWritten,
+	}
+
+	// zero-length sequences never match
+	if sequence != `` {
+		self.literals[sequence] = intercept
+		self.automaton = nil
+	}
+
+	return intercept
+}
+
+// Add a regular expression intercept.  The handler is called with the matched bytes and the values of any capture
+// groups whenever the pattern matches the stream.  Matches are reported as soon as they are seen, so patterns that
+// could match more data as it arrives (e.g.: "error: .*") should be anchored with a terminating sequence
+// (e.g.: "error: .*\n").
+//
+// Each time data is read, patterns are only searched from the end of their previous match, and patterns with a
+// maximum length (e.g. no "*" or "+") only as far back as a new match could start.  Patterns containing assertions
+// about the preceding text ("^", "\A", "\b", "\B") are searched across the whole window.
+func (self *ScanInterceptor) InterceptRegexp(pattern *regexp.Regexp, handler InterceptMatchFunc) *Intercept {
+	var intercept = &Intercept{
+		pattern:      pattern,
+		matchHandler: handler,
+		since:        self.totalWritten,
+		maxLength:    -1,
+		contextual:   true,
 	}
+
+	if parsed, err := syntax.Parse(pattern.String(), syntax.Perl); err == nil {
+		intercept.maxLength, intercept.contextual = regexpMatchBounds(parsed)
+	}
+
+	self.patterns = append(self.patterns, intercept)
+
+	return intercept
 }
 
 // Implements the bufio.SplitFunc function signature for use in a bufio.Scanner.
@@ -61,76 +173,296 @@ func (self *ScanInterceptor) Scan(data []byte, atEOF bool) (advance int, token [
 	advance, token, err = self.passthrough(data, atEOF)
 
 	if !self.Disabled {
-		if len(token) > 0 {
-			if _, err := self.accumulator.Write(token); err != nil {
-				return 0, nil, err
+		self.totalScanned += int64(len(token))
+
+		// data[0] is always at the stream offset we've advanced to, but we may have already inspected
+		// some (or all) of it on a previous call.  only look at what we haven't seen yet.
+		var seen = self.totalWritten - self.totalAdvanced
+
+		if seen < 0 {
+			// we were disabled for a while; pick up from here
+			self.totalWritten = self.totalAdvanced
+			self.state = 0
+			self.window = nil
+			seen = 0
+		}
+
+		if seen < int64(len(data)) {
+			self.inspect(data[seen:])
+		}
+	}
+
+	if advance > 0 {
+		self.totalAdvanced += int64(advance)
+	}
+
+	// return the results of the SplitFunc we were given
+	return advance, token, err
+}
+
+// feeds newly-read bytes through all registered intercepts.
+func (self *ScanInterceptor) inspect(chunk []byte) {
+	var base = self.totalWritten
+
+	self.totalWritten += int64(len(chunk))
+
+	if len(self.literals) > 0 {
+		if self.automaton == nil {
+			self.automaton = newAhoCorasick(self.literals)
+			self.state = 0
+
+			// replay the recent stream (without firing) so that partial matches in progress when
+			// the automaton was rebuilt can still complete
+			for _, c := range self.window {
+				self.state = self.automaton.next(self.state, c)
 			}
+		}
 
-			// however far we just advanced (if at all), keep track of that
-			self.totalWritten += int64(len(token))
+		for i, c := range chunk {
+			self.state = self.automaton.next(self.state, c)
+
+			for _, intercept := range self.automaton.nodes[self.state].outputs {
+				var end = base + int64(i) + 1
+
+				intercept.fire(end-int64(len(intercept.sequence)), end, []byte(intercept.sequence), nil)
+			}
 		}
+	}
 
-		// if we've accumulated *at least* as many bytes as our longest subsequence, then
-		// we go to work...
-		if processedLen := self.accumulator.Len(); processedLen >= self.longestSubsequence {
-			// get the bytes we've accumulated since start or the last time we reset
-			soFar := self.accumulator.Bytes()
+	if len(self.window) == 0 {
+		self.windowStart = base
+	}
 
-			// for each registered subsequence...
-			for k, handler := range self.subsequences {
-				subseq := []byte(k)
+	self.window = append(self.window, chunk...)
 
-				// skip zero-length matches
-				if len(subseq) == 0 {
-					continue
-				}
+	for _, intercept := range self.patterns {
+		var from = self.windowStart
+
+		// unless the pattern depends on what precedes it (e.g. "^" or "\b"), only search from where
+		// a match that hasn't been reported yet could start: after the last match, and (for patterns
+		// with a maximum length) no further back than could reach into the new data
+		if !intercept.contextual {
+			if intercept.lastEnd > from {
+				from = intercept.lastEnd
+			}
 
-				// the High Water Mark (HWM) represents the furthest we've ever gotten in the stream.
-				// we make sure that our current HWM is *before* the end of the stream, so that if this
-				// SplitFunc is called repeatedy for the same data (which can happen), we're not firing off
-				// multiple handler calls for the same position(s).
-				//
-				if self.highWaterMark[k] > self.totalWritten {
-					continue
+			if intercept.maxLength >= 0 {
+				if earliest := base - int64(intercept.maxLength) + 1; earliest > from {
+					from = earliest
 				}
+			}
+		}
 
-				work := soFar
+		var offset = int(from - self.windowStart)
 
-				// find the index in the stream of our match (if any)
-				for {
-					if indexOf := bytes.Index(work, subseq); indexOf >= 0 {
-						// mark the end of the stream (so we ensure we dont fire events for anything before this point)
-						endIndex := indexOf + len(subseq)
+		for _, loc := range intercept.pattern.FindAllSubmatchIndex(self.window[offset:], -1) {
+			for g := range loc {
+				if loc[g] >= 0 {
+					loc[g] += offset
+				}
+			}
 
-						// fire the handler
-						handler(work[indexOf:endIndex])
-						work = work[endIndex:]
+			// skip zero-length matches
+			if loc[1] <= loc[0] {
+				continue
+			}
 
-						self.interceptStats[k] = self.interceptStats[k] + 1
+			var groups = make([][]byte, 0, len(loc)/2-1)
 
-						// advance the HWM for this interceptor past this result
-						self.highWaterMark[k] = self.totalWritten + int64(endIndex) + 1
-					} else {
-						break
-					}
+			for g := 2; g < len(loc); g += 2 {
+				if loc[g] >= 0 {
+					groups = append(groups, append([]byte(nil), self.window[loc[g]:loc[g+1]]...))
+				} else {
+					groups = append(groups, nil)
 				}
 			}
 
-			// reset the accumulator, we go again!
-			self.accumulator = bytes.NewBuffer(nil)
+			intercept.fire(
+				self.windowStart+int64(loc[0]),
+				self.windowStart+int64(loc[1]),
+				append([]byte(nil), self.window[loc[0]:loc[1]]...),
+				groups,
+			)
 		}
 	}
 
-	// return the results of the SplitFunc we were given
-	return advance, token, err
+	var size = self.RegexpWindow
+
+	if size <= 0 {
+		size = DefaultInterceptWindow
+	}
+
+	if excess := len(self.window) - size; excess > 0 {
+		self.window = append(self.window[:0], self.window[excess:]...)
+		self.windowStart += int64(excess)
+	}
 }
 
-// Return the total number of bytes this scanner has scanned.
+// Return the total number of bytes this scanner has scanned.  Like the tokens returned by the
+// passthrough SplitFunc, this does not include delimiters, nor any data scanned while Disabled.
 func (self *ScanInterceptor) BytesScanned() int64 {
-	return self.totalWritten
+	return self.totalScanned
 }
 
-// Returns a map of intercept sequences and the number of times each one was fired.
+// Returns a map of intercept sequences (and regular expressions) and the number of times each one was fired.
 func (self *ScanInterceptor) InterceptCounts() map[string]int64 {
-	return self.interceptStats
+	var counts = make(map[string]int64)
+
+	for sequence, intercept := range self.literals {
+		counts[sequence] = intercept.count
+	}
+
+	for _, intercept := range self.patterns {
+		counts[intercept.String()] += intercept.count
+	}
+
+	return counts
+}
+
+// returns the maximum length in bytes of a match of the given regular expression (or -1 if it is
+// unbounded), and whether it contains assertions that depend on the text preceding a match.
+func regexpMatchBounds(re *syntax.Regexp) (int, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpEndLine, syntax.OpEndText:
+		return 0, false
+	case syntax.OpBeginLine, syntax.OpBeginText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return 0, true
+	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return len(re.Rune) * utf8.UTFMax, false
+		}
+
+		var n int
+
+		for _, r := range re.Rune {
+			n += utf8.RuneLen(r)
+		}
+
+		return n, false
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return utf8.UTFMax, false
+	case syntax.OpCapture, syntax.OpQuest:
+		return regexpMatchBounds(re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus:
+		var _, contextual = regexpMatchBounds(re.Sub[0])
+		return -1, contextual
+	case syntax.OpRepeat:
+		var n, contextual = regexpMatchBounds(re.Sub[0])
+
+		if re.Max < 0 || n < 0 {
+			return -1, contextual
+		}
+
+		return n * re.Max, contextual
+	case syntax.OpConcat, syntax.OpAlternate:
+		var total int
+		var anyContextual bool
+
+		for _, sub := range re.Sub {
+			var n, contextual = regexpMatchBounds(sub)
+
+			anyContextual = anyContextual || contextual
+
+			if total >= 0 {
+				if n < 0 {
+					total = -1
+				} else if re.Op == syntax.OpConcat {
+					total += n
+				} else if n > total {
+					total = n
+				}
+			}
+		}
+
+		return total, anyContextual
+	default:
+		return -1, true
+	}
+}
+
+type ahoCorasickNode struct {
+	children map[byte]int
+	fail     int
+	outputs  []*Intercept
+}
+
+// an Aho-Corasick automaton for finding many literal sequences in a single pass over a stream.
+type ahoCorasick struct {
+	nodes []ahoCorasickNode
+}
+
+func newAhoCorasick(literals map[string]*Intercept) *ahoCorasick {
+	var ac = &ahoCorasick{
+		nodes: []ahoCorasickNode{{
+			children: make(map[byte]int),
+		}},
+	}
+
+	// build the trie
+	for sequence, intercept := range literals {
+		var node = 0
+
+		for i := 0; i < len(sequence); i++ {
+			next, ok := ac.nodes[node].children[sequence[i]]
+
+			if !ok {
+				next = len(ac.nodes)
+				ac.nodes = append(ac.nodes, ahoCorasickNode{
+					children: make(map[byte]int),
+				})
+				ac.nodes[node].children[sequence[i]] = next
+			}
+
+			node = next
+		}
+
+		ac.nodes[node].outputs = append(ac.nodes[node].outputs, intercept)
+	}
+
+	// breadth-first, link each node to the longest proper suffix that is also in the trie,
+	// and inherit that node's outputs.
+	var queue []int
+
+	for _, child := range ac.nodes[0].children {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		var node = queue[0]
+
+		queue = queue[1:]
+
+		for c, child := range ac.nodes[node].children {
+			var fail = ac.nodes[node].fail
+
+			for fail > 0 {
+				if _, ok := ac.nodes[fail].children[c]; ok {
+					break
+				}
+
+				fail = ac.nodes[fail].fail
+			}
+
+			if next, ok := ac.nodes[fail].children[c]; ok && next != child {
+				ac.nodes[child].fail = next
+			}
+
+			ac.nodes[child].outputs = append(ac.nodes[child].outputs, ac.nodes[ac.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return ac
+}
+
+func (self *ahoCorasick) next(state int, c byte) int {
+	for {
+		if next, ok := self.nodes[state].children[c]; ok {
+			return next
+		} else if state == 0 {
+			return 0
+		}
+
+		state = self.nodes[state].fail
+	}
 }
@@ -3,7 +3,11 @@ package stringutil
 import (
 	"bufio"
 	"bytes"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
 	"testing"
+	"testing/iotest"
 
 	"github.com/ghetzel/testify/require"
 )
@@ -243,3 +247,269 @@ func TestScanInterceptorRepeats(t *testing.T) {
 	assert.Equal(1, father)
 	assert.Equal(3, had)
 }
+
+func TestScanInterceptorRegexp(t *testing.T) {
+	assert := require.New(t)
+	var codes []string
+	var hosts []string
+	var prompts int
+
+	splitter := NewScanInterceptor(bufio.ScanLines)
+
+	splitter.InterceptRegexp(regexp.MustCompile(`error (E\d+): ([^\n]+)\n`), func(match []byte, groups [][]byte) {
+		assert.Len(groups, 2)
+		codes = append(codes, string(groups[0])+`=`+string(groups[1]))
+	})
+
+	splitter.InterceptRegexp(regexp.MustCompile(`connected to (\w+)(?::(\d+))?`), func(match []byte, groups [][]byte) {
+		hosts = append(hosts, string(match))
+		assert.Len(groups, 2)
+	}).Once()
+
+	splitter.InterceptRegexp(regexp.MustCompile(`(?i)password: $`), func(match []byte, groups [][]byte) {
+		prompts++
+		assert.Empty(groups)
+	})
+
+	data := bytes.NewBufferString(
+		"connected to alpha:22\n" +
+			"error E100: disk full\n" +
+			"connected to beta\n" +
+			"error E200: out of memory\n" +
+			"Password: ",
+	)
+
+	scanner := bufio.NewScanner(data)
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		continue
+	}
+
+	assert.NoError(scanner.Err())
+	assert.Equal([]string{`E100=disk full`, `E200=out of memory`}, codes)
+	assert.Equal([]string{`connected to alpha:22`}, hosts)
+	assert.Equal(1, prompts)
+	assert.Equal(map[string]int64{
+		`error (E\d+): ([^\n]+)\n`:      2,
+		`connected to (\w+)(?::(\d+))?`: 1,
+		`(?i)password: $`:               1,
+	}, splitter.InterceptCounts())
+}
+
+func TestScanInterceptorLimits(t *testing.T) {
+	assert := require.New(t)
+	var seen []string
+
+	splitter := NewScanInterceptor(bufio.ScanBytes)
+
+	once := splitter.Intercept(`ping`, func(seq []byte) {
+		seen = append(seen, `once:`+string(seq))
+	}).Once()
+
+	twice := splitter.Intercept(`pong`, func(seq []byte) {
+		seen = append(seen, `twice:`+string(seq))
+	}).Limit(2)
+
+	scanner := bufio.NewScanner(bytes.NewBufferString(`ping pong ping pong ping pong`))
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		continue
+	}
+
+	assert.NoError(scanner.Err())
+	assert.Equal([]string{`once:ping`, `twice:pong`, `twice:pong`}, seen)
+	assert.Equal(int64(1), once.Count())
+	assert.Equal(int64(2), twice.Count())
+	assert.Equal(`pong`, twice.String())
+}
+
+func TestScanInterceptorManyLiterals(t *testing.T) {
+	assert := require.New(t)
+	counts := make(map[string]int)
+
+	intercepts := make(map[string]InterceptFunc)
+
+	for _, word := range []string{`he`, `she`, `his`, `hers`, `aa`} {
+		word := word
+
+		intercepts[word] = func(seq []byte) {
+			assert.Equal(word, string(seq))
+			counts[word]++
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		intercepts[fmt.Sprintf("signature-%04d", i)] = func(seq []byte) {
+			counts[`signature`]++
+		}
+	}
+
+	splitter := NewScanInterceptor(bufio.ScanLines, intercepts)
+
+	// a small read buffer forces matches to span multiple reads
+	scanner := bufio.NewScanner(iotest.OneByteReader(bytes.NewBufferString(
+		"ushers\nsignature-0042 signature-0999\nsignature-1000\naaaa",
+	)))
+
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		continue
+	}
+
+	assert.NoError(scanner.Err())
+	assert.Equal(map[string]int{
+		`she`:       1,
+		`he`:        1,
+		`hers`:      1,
+		`signature`: 2,
+		`aa`:        2,
+	}, counts)
+}
+
+func TestScanInterceptorBoundedWindow(t *testing.T) {
+	assert := require.New(t)
+	var matches int
+
+	splitter := NewScanInterceptor(bufio.ScanLines)
+	splitter.RegexpWindow = 64
+	splitter.InterceptRegexp(regexp.MustCompile(`needle\d`), func(match []byte, groups [][]byte) {
+		matches++
+	})
+
+	var input bytes.Buffer
+
+	for i := 0; i < 10000; i++ {
+		input.WriteString("hay hay hay hay hay hay\n")
+
+		if i%1000 == 0 {
+			input.WriteString("needle1\n")
+		}
+	}
+
+	scanner := bufio.NewScanner(&input)
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		assert.True(len(splitter.window) <= 64)
+	}
+
+	assert.NoError(scanner.Err())
+	assert.Equal(10, matches)
+}
+
+func TestScanInterceptorBytesScanned(t *testing.T) {
+	assert := require.New(t)
+
+	splitter := NewScanInterceptor(bufio.ScanLines)
+	scanner := bufio.NewScanner(bytes.NewBufferString("first\nsecond\r\nthird"))
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		continue
+	}
+
+	// delimiters are not counted
+	assert.NoError(scanner.Err())
+	assert.Equal(int64(len(`first`)+len(`second`)+len(`third`)), splitter.BytesScanned())
+
+	// nor is anything scanned while disabled
+	splitter.Disabled = true
+	scanner = bufio.NewScanner(bytes.NewBufferString("ignored\n"))
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		continue
+	}
+
+	assert.Equal(int64(16), splitter.BytesScanned())
+}
+
+func TestScanInterceptorAddInterceptMidMatch(t *testing.T) {
+	assert := require.New(t)
+	var seen []string
+
+	splitter := NewScanInterceptor(bufio.ScanLines)
+
+	// register new intercepts partway through the sequences they match
+	splitter.Intercept(`user@host's pass`, func(seq []byte) {
+		splitter.Intercept(`password: `, func(seq []byte) {
+			seen = append(seen, string(seq))
+		})
+
+		splitter.InterceptRegexp(regexp.MustCompile(`host's (\w+):`), func(match []byte, groups [][]byte) {
+			seen = append(seen, string(groups[0]))
+		})
+	})
+
+	scanner := bufio.NewScanner(iotest.OneByteReader(bytes.NewBufferString("password: user@host's password: \n")))
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		continue
+	}
+
+	// matches that ended before the intercepts were added are not reported
+	assert.NoError(scanner.Err())
+	assert.Equal([]string{`password`, `password: `}, seen)
+}
+
+func TestScanInterceptorRegexpBounds(t *testing.T) {
+	assert := require.New(t)
+
+	for pattern, bounds := range map[string][2]interface{}{
+		`password: `:          {10, false},
+		`(?i)ok`:              {8, false},
+		`E\d{3}`:              {13, false},
+		`(yes|no)\n?`:         {4, false},
+		`日本`:                  {6, false},
+		`error: .*\n`:         {-1, false},
+		`a{2,}`:               {-1, false},
+		`^\$ $`:               {2, true},
+		`\bword\b`:            {4, true},
+		`(?m)^prompt> (\w+)$`: {-1, true},
+	} {
+		parsed, err := syntax.Parse(pattern, syntax.Perl)
+		assert.NoError(err)
+
+		n, contextual := regexpMatchBounds(parsed)
+		assert.Equal(bounds[0], n, pattern)
+		assert.Equal(bounds[1], contextual, pattern)
+	}
+}
+
+func TestScanInterceptorRegexpIncremental(t *testing.T) {
+	assert := require.New(t)
+	var seen = make(map[string][]string)
+
+	splitter := NewScanInterceptor(bufio.ScanLines)
+
+	for _, pattern := range []string{`E\d{3}`, `error: [^\n]*\n`, `\bok\b`, `(?m)^> `} {
+		var key = pattern
+
+		splitter.InterceptRegexp(regexp.MustCompile(pattern), func(match []byte, groups [][]byte) {
+			seen[key] = append(seen[key], string(match))
+		})
+	}
+
+	// reading one byte at a time, matches spanning many reads are still found exactly once
+	scanner := bufio.NewScanner(iotest.OneByteReader(bytes.NewBufferString(
+		"> E100 error: disk full\nbroken E2000 token\n> ok\nbook\nerror: again\n",
+	)))
+
+	scanner.Split(splitter.Scan)
+
+	for scanner.Scan() {
+		continue
+	}
+
+	assert.NoError(scanner.Err())
+	assert.Equal(map[string][]string{
+		`E\d{3}`:          {`E100`, `E200`},
+		`error: [^\n]*\n`: {"error: disk full\n", "error: again\n"},
+		`\bok\b`:          {`ok`},
+		`(?m)^> `:         {`> `, `> `},
+	}, seen)
+}
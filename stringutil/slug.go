@@ -0,0 +1,260 @@
+package stringutil
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Transliterations used by Transliterate to map non-ASCII characters to ASCII equivalents.
+// Entries may be added or replaced to customize the output.
+var Transliterations = make(map[rune]string)
+
+// Symbols that Transliterate replaces with words. These are separated from any
+// adjacent letters or digits with a space (e.g. "AT&T" becomes "AT and T").
+var TransliterationWords = map[rune]string{
+	'&': `and`,
+	'@': `at`,
+	'%': `percent`,
+	'™': `tm`,
+	'€': `EUR`,
+	'£': `GBP`,
+	'¥': `JPY`,
+	'₹': `INR`,
+	'₽': `RUB`,
+	'°': `deg`,
+}
+
+func init() {
+	for _, group := range [][2]string{
+		// Latin
+		{`ÀÁÂÃÄÅĀĂĄǍǞǠǺȀȂȦȺḀẠẢẤẦẨẪẬẮẰẲẴẶ`, `A`},
+		{`àáâãäåāăąǎǟǡǻȁȃȧḁạảấầẩẫậắằẳẵặ`, `a`},
+		{`ÆǢǼ`, `AE`}, {`æǣǽ`, `ae`},
+		{`ḂḄḆƁ`, `B`}, {`ḃḅḇɓƀ`, `b`},
+		{`ÇĆĈĊČƇȻḈ`, `C`}, {`çćĉċčƈȼḉ`, `c`},
+		{`ÐĎĐƉƊḊḌḎḐḒ`, `D`}, {`ðďđɖɗḋḍḏḑḓ`, `d`},
+		{`ÈÉÊËĒĔĖĘĚȄȆȨƎƏḔḖḘḚḜẸẺẼẾỀỂỄỆ`, `E`},
+		{`èéêëēĕėęěȅȇȩǝəḕḗḙḛḝẹẻẽếềểễệ`, `e`},
+		{`ḞƑ`, `F`}, {`ḟƒ`, `f`},
+		{`ĜĞĠĢǤǦǴƓḠ`, `G`}, {`ĝğġģǥǧǵɠḡ`, `g`},
+		{`ĤĦȞḢḤḦḨḪ`, `H`}, {`ĥħȟḣḥḧḩḫẖ`, `h`},
+		{`ÌÍÎÏĨĪĬĮİǏȈȊƗḬḮỈỊ`, `I`}, {`ìíîïĩīĭįıǐȉȋɨḭḯỉị`, `i`},
+		{`Ĳ`, `IJ`}, {`ĳ`, `ij`},
+		{`Ĵ`, `J`}, {`ĵǰȷ`, `j`},
+		{`ĶǨƘḰḲḴ`, `K`}, {`ķĸǩƙḱḳḵ`, `k`},
+		{`ĹĻĽĿŁȽḶḸḺḼ`, `L`}, {`ĺļľŀłƚḷḹḻḽ`, `l`},
+		{`ḾṀṂ`, `M`}, {`ḿṁṃ`, `m`},
+		{`ÑŃŅŇŊǸƝṄṆṈṊ`, `N`}, {`ñńņňŉŋǹɲṅṇṉṋ`, `n`},
+		{`ÒÓÔÕÖØŌŎŐƟƠǑǪǬǾȌȎȪȬȮȰṌṎṐṒỌỎỐỒỔỖỘỚỜỞỠỢ`, `O`},
+		{`òóôõöøōŏőɵơǒǫǭǿȍȏȫȭȯȱṍṏṑṓọỏốồổỗộớờởỡợ`, `o`},
+		{`Œ`, `OE`}, {`œ`, `oe`},
+		{`ṔṖƤ`, `P`}, {`ṕṗƥ`, `p`},
+		{`ŔŖŘȐȒṘṚṜṞ`, `R`}, {`ŕŗřȑȓṙṛṝṟ`, `r`},
+		{`ŚŜŞŠȘṠṢṤṦṨ`, `S`}, {`śŝşšșſṡṣṥṧṩ`, `s`},
+		{`ẞ`, `SS`}, {`ß`, `ss`},
+		{`ŢŤŦȚƬƮṪṬṮṰ`, `T`}, {`ţťŧțƭʈṫṭṯṱẗ`, `t`},
+		{`Þ`, `TH`}, {`þ`, `th`},
+		{`ÙÚÛÜŨŪŬŮŰŲƯǓǕǗǙǛȔȖɄṲṴṶṸṺỤỦỨỪỬỮỰ`, `U`},
+		{`ùúûüũūŭůűųưǔǖǘǚǜȕȗʉṳṵṷṹṻụủứừửữự`, `u`},
+		{`ṼṾƲ`, `V`}, {`ṽṿʋ`, `v`},
+		{`ŴẀẂẄẆẈ`, `W`}, {`ŵẁẃẅẇẉẘ`, `w`},
+		{`ẊẌ`, `X`}, {`ẋẍ`, `x`},
+		{`ÝŶŸƳȲẎỲỴỶỸ`, `Y`}, {`ýÿŷƴȳẏẙỳỵỷỹ`, `y`},
+		{`ŹŻŽƵȤẐẒẔ`, `Z`}, {`źżžƶȥẑẓẕ`, `z`},
+
+		// ligatures
+		{`ﬀ`, `ff`}, {`ﬁ`, `fi`}, {`ﬂ`, `fl`}, {`ﬃ`, `ffi`}, {`ﬄ`, `ffl`}, {`ﬅﬆ`, `st`},
+		{`Ǆ`, `DZ`}, {`ǅ`, `Dz`}, {`ǆ`, `dz`}, {`Ǉ`, `LJ`}, {`ǈ`, `Lj`}, {`ǉ`, `lj`},
+		{`Ǌ`, `NJ`}, {`ǋ`, `Nj`}, {`ǌ`, `nj`},
+
+		// Greek
+		{`ΑΆ`, `A`}, {`αά`, `a`}, {`Β`, `B`}, {`β`, `b`}, {`Γ`, `G`}, {`γ`, `g`},
+		{`Δ`, `D`}, {`δ`, `d`}, {`ΕΈ`, `E`}, {`εέ`, `e`}, {`Ζ`, `Z`}, {`ζ`, `z`},
+		{`ΗΉ`, `I`}, {`ηή`, `i`}, {`Θ`, `TH`}, {`θ`, `th`}, {`ΙΊΪ`, `I`}, {`ιίϊΐ`, `i`},
+		{`Κ`, `K`}, {`κ`, `k`}, {`Λ`, `L`}, {`λ`, `l`}, {`Μ`, `M`}, {`μ`, `m`},
+		{`Ν`, `N`}, {`ν`, `n`}, {`Ξ`, `X`}, {`ξ`, `x`}, {`ΟΌ`, `O`}, {`οό`, `o`},
+		{`Π`, `P`}, {`π`, `p`}, {`Ρ`, `R`}, {`ρ`, `r`}, {`Σ`, `S`}, {`σς`, `s`},
+		{`Τ`, `T`}, {`τ`, `t`}, {`ΥΎΫ`, `Y`}, {`υύϋΰ`, `y`}, {`Φ`, `F`}, {`φ`, `f`},
+		{`Χ`, `CH`}, {`χ`, `ch`}, {`Ψ`, `PS`}, {`ψ`, `ps`}, {`ΩΏ`, `O`}, {`ωώ`, `o`},
+
+		// Cyrillic
+		{`А`, `A`}, {`а`, `a`}, {`Б`, `B`}, {`б`, `b`}, {`В`, `V`}, {`в`, `v`},
+		{`ГҐ`, `G`}, {`гґ`, `g`}, {`Д`, `D`}, {`д`, `d`}, {`ЕЭ`, `E`}, {`еэ`, `e`},
+		{`Ё`, `Yo`}, {`ё`, `yo`}, {`Є`, `Ye`}, {`є`, `ye`}, {`Ж`, `Zh`}, {`ж`, `zh`},
+		{`З`, `Z`}, {`з`, `z`}, {`ИІ`, `I`}, {`иі`, `i`}, {`Ї`, `Yi`}, {`ї`, `yi`},
+		{`ЙЫ`, `Y`}, {`йы`, `y`}, {`КЌ`, `K`}, {`кќ`, `k`}, {`Л`, `L`}, {`л`, `l`},
+		{`М`, `M`}, {`м`, `m`}, {`Н`, `N`}, {`н`, `n`}, {`О`, `O`}, {`о`, `o`},
+		{`П`, `P`}, {`п`, `p`}, {`Р`, `R`}, {`р`, `r`}, {`С`, `S`}, {`с`, `s`},
+		{`Т`, `T`}, {`т`, `t`}, {`УЎ`, `U`}, {`уў`, `u`}, {`Ф`, `F`}, {`ф`, `f`},
+		{`Х`, `Kh`}, {`х`, `kh`}, {`Ц`, `Ts`}, {`ц`, `ts`}, {`Ч`, `Ch`}, {`ч`, `ch`},
+		{`Ш`, `Sh`}, {`ш`, `sh`}, {`Щ`, `Shch`}, {`щ`, `shch`}, {`ЪЬъь`, ``},
+		{`Ю`, `Yu`}, {`ю`, `yu`}, {`Я`, `Ya`}, {`я`, `ya`}, {`Ђ`, `Dj`}, {`ђ`, `dj`},
+		{`Ј`, `J`}, {`ј`, `j`}, {`Љ`, `Lj`}, {`љ`, `lj`}, {`Њ`, `Nj`}, {`њ`, `nj`},
+		{`Ћ`, `C`}, {`ћ`, `c`}, {`Џ`, `Dz`}, {`џ`, `dz`}, {`Ѕ`, `Dz`}, {`ѕ`, `dz`},
+
+		// punctuation and symbols
+		{"      ", ` `},
+		{`‘’‚‛′`, `'`}, {`“”„‟″«»`, `"`}, {`‐‑‒–—―−`, `-`}, {`…`, `...`},
+		{`‹`, `<`}, {`›`, `>`}, {`×`, `x`}, {`÷`, `/`}, {`•·`, `*`},
+		{`©`, `(c)`}, {`®`, `(r)`}, {`¹`, `1`}, {`²`, `2`}, {`³`, `3`},
+		{`¼`, `1/4`}, {`½`, `1/2`}, {`¾`, `3/4`}, {`¡`, `!`}, {`¿`, `?`},
+	} {
+		for _, r := range group[0] {
+			Transliterations[r] = group[1]
+		}
+	}
+}
+
+// Converts the given string to ASCII, replacing accented Latin characters, Greek and Cyrillic
+// letters, ligatures, and common symbols with their closest ASCII equivalents.  Combining marks
+// are removed, and characters with no known equivalent are dropped.
+func Transliterate(in string) string {
+	var out strings.Builder
+	var runes = []rune(in)
+
+	for i, r := range runes {
+		if word, ok := TransliterationWords[r]; ok {
+			out.WriteString(spaceIfAlnum(runes, i-1) + word + spaceIfAlnum(runes, i+1))
+		} else if r < utf8.RuneSelf {
+			out.WriteRune(r)
+		} else if repl, ok := Transliterations[r]; ok {
+			out.WriteString(repl)
+		}
+	}
+
+	return out.String()
+}
+
+func spaceIfAlnum(runes []rune, i int) string {
+	if i >= 0 && i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+		return ` `
+	}
+
+	return ``
+}
+
+// Options that control the output of Slugify.
+type SlugOptions struct {
+	// The string placed between words (default: "-").
+	Separator string
+
+	// If greater than zero, slugs are truncated to at most this many characters, breaking on
+	// word boundaries where possible.
+	MaxLength int
+
+	// Preserve the case of the input rather than lowercasing it.
+	PreserveCase bool
+}
+
+// Returns a URL- and filename-safe version of the given string, consisting only of ASCII
+// letters and digits separated by a separator.  Non-ASCII input is transliterated first. If
+// opts is nil, the slug is lowercase and hyphen-separated.
+func Slugify(in string, opts *SlugOptions) string {
+	if opts == nil {
+		opts = new(SlugOptions)
+	}
+
+	var separator = opts.Separator
+
+	if separator == `` {
+		separator = `-`
+	}
+
+	var words = strings.FieldsFunc(Transliterate(in), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+
+	if !opts.PreserveCase {
+		for i, word := range words {
+			words[i] = strings.ToLower(word)
+		}
+	}
+
+	return joinWithin(words, separator, opts.MaxLength)
+}
+
+// Same as Slugify, but ensures that the returned slug is unique by calling exists with each
+// candidate. If the slug is taken, successive numeric suffixes are tried (e.g. "name-2",
+// "name-3") until one is found for which exists returns false.  The slug is shortened to make
+// room for the suffix within opts.MaxLength; if the limit is too small for any of it, the number
+// is used on its own.
+func SlugifyUnique(in string, opts *SlugOptions, exists func(slug string) bool) string {
+	var slug = Slugify(in, opts)
+
+	if exists == nil || !exists(slug) {
+		return slug
+	}
+
+	if opts == nil {
+		opts = new(SlugOptions)
+	}
+
+	var separator = opts.Separator
+
+	if separator == `` {
+		separator = `-`
+	}
+
+	for n := 2; ; n++ {
+		var suffix = separator + strconv.Itoa(n)
+		var base = slug
+
+		// make room for the suffix within the maximum length
+		if opts.MaxLength > 0 && len(base)+len(suffix) > opts.MaxLength {
+			if room := opts.MaxLength - len(suffix); room > 0 {
+				base = joinWithin(strings.Split(slug, separator), separator, room)
+			} else {
+				base = ``
+			}
+		}
+
+		var candidate = base + suffix
+
+		// if there is no room for any of the slug, the number alone is used
+		if base == `` {
+			candidate = strconv.Itoa(n)
+		}
+
+		if !exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+// joins as many words as will fit within maxLength characters. If even the first word will
+// not fit, it is truncated.
+func joinWithin(words []string, separator string, maxLength int) string {
+	var out = strings.Join(words, separator)
+
+	if maxLength <= 0 || len(out) <= maxLength {
+		return out
+	}
+
+	out = ``
+
+	for _, word := range words {
+		if word == `` {
+			continue
+		}
+
+		var next = word
+
+		if out != `` {
+			next = out + separator + word
+		}
+
+		if len(next) > maxLength {
+			if out == `` {
+				out = word[:maxLength]
+			}
+
+			break
+		}
+
+		out = next
+	}
+
+	return out
+}
@@ -0,0 +1,127 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestTransliterate(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`hello`, Transliterate(`hello`))
+	assert.Equal(`Creme Brulee`, Transliterate(`Crème Brûlée`))
+	assert.Equal(`Strasse`, Transliterate(`Straße`))
+	assert.Equal(`AEther OEuvre`, Transliterate(`Æther Œuvre`))
+	assert.Equal(`Lodz`, Transliterate(`Łódź`))
+	assert.Equal(`THorn`, Transliterate(`Þorn`))
+	assert.Equal(`Athina`, Transliterate(`Αθήνα`))
+	assert.Equal(`Moskva`, Transliterate(`Москва`))
+	assert.Equal(`Shchuka`, Transliterate(`Щука`))
+	assert.Equal(`Ukrayina`, Transliterate(`Україна`))
+	assert.Equal(`office fluff`, Transliterate(`oﬃce ﬂuﬀ`))
+	assert.Equal(`Tieng Viet`, Transliterate(`Tiếng Việt`))
+	assert.Equal(`"quoted" - it's...`, Transliterate(`“quoted” — it’s…`))
+
+	// decomposed input loses its combining marks
+	assert.Equal(`cafe`, Transliterate("cafe\u0301"))
+
+	// symbols become words
+	assert.Equal(`AT and T`, Transliterate(`AT&T`))
+	assert.Equal(`Tom and Jerry`, Transliterate(`Tom & Jerry`))
+	assert.Equal(`me at home`, Transliterate(`me@home`))
+	assert.Equal(`100 percent`, Transliterate(`100%`))
+	assert.Equal(`(c) 2020`, Transliterate(`© 2020`))
+
+	// unknown characters are dropped
+	assert.Equal(`ab`, Transliterate(`a漢b`))
+}
+
+func TestSlugify(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(``, Slugify(``, nil))
+	assert.Equal(`hello-world`, Slugify(`Hello, World!`, nil))
+	assert.Equal(`creme-brulee-recipe`, Slugify(`  Crème Brûlée -- Recipe  `, nil))
+	assert.Equal(`at-and-t-mobility`, Slugify(`AT&T Mobility`, nil))
+	assert.Equal(`privet-mir`, Slugify(`Привет, мир`, nil))
+	assert.Equal(`version-2-0-release`, Slugify(`Version 2.0 Release`, nil))
+
+	assert.Equal(`hello_world`, Slugify(`Hello World`, &SlugOptions{
+		Separator: `_`,
+	}))
+
+	assert.Equal(`Hello-World`, Slugify(`Hello World`, &SlugOptions{
+		PreserveCase: true,
+	}))
+
+	// truncation happens on word boundaries
+	assert.Equal(`the-quick-brown`, Slugify(`The quick brown fox jumps`, &SlugOptions{
+		MaxLength: 18,
+	}))
+
+	assert.Equal(`the-quick-brown-fox`, Slugify(`The quick brown fox jumps`, &SlugOptions{
+		MaxLength: 19,
+	}))
+
+	// unless the first word is too long on its own
+	assert.Equal(`supercalif`, Slugify(`Supercalifragilistic word`, &SlugOptions{
+		MaxLength: 10,
+	}))
+}
+
+func TestSlugifyUnique(t *testing.T) {
+	assert := require.New(t)
+
+	taken := map[string]bool{
+		`hello-world`:   true,
+		`hello-world-2`: true,
+		`the-quick`:     true,
+	}
+
+	exists := func(slug string) bool {
+		return taken[slug]
+	}
+
+	assert.Equal(`new-post`, SlugifyUnique(`New Post`, nil, exists))
+	assert.Equal(`hello-world-3`, SlugifyUnique(`Hello World`, nil, exists))
+	assert.Equal(`hello_world`, SlugifyUnique(`Hello World`, &SlugOptions{
+		Separator: `_`,
+	}, exists))
+
+	taken[`hello_world`] = true
+	assert.Equal(`hello_world_2`, SlugifyUnique(`Hello World`, &SlugOptions{
+		Separator: `_`,
+	}, exists))
+
+	// suffixes still fit within the maximum length
+	assert.Equal(`the-2`, SlugifyUnique(`The quick brown fox`, &SlugOptions{
+		MaxLength: 9,
+	}, exists))
+
+	// limits too small for the slug and its suffix never return more than the limit allows
+	for _, limit := range []int{1, 2, 3} {
+		var slug = SlugifyUnique(`Hello World`, &SlugOptions{
+			MaxLength: limit,
+		}, func(slug string) bool {
+			return slug == `hello-world`[:limit]
+		})
+
+		assert.True(len(slug) <= limit, slug)
+		assert.NotEqual(`hello-world`[:limit], slug)
+	}
+
+	taken[`h`] = true
+	taken[`2`] = true
+	assert.Equal(`3`, SlugifyUnique(`Hello World`, &SlugOptions{
+		MaxLength: 1,
+	}, exists))
+
+	assert.Equal(`h-2`, SlugifyUnique(`Hello World`, &SlugOptions{
+		MaxLength: 3,
+	}, func(slug string) bool {
+		return slug == `hel`
+	}))
+
+	assert.Equal(`hello-world`, SlugifyUnique(`Hello World`, nil, nil))
+}
@@ -4,7 +4,6 @@ package stringutil
 import (
 	"fmt"
 	"math"
-	"os"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -12,14 +11,12 @@ import (
 	"time"
 	"unicode"
 
-	"github.com/ghetzel/go-stockutil/rxutil"
 	"github.com/ghetzel/go-stockutil/typeutil"
 	"github.com/ghetzel/go-stockutil/utils"
 	"github.com/jdkato/prose/tokenize"
 )
 
 var rxHexadecimal = regexp.MustCompile(`^[0-9a-fA-F]+$`)
-var rxEnvVarExpr = regexp.MustCompile(`(\$\{(?P<env>\w+)(?:\|(?P<fallback>.*?))?(?::(?P<fmt>%[^\}]+))?\})`) // ${ENV}, ${ENV:%04s}, ${ENV|fallback}, ${ENV|fallback:%04s}
 var DefaultThousandsSeparator = `,`
 var DefaultDecimalSeparator = `.`
 
@@ -29,7 +26,11 @@ var DefaultDecimalSeparator = `.`
 // the case when running ExpandEnv() against various shell languages (Bash, et. al)
 //
 var ExpandEnvPreserveIfEmpty = false
-var ExpandEnvTempDelimiterOpen = "\u3018"  // LEFT WHITE TORTOISE SHELL BRACKET (U+3018, Ps): 〘
+
+// Deprecated: ExpandEnv no longer uses temporary delimiters when preserving empty sequences.
+var ExpandEnvTempDelimiterOpen = "\u3018" // LEFT WHITE TORTOISE SHELL BRACKET (U+3018, Ps): 〘
+
+// Deprecated: ExpandEnv no longer uses temporary delimiters when preserving empty sequences.
 var ExpandEnvTempDelimiterClose = "\u3019" // RIGHT WHITE TORTOISE SHELL BRACKET (U+3019, Pe): 〙
 
 var NilStrings = utils.NilStrings
@@ -346,6 +347,18 @@ func ConvertToTime(in interface{}) (time.Time, error) {
 	return utils.ConvertToTime(in)
 }
 
+// Converts the given value to a time, resolving relative and natural language expressions
+// (e.g.: "3 days ago", "in 2h", "yesterday 14:00", "next monday", "end of month") against the
+// given reference time.  Values without an explicit time zone are interpreted in the given location.
+func ConvertToTimeIn(in interface{}, reference time.Time, loc *time.Location) (time.Time, error) {
+	return utils.ConvertToTimeIn(in, reference, loc)
+}
+
+// Parses a relative or natural language time expression against the given reference time.
+func ParseRelativeTime(in string, reference time.Time) (time.Time, error) {
+	return utils.ParseRelativeTime(in, reference)
+}
+
 func ConvertToBytes(in interface{}) ([]byte, error) {
 	return utils.ConvertToBytes(in)
 }
@@ -399,53 +412,26 @@ func TokenizeFunc(in string, tokenizer func(rune) bool, partfn func(part string)
 	return out
 }
 
+// Returns the given value in PascalCase.  This is the same as ToPascalCase, and uses the initialisms
+// and word boundaries of DefaultCaseConverter.
 func Camelize(in interface{}) string {
-	return strings.Join(TokenizeFunc(MustString(in), IsSeparator, func(part string) []string {
-		part = strings.TrimSpace(part)
-		part = strings.Title(part)
-		return []string{part}
-	}), ``)
+	return ToPascalCase(in)
 }
 
+// Returns the given value in snake_case.  This is the same as ToSnakeCase.
 func Underscore(in interface{}) string {
-	return Snakeify(in, '_')
+	return ToSnakeCase(in)
 }
 
+// Returns the given value in kebab-case.  This is the same as ToKebabCase.
 func Hyphenate(in interface{}) string {
-	return Snakeify(in, '-')
+	return ToKebabCase(in)
 }
 
+// Returns the words of the given value in lowercase, joined by the given separator.  Words are split
+// in the same way as SplitCaseWords.
 func Snakeify(in interface{}, separator rune) string {
-	inS := strings.Map(func(r rune) rune {
-		if unicode.IsSpace(r) {
-			return separator
-		} else if unicode.IsPunct(r) && separator != r {
-			return separator
-		} else {
-			return r
-		}
-	}, MustString(in))
-
-	out := make([]rune, 0)
-	runes := []rune(inS)
-
-	sepfn := func(i int) bool {
-		return i >= 0 && i < len(runes) && unicode.IsLower(runes[i])
-	}
-
-	for i, r := range runes {
-		if unicode.IsUpper(r) {
-			r = unicode.ToLower(r)
-
-			if i > 0 && runes[i-1] != separator && (sepfn(i-1) || sepfn(i+1)) {
-				out = append(out, separator)
-			}
-		}
-
-		out = append(out, r)
-	}
-
-	return string(out)
+	return DefaultCaseConverter.join(SplitCaseWords(in), string(separator), strings.ToLower)
 }
 
 // Returns whether the letters (Unicode Catgeory 'L') in a given string are
@@ -845,22 +831,24 @@ func SplitWords(in string) []string {
 	return out
 }
 
-// Truncate the given string to a certain number of characters.
+// Truncate the given string to a certain number of characters.  Strings that already fit are
+// returned unchanged, without the trailer.
 func Elide(in string, charcount int, trailer ...string) string {
-	if len(in) < charcount {
-		return in
+	if out, cut := truncateWidth(in, charcount, false); cut {
+		return out + strings.Join(trailer, ``)
 	}
 
-	return in[0:charcount] + strings.Join(trailer, ``)
+	return in
 }
 
-// Truncate the given string to a certain number of characters from the end.
+// Truncate the given string to a certain number of characters from the end.  Strings that
+// already fit are returned unchanged, without the leader.
 func ElideRight(in string, charcount int, leader ...string) string {
-	if len(in) < charcount {
-		return in
+	if out, cut := truncateWidth(in, charcount, true); cut {
+		return strings.Join(leader, ``) + out
 	}
 
-	return strings.Join(leader, ``) + in[len(in)-charcount:]
+	return in
 }
 
 // Truncate the given string to a certain number of words.
@@ -920,80 +908,43 @@ func SqueezeSpace(in string) string {
 	})
 }
 
-// Return the given string with environment variable substitution sequences
-// expanded and (optionally) formatted.  This function operates similarly to
-// os.ExpandEnv, but accepts custom fmt.Printf formatting directives.
-func ExpandEnv(in string) string {
-	for {
-		if match := rxutil.Match(rxEnvVarExpr, in); match != nil {
-			var format = match.Group(`fmt`)
-			var varname = match.Group(`env`)
-			var fallback = match.Group(`fallback`)
-
-			if varname != `` {
-				if format == `` {
-					format = `%v`
-				}
-
-				var typed interface{}
-
-				if val := os.Getenv(varname); val != `` {
-					typed = Autotype(val)
-				} else {
-					typed = Autotype(fallback)
-				}
-
-				if typed != nil {
-					in = match.ReplaceGroup(1, fmt.Sprintf(format, typed))
-				} else if ExpandEnvPreserveIfEmpty {
-					// this One Weird Trick™ always weirds me out, but here goes
-					// we're in a loop that recursively expands all ${...} sequences and eliminates those sequences
-					// from the output *UNLESS* ExpandEnvPreserveIfEmpty==true.  In that case, we're saying
-					// "if this sequence would expand to an empty string, leave it alone in the output."
-					//
-					// Problem is, that creates an infinite loop of replacing the same sequence with itself forever.
-					// So what we do instead is we swap out the delimiters denoting the start and end of the expansion
-					// sequence with a Unicode character that is _very_ unlikely to appear in the types of strings this
-					// function is expected to process.  We then go back, outside of the loop, and swap those characters back
-					// with the correct delimiters.
-					//
-					// It's a bit janky, for sure, but it works. If you somehow end up with a conflict with these specific
-					// temporary delimiters, you can change the package-level variables to some less-problematic characters
-					// to your use case.
-
-					in = match.ReplaceGroup(1, fmt.Sprintf(format, ExpandEnvTempDelimiterOpen+varname+ExpandEnvTempDelimiterClose))
-				} else {
-					in = match.ReplaceGroup(1, fmt.Sprintf(format, ``))
-				}
-			}
-		} else {
-			break
-		}
-	}
-
-	in = strings.ReplaceAll(in, ExpandEnvTempDelimiterOpen, `${`)
-	in = strings.ReplaceAll(in, ExpandEnvTempDelimiterClose, `}`)
-
-	return in
-}
-
 // Takes the given string, splits it into lines, and prefixes each line with the given prefix string.
+// ANSI text attributes (e.g. colors) that carry over from one line to the next are not applied to
+// the prefix.
 func PrefixLines(in interface{}, prefix string) string {
 	lines := SplitLines(in, "\n")
+	active := ``
 
 	for i, line := range lines {
-		lines[i] = prefix + line
+		if active != `` {
+			lines[i] = ansiReset + prefix + active + line
+		} else {
+			lines[i] = prefix + line
+		}
+
+		active = activeSGR(active, line)
 	}
 
 	return strings.Join(lines, "\n")
 }
 
 // Takes the given string, splits it into lines, and suffixes each line with the given suffix string.
+// ANSI text attributes (e.g. colors) left active at the end of a line are not applied to the suffix.
 func SuffixLines(in interface{}, suffix string) string {
 	lines := SplitLines(in, "\n")
+	active := ``
 
 	for i, line := range lines {
-		lines[i] = line + suffix
+		if active = activeSGR(active, line); active != `` {
+			lines[i] = line + ansiReset + suffix
+
+			// restore the attributes for the next line
+			if i < len(lines)-1 {
+				lines[i] += active
+			}
+		} else {
+			lines[i] = line + suffix
+		}
 	}
 
 	return strings.Join(lines, "\n")
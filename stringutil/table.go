@@ -0,0 +1,620 @@
+package stringutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghetzel/go-stockutil/typeutil"
+	"github.com/mgutz/ansi"
+)
+
+// Represents an output format for a Table.
+type TableFormat int
+
+const (
+	PlainTable    TableFormat = iota // columns aligned with spaces
+	BoxTable                         // columns surrounded by box-drawing borders
+	MarkdownTable                    // a GitHub-flavored Markdown table
+	CSVTable                         // comma-separated values
+	TSVTable                         // tab-separated values
+)
+
+func (self TableFormat) String() string {
+	switch self {
+	case PlainTable:
+		return `plain`
+	case BoxTable:
+		return `box`
+	case MarkdownTable:
+		return `markdown`
+	case CSVTable:
+		return `csv`
+	case TSVTable:
+		return `tsv`
+	default:
+		return ``
+	}
+}
+
+// Represents how the values in a table column are aligned.
+type ColumnAlignment int
+
+const (
+	ColumnLeft ColumnAlignment = iota
+	ColumnRight
+	ColumnCenter
+)
+
+// The string appended to values that are truncated to fit a column's MaxWidth.
+var TableEllipsis = `…`
+
+// The string placed between columns in a PlainTable.
+var TableColumnSeparator = `  `
+
+// Describes a single column in a Table.
+type TableColumn struct {
+	// The text shown in the column's header.
+	Name string
+
+	// How values in this column are aligned.
+	Align ColumnAlignment
+
+	// If greater than zero, values wider than this many terminal columns are truncated and
+	// suffixed with TableEllipsis.  This does not apply to CSV or TSV output.
+	MaxWidth int
+}
+
+// A Table renders rows of values as aligned text in a variety of formats.  Column widths are
+// calculated using DisplayWidth, so values containing ANSI escape sequences and wide characters
+// line up correctly.
+type Table struct {
+	// The format used by Write and String.
+	Format TableFormat
+
+	// The columns of the table, in order.
+	Columns []TableColumn
+
+	// The values in each row.  Rows with fewer values than there are columns are padded with
+	// empty values.
+	Rows [][]string
+
+	// An ANSI color expression (as used by the log package, e.g. "green+b" or "${green+b}") that
+	// is applied to the header row in plain and box tables.
+	HeaderStyle string
+
+	// Omit the header row.  This does not apply to Markdown tables, which require one.
+	HideHeader bool
+}
+
+// Returns a new Table with the given column names.
+func NewTable(columns ...string) *Table {
+	var table = new(Table)
+
+	for _, name := range columns {
+		table.Columns = append(table.Columns, TableColumn{
+			Name: name,
+		})
+	}
+
+	return table
+}
+
+// Returns a new Table populated from the given data, which may be a [][]string (where the first
+// row is the header), a slice of maps (whose keys become the columns, in sorted order), or a
+// slice of structs (whose exported fields become the columns, honoring "json" struct tags).
+func TableFrom(data interface{}) (*Table, error) {
+	switch rows := data.(type) {
+	case [][]string:
+		if len(rows) == 0 {
+			return NewTable(), nil
+		}
+
+		var table = NewTable(rows[0]...)
+
+		for _, row := range rows[1:] {
+			table.Rows = append(table.Rows, append([]string(nil), row...))
+		}
+
+		return table, nil
+	}
+
+	var rows = reflect.ValueOf(data)
+
+	if rows.Kind() != reflect.Slice && rows.Kind() != reflect.Array {
+		return nil, fmt.Errorf("Cannot build table from %T: expected a slice", data)
+	}
+
+	var elemType = rows.Type().Elem()
+
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	switch elemType.Kind() {
+	case reflect.Map:
+		return tableFromMaps(rows)
+	case reflect.Struct:
+		return tableFromStructs(rows, elemType)
+	case reflect.Interface:
+		// a slice of interface{} can hold maps or structs; use the first non-nil element to decide
+		for i := 0; i < rows.Len(); i++ {
+			if first := indirectValue(rows.Index(i)); first.IsValid() {
+				switch first.Kind() {
+				case reflect.Map:
+					return tableFromMaps(rows)
+				case reflect.Struct:
+					return tableFromStructs(rows, first.Type())
+				}
+
+				return nil, fmt.Errorf("Cannot build table from %T: elements must be maps or structs", data)
+			}
+		}
+
+		return NewTable(), nil
+	}
+
+	return nil, fmt.Errorf("Cannot build table from %T: elements must be maps or structs", data)
+}
+
+func tableFromMaps(rows reflect.Value) (*Table, error) {
+	var seen = make(map[string]bool)
+	var names []string
+
+	for i := 0; i < rows.Len(); i++ {
+		if row := indirectValue(rows.Index(i)); row.IsValid() {
+			for _, key := range row.MapKeys() {
+				if name := typeutil.String(key.Interface()); !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	var table = NewTable(names...)
+
+	for i := 0; i < rows.Len(); i++ {
+		var values = make([]interface{}, len(names))
+
+		if row := indirectValue(rows.Index(i)); row.IsValid() {
+			for _, key := range row.MapKeys() {
+				var name = typeutil.String(key.Interface())
+				var c = sort.SearchStrings(names, name)
+
+				values[c] = row.MapIndex(key).Interface()
+			}
+		}
+
+		table.AddRow(values...)
+	}
+
+	return table, nil
+}
+
+type tableField struct {
+	name  string
+	index []int
+}
+
+func tableFromStructs(rows reflect.Value, elemType reflect.Type) (*Table, error) {
+	var fields = tableFields(elemType, nil)
+	var table = NewTable()
+
+	for _, field := range fields {
+		table.Columns = append(table.Columns, TableColumn{
+			Name: field.name,
+		})
+	}
+
+	for i := 0; i < rows.Len(); i++ {
+		var values = make([]interface{}, len(fields))
+
+		if row := indirectValue(rows.Index(i)); row.IsValid() {
+			if row.Type() != elemType {
+				return nil, fmt.Errorf("Cannot build table: row %d is a %v, expected %v", i, row.Type(), elemType)
+			}
+
+			for c, field := range fields {
+				if value := fieldByIndex(row, field.index); value.IsValid() {
+					values[c] = value.Interface()
+				}
+			}
+		}
+
+		table.AddRow(values...)
+	}
+
+	return table, nil
+}
+
+// returns the exported fields of the given struct type, named according to their "json" tags.
+// embedded structs without a tag have their fields promoted.
+func tableFields(structType reflect.Type, parent []int) []tableField {
+	var fields []tableField
+
+	for i := 0; i < structType.NumField(); i++ {
+		var field = structType.Field(i)
+		var index = append(append([]int(nil), parent...), i)
+		var name = field.Name
+		var tag = strings.SplitN(field.Tag.Get(`json`), `,`, 2)[0]
+
+		if tag == `-` {
+			continue
+		} else if tag != `` {
+			name = tag
+		} else if field.Anonymous {
+			var embedded = field.Type
+
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, tableFields(embedded, index)...)
+				continue
+			}
+		}
+
+		if field.IsExported() {
+			fields = append(fields, tableField{
+				name:  name,
+				index: index,
+			})
+		}
+	}
+
+	return fields
+}
+
+// like reflect.Value.FieldByIndex, but returns an invalid value instead of panicking when
+// traversing a nil embedded pointer.
+func fieldByIndex(value reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if value = indirectValue(value); !value.IsValid() {
+			return value
+		}
+
+		value = value.Field(i)
+	}
+
+	return value
+}
+
+func indirectValue(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+
+		value = value.Elem()
+	}
+
+	return value
+}
+
+// Appends a row of values to the table.  Values are converted to strings, with nil values
+// becoming empty strings.
+func (self *Table) AddRow(values ...interface{}) *Table {
+	var row = make([]string, len(values))
+
+	for i, value := range values {
+		if value != nil {
+			row[i] = typeutil.String(value)
+		}
+	}
+
+	self.Rows = append(self.Rows, row)
+
+	return self
+}
+
+// Sets the alignment of the column at the given index.
+func (self *Table) SetAlign(column int, align ColumnAlignment) *Table {
+	if column >= 0 && column < len(self.Columns) {
+		self.Columns[column].Align = align
+	}
+
+	return self
+}
+
+// Sets the maximum display width of the column at the given index.
+func (self *Table) SetMaxWidth(column int, width int) *Table {
+	if column >= 0 && column < len(self.Columns) {
+		self.Columns[column].MaxWidth = width
+	}
+
+	return self
+}
+
+// Returns the table rendered in its Format.
+func (self *Table) String() string {
+	var buf bytes.Buffer
+
+	self.Write(&buf)
+
+	return buf.String()
+}
+
+// Renders the table to the given writer in its Format.
+func (self *Table) Write(w io.Writer) error {
+	switch self.Format {
+	case CSVTable:
+		return self.writeDelimited(w, ',')
+	case TSVTable:
+		return self.writeDelimited(w, '\t')
+	case MarkdownTable:
+		return self.writeMarkdown(w)
+	case BoxTable:
+		return self.writeBox(w)
+	default:
+		return self.writePlain(w)
+	}
+}
+
+// returns the header and rows (elided, and with newlines flattened), and the width of each column.
+func (self *Table) cells(escape func(string) string) ([]string, [][]string, []int) {
+	var ncols = len(self.Columns)
+
+	for _, row := range self.Rows {
+		if len(row) > ncols {
+			ncols = len(row)
+		}
+	}
+
+	var widths = make([]int, ncols)
+	var header = make([]string, ncols)
+	var rows = make([][]string, len(self.Rows))
+
+	var prepare = func(c int, value string) string {
+		value = strings.NewReplacer("\r\n", ` `, "\n", ` `, "\r", ` `).Replace(value)
+
+		if escape != nil {
+			value = escape(value)
+		}
+
+		if c < len(self.Columns) {
+			if max := self.Columns[c].MaxWidth; max > 0 && DisplayWidth(value) > max {
+				var keep = max - DisplayWidth(TableEllipsis)
+
+				if keep < 0 {
+					keep = 0
+				}
+
+				value, _ = truncateWidth(value, keep, false)
+				value += TableEllipsis
+			}
+		}
+
+		if w := DisplayWidth(value); w > widths[c] {
+			widths[c] = w
+		}
+
+		return value
+	}
+
+	for c, column := range self.Columns {
+		header[c] = prepare(c, column.Name)
+	}
+
+	for r, row := range self.Rows {
+		rows[r] = make([]string, ncols)
+
+		for c, value := range row {
+			rows[r][c] = prepare(c, value)
+		}
+	}
+
+	return header, rows, widths
+}
+
+func (self *Table) align(c int, value string, width int) string {
+	var align ColumnAlignment
+
+	if c < len(self.Columns) {
+		align = self.Columns[c].Align
+	}
+
+	switch align {
+	case ColumnRight:
+		return AlignRight(value, width)
+	case ColumnCenter:
+		return Center(value, width)
+	default:
+		return Pad(value, width)
+	}
+}
+
+func (self *Table) styleHeader(value string) string {
+	if style := strings.TrimSuffix(strings.TrimPrefix(self.HeaderStyle, `${`), `}`); style != `` {
+		return ansi.ColorCode(style) + value + ansi.Reset
+	}
+
+	return value
+}
+
+func (self *Table) writePlain(w io.Writer) error {
+	var header, rows, widths = self.cells(nil)
+	var out strings.Builder
+
+	var writeRow = func(row []string, styled bool) {
+		var line strings.Builder
+
+		for c, value := range row {
+			if c > 0 {
+				line.WriteString(TableColumnSeparator)
+			}
+
+			value = self.align(c, value, widths[c])
+
+			if styled {
+				value = self.styleHeader(value)
+			}
+
+			line.WriteString(value)
+		}
+
+		out.WriteString(strings.TrimRight(line.String(), ` `))
+		out.WriteString("\n")
+	}
+
+	if !self.HideHeader && len(self.Columns) > 0 {
+		writeRow(header, true)
+	}
+
+	for _, row := range rows {
+		writeRow(row, false)
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func (self *Table) writeBox(w io.Writer) error {
+	var header, rows, widths = self.cells(nil)
+	var out strings.Builder
+
+	var writeRule = func(left string, middle string, right string) {
+		out.WriteString(left)
+
+		for c, width := range widths {
+			if c > 0 {
+				out.WriteString(middle)
+			}
+
+			out.WriteString(strings.Repeat(`─`, width+2))
+		}
+
+		out.WriteString(right + "\n")
+	}
+
+	var writeRow = func(row []string, styled bool) {
+		out.WriteString(`│`)
+
+		for c, value := range row {
+			value = self.align(c, value, widths[c])
+
+			if styled {
+				value = self.styleHeader(value)
+			}
+
+			out.WriteString(` ` + value + ` │`)
+		}
+
+		out.WriteString("\n")
+	}
+
+	writeRule(`┌`, `┬`, `┐`)
+
+	if !self.HideHeader && len(self.Columns) > 0 {
+		writeRow(header, true)
+
+		if len(rows) > 0 {
+			writeRule(`├`, `┼`, `┤`)
+		}
+	}
+
+	for _, row := range rows {
+		writeRow(row, false)
+	}
+
+	writeRule(`└`, `┴`, `┘`)
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func (self *Table) writeMarkdown(w io.Writer) error {
+	var header, rows, widths = self.cells(func(value string) string {
+		return strings.ReplaceAll(value, `|`, `\|`)
+	})
+
+	var out strings.Builder
+
+	var writeRow = func(row []string) {
+		out.WriteString(`|`)
+
+		for c, value := range row {
+			out.WriteString(` ` + self.align(c, value, widths[c]) + ` |`)
+		}
+
+		out.WriteString("\n")
+	}
+
+	// the delimiter row needs at least three characters per column
+	for c := range widths {
+		if widths[c] < 3 {
+			widths[c] = 3
+		}
+	}
+
+	writeRow(header)
+	out.WriteString(`|`)
+
+	for c, width := range widths {
+		var align ColumnAlignment
+
+		if c < len(self.Columns) {
+			align = self.Columns[c].Align
+		}
+
+		switch align {
+		case ColumnRight:
+			out.WriteString(` ` + strings.Repeat(`-`, width-1) + `: |`)
+		case ColumnCenter:
+			out.WriteString(` :` + strings.Repeat(`-`, width-2) + `: |`)
+		default:
+			out.WriteString(` ` + strings.Repeat(`-`, width) + ` |`)
+		}
+	}
+
+	out.WriteString("\n")
+
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func (self *Table) writeDelimited(w io.Writer, delimiter rune) error {
+	var writer = csv.NewWriter(w)
+
+	writer.Comma = delimiter
+
+	if !self.HideHeader && len(self.Columns) > 0 {
+		var header = make([]string, len(self.Columns))
+
+		for c, column := range self.Columns {
+			header[c] = column.Name
+		}
+
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range self.Rows {
+		var record = row
+
+		// pad short rows so that every record has the same number of fields
+		if len(record) < len(self.Columns) {
+			record = append(append([]string(nil), row...), make([]string, len(self.Columns)-len(row))...)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
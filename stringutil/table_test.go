@@ -0,0 +1,168 @@
+package stringutil
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+	"github.com/mgutz/ansi"
+)
+
+type tableTestBase struct {
+	ID int `json:"id"`
+}
+
+type tableTestRow struct {
+	tableTestBase
+	Name    string  `json:"name"`
+	Price   float64 `json:"price,omitempty"`
+	Secret  string  `json:"-"`
+	Comment string
+	hidden  string
+}
+
+func TestTablePlain(t *testing.T) {
+	assert := require.New(t)
+
+	var table = NewTable(`name`, `qty`, `note`)
+
+	table.AddRow(`apple`, 3, `red`)
+	table.AddRow(`banana`, 12)
+	table.AddRow(`kiwi`, nil, `green`)
+	table.SetAlign(1, ColumnRight)
+
+	assert.Equal("name    qty  note\n"+
+		"apple     3  red\n"+
+		"banana   12\n"+
+		"kiwi         green\n", table.String())
+
+	table.HideHeader = true
+	assert.Equal("apple     3  red\n"+
+		"banana   12\n"+
+		"kiwi         green\n", table.String())
+}
+
+func TestTableBox(t *testing.T) {
+	assert := require.New(t)
+
+	var table = NewTable(`a`, `b`)
+
+	table.Format = BoxTable
+	table.AddRow(`one`, `two`)
+	table.AddRow(`three`, `4`)
+	table.SetAlign(1, ColumnCenter)
+
+	assert.Equal(""+
+		"┌───────┬─────┐\n"+
+		"│ a     │  b  │\n"+
+		"├───────┼─────┤\n"+
+		"│ one   │ two │\n"+
+		"│ three │  4  │\n"+
+		"└───────┴─────┘\n", table.String())
+}
+
+func TestTableMarkdown(t *testing.T) {
+	assert := require.New(t)
+
+	var table = NewTable(`key`, `value`, `n`)
+
+	table.Format = MarkdownTable
+	table.AddRow(`a|b`, `x`, 1)
+	table.SetAlign(1, ColumnCenter)
+	table.SetAlign(2, ColumnRight)
+
+	assert.Equal(""+
+		"| key  | value |   n |\n"+
+		"| ---- | :---: | --: |\n"+
+		"| a\\|b |   x   |   1 |\n", table.String())
+}
+
+func TestTableDelimited(t *testing.T) {
+	assert := require.New(t)
+
+	var table = NewTable(`a`, `b`)
+
+	table.AddRow(`hello, world`, `"quoted"`)
+	table.AddRow(`x`)
+
+	table.Format = CSVTable
+	assert.Equal("a,b\n\"hello, world\",\"\"\"quoted\"\"\"\nx,\n", table.String())
+
+	table.Format = TSVTable
+	assert.Equal("a\tb\nhello, world\t\"\"\"quoted\"\"\"\nx\t\n", table.String())
+}
+
+func TestTableWidths(t *testing.T) {
+	assert := require.New(t)
+
+	var table = NewTable(`word`, `x`)
+
+	table.AddRow(`日本語`, `1`)
+	table.AddRow("\x1b[31mred\x1b[0m", `2`)
+	table.AddRow(`a very long value`, `3`)
+	table.SetMaxWidth(0, 8)
+
+	assert.Equal(""+
+		"word      x\n"+
+		"日本語    1\n"+
+		"\x1b[31mred\x1b[0m       2\n"+
+		"a very …  3\n", table.String())
+}
+
+func TestTableHeaderStyle(t *testing.T) {
+	assert := require.New(t)
+
+	var table = NewTable(`a`, `b`)
+
+	table.HeaderStyle = `${green+b}`
+	table.AddRow(`1`, `2`)
+
+	assert.Equal(ansi.ColorCode(`green+b`)+`a`+ansi.Reset+`  `+ansi.ColorCode(`green+b`)+`b`+ansi.Reset+"\n1  2\n", table.String())
+}
+
+func TestTableFrom(t *testing.T) {
+	assert := require.New(t)
+
+	table, err := TableFrom([][]string{
+		{`a`, `b`},
+		{`1`, `2`},
+	})
+
+	assert.NoError(err)
+	assert.Equal("a  b\n1  2\n", table.String())
+
+	table, err = TableFrom([]map[string]interface{}{
+		{`name`: `one`, `id`: 1},
+		{`name`: `two`, `extra`: true},
+	})
+
+	assert.NoError(err)
+	assert.Equal("extra  id  name\n"+
+		"       1   one\n"+
+		"true       two\n", table.String())
+
+	table, err = TableFrom([]*tableTestRow{
+		{tableTestBase: tableTestBase{ID: 1}, Name: `widget`, Price: 2.5, Secret: `x`, Comment: `ok`},
+		nil,
+		{tableTestBase: tableTestBase{ID: 2}, Name: `gadget`},
+	})
+
+	assert.NoError(err)
+	assert.Equal("id  name    price  Comment\n"+
+		"1   widget  2.5    ok\n"+
+		"\n"+
+		"2   gadget  0\n", table.String())
+
+	table, err = TableFrom([]interface{}{
+		map[string]string{`a`: `1`},
+		map[string]string{`a`: `2`},
+	})
+
+	assert.NoError(err)
+	assert.Equal("a\n1\n2\n", table.String())
+
+	_, err = TableFrom([]int{1, 2})
+	assert.Error(err)
+
+	_, err = TableFrom(`nope`)
+	assert.Error(err)
+}
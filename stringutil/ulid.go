@@ -0,0 +1,248 @@
+package stringutil
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The Crockford Base32 alphabet used to encode ULIDs.
+const crockfordAlphabet = `0123456789ABCDEFGHJKMNPQRSTVWXYZ`
+
+var crockfordDecode [256]byte
+
+var ulidState = struct {
+	sync.Mutex
+	lastMs  uint64
+	entropy [10]byte
+}{}
+
+func init() {
+	for i := range crockfordDecode {
+		crockfordDecode[i] = 0xff
+	}
+
+	for i, c := range crockfordAlphabet {
+		crockfordDecode[c] = byte(i)
+		crockfordDecode[strings.ToLower(string(c))[0]] = byte(i)
+	}
+
+	// Crockford's decoding rules allow for commonly-confused characters
+	for c, v := range map[byte]byte{'O': 0, 'o': 0, 'I': 1, 'i': 1, 'L': 1, 'l': 1} {
+		crockfordDecode[c] = v
+	}
+}
+
+// A Universally Unique Lexicographically Sortable Identifier: a 48-bit millisecond timestamp
+// followed by 80 random bits, represented as a 26-character Crockford Base32 string.
+type Ulid [16]byte
+
+// Generate a new ULID using the current time.  ULIDs generated within the same millisecond are
+// guaranteed to increase.
+func ULID() *Ulid {
+	ulidState.Lock()
+	defer ulidState.Unlock()
+
+	var ms = uint64(time.Now().UnixMilli())
+
+	if ms > ulidState.lastMs {
+		ulidState.lastMs = ms
+		randomBytes(ulidState.entropy[:])
+	} else if !incrementBytes(ulidState.entropy[:]) {
+		// the random component overflowed; borrow from the next millisecond
+		ulidState.lastMs++
+		randomBytes(ulidState.entropy[:])
+	}
+
+	var out = new(Ulid)
+
+	putUint48(out[0:6], ulidState.lastMs)
+	copy(out[6:], ulidState.entropy[:])
+
+	return out
+}
+
+// Generate a ULID for the given time.  Unlike ULID, successive calls with the same time are not
+// guaranteed to be ordered.
+func ULIDAt(t time.Time) *Ulid {
+	var out = new(Ulid)
+
+	putUint48(out[0:6], uint64(t.UnixMilli()))
+	randomBytes(out[6:])
+
+	return out
+}
+
+// Parse a ULID from its 26-character string representation.  Parsing is case-insensitive.
+func ParseULID(in string) (*Ulid, error) {
+	var out = new(Ulid)
+
+	if len(in) != 26 {
+		return nil, fmt.Errorf("invalid ULID length %d", len(in))
+	}
+
+	// check the alphabet first so that bad characters aren't mistaken for overflow
+	for i := 0; i < len(in); i++ {
+		if crockfordDecode[in[i]] == 0xff {
+			return nil, fmt.Errorf("invalid ULID %q: invalid character %q at position %d", in, in[i], i)
+		}
+	}
+
+	if crockfordDecode[in[0]] > 7 {
+		return nil, fmt.Errorf("invalid ULID %q: timestamp overflow", in)
+	}
+
+	// each character holds 5 bits; the first character only contributes 3 of them
+	var bit = -2
+
+	for i := 0; i < len(in); i++ {
+		var v = crockfordDecode[in[i]]
+
+		for b := 4; b >= 0; b-- {
+			if bit >= 0 && v&(1<<b) != 0 {
+				out[bit/8] |= 1 << (7 - bit%8)
+			}
+
+			bit++
+		}
+	}
+
+	return out, nil
+}
+
+// Same as ParseULID, but panics if the given string is not a valid ULID.
+func MustULID(in string) *Ulid {
+	if id, err := ParseULID(in); err == nil {
+		return id
+	} else {
+		panic(err)
+	}
+}
+
+// Return a ULID from its 16-byte binary representation.
+func UlidFromBytes(b []byte) (*Ulid, error) {
+	if len(b) != 16 {
+		return nil, fmt.Errorf("invalid ULID length %d", len(b))
+	}
+
+	var out = new(Ulid)
+	copy(out[:], b)
+
+	return out, nil
+}
+
+// Return the ULID as a 26-character Crockford Base32 string.
+func (self Ulid) String() string {
+	var out [26]byte
+	var bit = -2
+
+	for i := range out {
+		var v byte
+
+		for b := 4; b >= 0; b-- {
+			if bit >= 0 && self[bit/8]&(1<<(7-bit%8)) != 0 {
+				v |= 1 << b
+			}
+
+			bit++
+		}
+
+		out[i] = crockfordAlphabet[v]
+	}
+
+	return string(out[:])
+}
+
+// Return the 16-byte binary representation of the ULID.
+func (self Ulid) Bytes() []byte {
+	return self[:]
+}
+
+// Return the time embedded in the ULID.
+func (self Ulid) Timestamp() time.Time {
+	return time.UnixMilli(int64(uint48(self[0:6])))
+}
+
+func (self Ulid) MarshalText() ([]byte, error) {
+	return []byte(self.String()), nil
+}
+
+func (self *Ulid) UnmarshalText(data []byte) error {
+	if id, err := ParseULID(string(data)); err == nil {
+		*self = *id
+		return nil
+	} else {
+		return err
+	}
+}
+
+func (self Ulid) MarshalBinary() ([]byte, error) {
+	return self.Bytes(), nil
+}
+
+func (self *Ulid) UnmarshalBinary(data []byte) error {
+	if id, err := UlidFromBytes(data); err == nil {
+		*self = *id
+		return nil
+	} else {
+		return err
+	}
+}
+
+// Implements sql.Scanner, accepting either the string or 16-byte binary representation.
+func (self *Ulid) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		if src == `` {
+			return nil
+		}
+
+		return self.UnmarshalText([]byte(src))
+	case []byte:
+		if len(src) == 0 {
+			return nil
+		} else if len(src) == 16 {
+			return self.UnmarshalBinary(src)
+		}
+
+		return self.UnmarshalText(src)
+	default:
+		return fmt.Errorf("Scan: unable to scan type %T into ULID", src)
+	}
+}
+
+// Implements driver.Valuer; ULIDs are stored as strings.
+func (self Ulid) Value() (driver.Value, error) {
+	return self.String(), nil
+}
+
+// adds one to the big-endian integer in b, returning false if it overflowed.
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i]++; b[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func putUint48(b []byte, v uint64) {
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], v)
+	copy(b, buf[2:])
+}
+
+func uint48(b []byte) uint64 {
+	var buf [8]byte
+
+	copy(buf[2:], b)
+
+	return binary.BigEndian.Uint64(buf[:])
+}
@@ -0,0 +1,84 @@
+package stringutil
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestULID(t *testing.T) {
+	assert := require.New(t)
+
+	id, err := ParseULID(`01ARZ3NDEKTSV4RRFFQ69G5FAV`)
+	assert.NoError(err)
+	assert.Equal(`01ARZ3NDEKTSV4RRFFQ69G5FAV`, id.String())
+	assert.Equal(int64(1469922850259), id.Timestamp().UnixMilli())
+
+	// decoding is case-insensitive and forgiving of ambiguous characters
+	lower, err := ParseULID(`01arz3ndektsv4rrffq69g5fav`)
+	assert.NoError(err)
+	assert.Equal(id, lower)
+	assert.Equal(MustULID(`00000000000000000000000001`), MustULID(`0000000000000000000000000I`))
+
+	_, err = ParseULID(`01ARZ3NDEK`)
+	assert.Error(err)
+	_, err = ParseULID(`81ARZ3NDEKTSV4RRFFQ69G5FAV`)
+	assert.EqualError(err, `invalid ULID "81ARZ3NDEKTSV4RRFFQ69G5FAV": timestamp overflow`)
+	_, err = ParseULID(`01ARZ3NDEKTSV4RRFFQ69G5FAU`)
+	assert.EqualError(err, `invalid ULID "01ARZ3NDEKTSV4RRFFQ69G5FAU": invalid character 'U' at position 25`)
+
+	// characters outside the alphabet are reported as such, even in the first position
+	_, err = ParseULID(`U1ARZ3NDEKTSV4RRFFQ69G5FAV`)
+	assert.EqualError(err, `invalid ULID "U1ARZ3NDEKTSV4RRFFQ69G5FAV": invalid character 'U' at position 0`)
+	_, err = ParseULID(`-1ARZ3NDEKTSV4RRFFQ69G5FAV`)
+	assert.EqualError(err, `invalid ULID "-1ARZ3NDEKTSV4RRFFQ69G5FAV": invalid character '-' at position 0`)
+
+	assert.Equal(`7ZZZZZZZZZZZZZZZZZZZZZZZZZ`, MustULID(`7ZZZZZZZZZZZZZZZZZZZZZZZZZ`).String())
+
+	fromBytes, err := UlidFromBytes(id.Bytes())
+	assert.NoError(err)
+	assert.Equal(id, fromBytes)
+
+	// generation
+	now := time.Now()
+	var last string
+
+	for i := 0; i < 10000; i++ {
+		next := ULID().String()
+		assert.True(next > last, "%s should sort after %s", next, last)
+		last = next
+	}
+
+	assert.WithinDuration(now, MustULID(last).Timestamp(), 2*time.Second)
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	assert.True(at.Equal(ULIDAt(at).Timestamp()))
+
+	// marshaling
+	data, err := json.Marshal(map[string]interface{}{
+		`id`: id,
+	})
+
+	assert.NoError(err)
+	assert.Equal(`{"id":"01ARZ3NDEKTSV4RRFFQ69G5FAV"}`, string(data))
+
+	var decoded struct {
+		ID Ulid `json:"id"`
+	}
+
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(*id, decoded.ID)
+
+	value, err := id.Value()
+	assert.NoError(err)
+	assert.Equal(`01ARZ3NDEKTSV4RRFFQ69G5FAV`, value)
+
+	var scanned Ulid
+	assert.NoError(scanned.Scan(`01ARZ3NDEKTSV4RRFFQ69G5FAV`))
+	assert.Equal(*id, scanned)
+	assert.NoError(scanned.Scan(id.Bytes()))
+	assert.Equal(*id, scanned)
+	assert.Error(scanned.Scan(42))
+}
@@ -1,8 +1,13 @@
 package stringutil
 
 import (
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
+	"io"
+	"sync"
+	"time"
 
 	"github.com/ghetzel/uuid"
 	"github.com/jbenet/go-base58"
@@ -61,3 +66,141 @@ func (self *Uuid) Base64() string {
 func (self *Uuid) Base58() string {
 	return base58.EncodeAlphabet(self.Bytes(), base58.BTCAlphabet)
 }
+
+// Well-known namespaces for generating name-based (version 3 and 5) UUIDs.
+var (
+	UuidNamespaceDNS  = &Uuid{uuid.NameSpaceDNS}
+	UuidNamespaceURL  = &Uuid{uuid.NameSpaceURL}
+	UuidNamespaceOID  = &Uuid{uuid.NameSpaceOID}
+	UuidNamespaceX500 = &Uuid{uuid.NameSpaceX500}
+)
+
+// the number of 100ns intervals between the start of the Gregorian calendar (1582-10-15) and the Unix epoch.
+const gregorianToUnix = 0x01B21DD213814000
+
+var uuidv6State = struct {
+	sync.Mutex
+	last     int64
+	clockSeq uint16
+}{}
+
+var uuidv7State = struct {
+	sync.Mutex
+	lastMs  int64
+	counter uint16
+}{}
+
+// Generate a name-based (version 3) UUID from the MD5 hash of the given namespace and name.
+func UUIDv3(namespace *Uuid, name string) *Uuid {
+	return &Uuid{
+		UUID: uuid.NewMD5(namespace.UUID, []byte(name)),
+	}
+}
+
+// Generate a name-based (version 5) UUID from the SHA-1 hash of the given namespace and name.
+func UUIDv5(namespace *Uuid, name string) *Uuid {
+	return &Uuid{
+		UUID: uuid.NewSHA1(namespace.UUID, []byte(name)),
+	}
+}
+
+// Generate a time-ordered (version 6) UUID.  This is a version 1 UUID with its timestamp fields
+// reordered so that UUIDs sort in the order they were created, and with a random node ID.  UUIDs
+// generated within the same 100ns interval are guaranteed to increase.
+func UUIDv6() *Uuid {
+	uuidv6State.Lock()
+	defer uuidv6State.Unlock()
+
+	var ts = time.Now().UnixNano()/100 + gregorianToUnix
+	var out = new(Uuid)
+
+	if uuidv6State.clockSeq == 0 {
+		var seq [2]byte
+		randomBytes(seq[:])
+		uuidv6State.clockSeq = binary.BigEndian.Uint16(seq[:])&0x3fff | 0x8000
+	}
+
+	if ts <= uuidv6State.last {
+		ts = uuidv6State.last + 1
+	}
+
+	uuidv6State.last = ts
+
+	binary.BigEndian.PutUint32(out.UUID[0:4], uint32(ts>>28))
+	binary.BigEndian.PutUint16(out.UUID[4:6], uint16(ts>>12))
+	binary.BigEndian.PutUint16(out.UUID[6:8], uint16(ts&0xfff)|0x6000)
+	binary.BigEndian.PutUint16(out.UUID[8:10], uuidv6State.clockSeq)
+	randomBytes(out.UUID[10:16])
+
+	return out
+}
+
+// Generate a time-ordered (version 7) UUID from the current Unix time in milliseconds.  UUIDs
+// generated within the same millisecond are guaranteed to increase.
+func UUIDv7() *Uuid {
+	uuidv7State.Lock()
+	defer uuidv7State.Unlock()
+
+	var ms = time.Now().UnixMilli()
+	var rand [2]byte
+
+	if ms > uuidv7State.lastMs {
+		randomBytes(rand[:])
+		uuidv7State.counter = binary.BigEndian.Uint16(rand[:]) & 0x7ff
+	} else if uuidv7State.counter++; uuidv7State.counter > 0xfff {
+		// the counter is exhausted; borrow from the next millisecond
+		ms = uuidv7State.lastMs + 1
+		randomBytes(rand[:])
+		uuidv7State.counter = binary.BigEndian.Uint16(rand[:]) & 0x7ff
+	} else {
+		ms = uuidv7State.lastMs
+	}
+
+	uuidv7State.lastMs = ms
+
+	var out = UUIDv7At(time.UnixMilli(ms))
+
+	binary.BigEndian.PutUint16(out.UUID[6:8], uuidv7State.counter|0x7000)
+
+	return out
+}
+
+// Generate a version 7 UUID for the given time.  Unlike UUIDv7, successive calls with the same
+// time are not guaranteed to be ordered.
+func UUIDv7At(t time.Time) *Uuid {
+	var out = new(Uuid)
+
+	putUint48(out.UUID[0:6], uint64(t.UnixMilli()))
+	randomBytes(out.UUID[6:16])
+
+	out.UUID[6] = out.UUID[6]&0x0f | 0x70
+	out.UUID[8] = out.UUID[8]&0x3f | 0x80
+
+	return out
+}
+
+// Return the time embedded in a time-based (version 1, 6, or 7) UUID.  The zero time is returned
+// for all other versions.
+func (self *Uuid) Timestamp() time.Time {
+	switch self.Version() {
+	case 1:
+		sec, nsec := self.UUID.Time().UnixTime()
+		return time.Unix(sec, nsec)
+	case 6:
+		var ts = int64(binary.BigEndian.Uint32(self.UUID[0:4]))<<28 |
+			int64(binary.BigEndian.Uint16(self.UUID[4:6]))<<12 |
+			int64(binary.BigEndian.Uint16(self.UUID[6:8])&0xfff)
+
+		return time.Unix(0, (ts-gregorianToUnix)*100)
+	case 7:
+		return time.UnixMilli(int64(uint48(self.UUID[0:6])))
+	default:
+		return time.Time{}
+	}
+}
+
+func randomBytes(b []byte) {
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		panic(err)
+	}
+}
@@ -1,7 +1,9 @@
 package stringutil
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/ghetzel/testify/require"
 )
@@ -25,3 +27,56 @@ func TestUUID(t *testing.T) {
 	assert.Equal(`AQIDAQIDAQIDAQIDAQIDAQ==`, uuid.Base64())
 	assert.Equal(`8DfbUyTr2zZABVZdbmdo6`, uuid.Base58())
 }
+
+func TestUUIDVersions(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`6fa459ea-ee8a-3ca4-894e-db77e160355e`, UUIDv3(UuidNamespaceDNS, `python.org`).String())
+	assert.Equal(`886313e1-3b8a-5372-9b90-0c9aee199e5d`, UUIDv5(UuidNamespaceDNS, `python.org`).String())
+	assert.Equal(UUIDv5(UuidNamespaceURL, `x`).String(), UUIDv5(UuidNamespaceURL, `x`).String())
+	assert.True(UUIDv3(UuidNamespaceDNS, `python.org`).Timestamp().IsZero())
+
+	// timestamps from the RFC 9562 examples
+	assert.Equal(`2022-02-22T19:22:22Z`, MustUUID(`1EC9414C-232A-6B00-B3C8-9F6BDECED846`).Timestamp().UTC().Format(time.RFC3339))
+	assert.Equal(`2022-02-22T19:22:22Z`, MustUUID(`017F22E2-79B0-7CC3-98C4-DC0C0C07398F`).Timestamp().UTC().Format(time.RFC3339))
+
+	now := time.Now()
+
+	for _, gen := range []func() *Uuid{UUIDv6, UUIDv7} {
+		var last string
+
+		for i := 0; i < 10000; i++ {
+			id := gen()
+			assert.True(id.String() > last, "%s should sort after %s", id, last)
+			last = id.String()
+		}
+
+		id := gen()
+		assert.Equal(`RFC4122`, id.Variant().String())
+		assert.WithinDuration(now, id.Timestamp(), 2*time.Second)
+	}
+
+	assert.EqualValues(6, UUIDv6().Version())
+	assert.EqualValues(7, UUIDv7().Version())
+
+	at := time.Date(2020, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	assert.True(at.Equal(UUIDv7At(at).Timestamp()))
+
+	// marshaling
+	id := UUIDv7()
+	data, err := json.Marshal(id)
+	assert.NoError(err)
+	assert.Equal(`"`+id.String()+`"`, string(data))
+
+	var decoded Uuid
+	assert.NoError(json.Unmarshal(data, &decoded))
+	assert.Equal(id.String(), decoded.String())
+
+	value, err := id.Value()
+	assert.NoError(err)
+	assert.Equal(id.String(), value)
+
+	var scanned Uuid
+	assert.NoError(scanned.Scan(id.Bytes()))
+	assert.Equal(id.String(), scanned.String())
+}
@@ -0,0 +1,429 @@
+package stringutil
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Unicode ranges of East Asian wide and fullwidth characters and emoji, which occupy two columns
+// when displayed in a terminal.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0},
+	{0x23F3, 0x23F3}, {0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F},
+	{0x2693, 0x2693}, {0x26A1, 0x26A1}, {0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5},
+	{0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA}, {0x26F2, 0x26F3}, {0x26F5, 0x26F5},
+	{0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B}, {0x2728, 0x2728},
+	{0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF},
+	{0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F},
+	{0xFF00, 0xFF60}, {0xFFE0, 0xFFE6}, {0x16FE0, 0x16FE4}, {0x17000, 0x18AFF}, {0x1B000, 0x1B2FF},
+	{0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F1E6, 0x1F1FF},
+	{0x1F200, 0x1F2FF}, {0x1F300, 0x1F64F}, {0x1F680, 0x1F6FF}, {0x1F7E0, 0x1F7EB}, {0x1F90C, 0x1F9FF},
+	{0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// Returns the number of terminal columns occupied by the given rune.  Control characters and
+// combining marks have no width, and wide characters occupy two columns.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7F:
+		return 0
+	case r < 0x7F:
+		return 1
+	case r < 0xA0:
+		return 0
+	case r >= 0x1160 && r <= 0x11FF:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+
+	if i := sort.Search(len(wideRanges), func(i int) bool {
+		return wideRanges[i][1] >= r
+	}); i < len(wideRanges) && r >= wideRanges[i][0] {
+		return 2
+	}
+
+	return 1
+}
+
+// Returns the number of terminal columns needed to display the given string.  ANSI escape
+// sequences are ignored, wide characters and emoji count as two columns, and combining marks
+// and other grapheme cluster extensions count as zero.  If the string contains multiple lines,
+// the width of the widest line is returned.
+func DisplayWidth(in string) int {
+	var max, width int
+
+	eachDisplaySegment(in, func(seg string, w int, escape bool) bool {
+		if seg == "\n" || seg == "\r\n" {
+			width = 0
+		} else {
+			width += w
+		}
+
+		if width > max {
+			max = width
+		}
+
+		return true
+	})
+
+	return max
+}
+
+// Returns the given string with all ANSI escape sequences removed.
+func StripANSI(in string) string {
+	if !strings.ContainsRune(in, 0x1B) {
+		return in
+	}
+
+	var out strings.Builder
+
+	eachDisplaySegment(in, func(seg string, w int, escape bool) bool {
+		if !escape {
+			out.WriteString(seg)
+		}
+
+		return true
+	})
+
+	return out.String()
+}
+
+// Pads the given string with spaces on the right so that it occupies at least width columns.
+func Pad(in string, width int) string {
+	if w := DisplayWidth(in); w < width {
+		return in + strings.Repeat(` `, width-w)
+	}
+
+	return in
+}
+
+// Pads the given string with spaces on the left so that it occupies at least width columns.
+func AlignRight(in string, width int) string {
+	if w := DisplayWidth(in); w < width {
+		return strings.Repeat(` `, width-w) + in
+	}
+
+	return in
+}
+
+// Pads the given string with spaces on both sides so that it is centered within width columns.
+// If the padding cannot be split evenly, the extra space is placed on the right.
+func Center(in string, width int) string {
+	if w := DisplayWidth(in); w < width {
+		var left = (width - w) / 2
+
+		return strings.Repeat(` `, left) + in + strings.Repeat(` `, width-w-left)
+	}
+
+	return in
+}
+
+// Options that control how WordWrap breaks text into lines.
+type WrapOptions struct {
+	// The maximum width of each line, in terminal columns (including indentation).
+	Width int
+
+	// A string to prefix the first line of each paragraph with.
+	Indent string
+
+	// A string to prefix every line after the first line of each paragraph with.
+	HangingIndent string
+
+	// Leave words that are longer than the line width intact instead of breaking them.
+	KeepLongWords bool
+}
+
+// Wraps the given text so that no line exceeds the given display width, breaking on whitespace.
+// Words that are too long to fit on a line by themselves are broken at grapheme boundaries.
+func WordWrap(in string, width int) string {
+	return WrapOptions{
+		Width: width,
+	}.Wrap(in)
+}
+
+// Wraps the given text according to the options.  Each line of the input is treated as a separate
+// paragraph; blank lines are preserved and runs of whitespace between words are collapsed.
+func (self WrapOptions) Wrap(in string) string {
+	var paragraphs = strings.Split(strings.ReplaceAll(in, "\r\n", "\n"), "\n")
+	var out = make([]string, 0, len(paragraphs))
+
+	for _, paragraph := range paragraphs {
+		out = append(out, self.wrapParagraph(paragraph)...)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func (self WrapOptions) wrapParagraph(paragraph string) []string {
+	var words = strings.Fields(paragraph)
+	var lines []string
+	var line strings.Builder
+	var lineWidth int
+
+	if len(words) == 0 {
+		return []string{``}
+	}
+
+	var available = func() int {
+		var prefix = self.Indent
+
+		if len(lines) > 0 {
+			prefix = self.HangingIndent
+		}
+
+		if w := self.Width - DisplayWidth(prefix); w > 0 || self.Width <= 0 {
+			return w
+		}
+
+		return 1
+	}
+
+	var flush = func() {
+		if len(lines) == 0 {
+			lines = append(lines, self.Indent+line.String())
+		} else {
+			lines = append(lines, self.HangingIndent+line.String())
+		}
+
+		line.Reset()
+		lineWidth = 0
+	}
+
+	for _, word := range words {
+		var wordWidth = DisplayWidth(word)
+		var space = 0
+
+		if line.Len() > 0 {
+			space = 1
+		}
+
+		if self.Width <= 0 || lineWidth+space+wordWidth <= available() {
+			if space > 0 {
+				line.WriteString(` `)
+				lineWidth++
+			}
+
+			line.WriteString(word)
+			lineWidth += wordWidth
+			continue
+		}
+
+		if line.Len() > 0 {
+			flush()
+		}
+
+		if wordWidth > available() && !self.KeepLongWords {
+			eachDisplaySegment(word, func(seg string, w int, escape bool) bool {
+				if lineWidth > 0 && lineWidth+w > available() {
+					flush()
+				}
+
+				line.WriteString(seg)
+				lineWidth += w
+				return true
+			})
+		} else {
+			line.WriteString(word)
+			lineWidth = wordWidth
+		}
+	}
+
+	if line.Len() > 0 {
+		flush()
+	}
+
+	return lines
+}
+
+// truncates the given string to at most width display columns, retaining any escape sequences
+// from the portion being removed so that color resets are not lost.  If fromLeft is true,
+// characters are removed from the start of the string instead of the end.  Returns whether
+// anything was removed.
+func truncateWidth(in string, width int, fromLeft bool) (string, bool) {
+	var segments []string
+	var widths []int
+	var escapes []bool
+
+	eachDisplaySegment(in, func(seg string, w int, escape bool) bool {
+		segments = append(segments, seg)
+		widths = append(widths, w)
+		escapes = append(escapes, escape)
+		return true
+	})
+
+	var keep = make([]bool, len(segments))
+	var pending []int
+	var total int
+	var cut bool
+
+	for n := 0; n < len(segments); n++ {
+		var i = n
+
+		if fromLeft {
+			i = len(segments) - 1 - n
+		}
+
+		switch {
+		case escapes[i]:
+			keep[i] = true
+		case cut:
+			// once something has been cut, only escape sequences are retained
+		case total+widths[i] > width:
+			cut = true
+
+			// zero-width characters (e.g. tabs) leading up to the cut are dropped along with it
+			for _, p := range pending {
+				keep[p] = false
+			}
+		default:
+			keep[i] = true
+			total += widths[i]
+
+			if widths[i] == 0 {
+				pending = append(pending, i)
+			} else {
+				pending = pending[:0]
+			}
+		}
+	}
+
+	if !cut {
+		return in, false
+	}
+
+	var out strings.Builder
+
+	for i, seg := range segments {
+		if keep[i] {
+			out.WriteString(seg)
+		}
+	}
+
+	return out.String(), true
+}
+
+// the ANSI sequence that resets all text attributes
+const ansiReset = "\x1b[0m"
+
+// returns the ANSI text attribute (SGR) sequences still in effect after the given string, given
+// those in effect before it.
+func activeSGR(active string, in string) string {
+	if !strings.ContainsRune(in, 0x1B) {
+		return active
+	}
+
+	eachDisplaySegment(in, func(seg string, w int, escape bool) bool {
+		if escape && strings.HasPrefix(seg, "\x1b[") && strings.HasSuffix(seg, `m`) {
+			switch params := seg[2 : len(seg)-1]; {
+			case params == `` || strings.Trim(params, `0`) == ``:
+				active = ``
+			case strings.HasPrefix(params, `0;`):
+				active = seg
+			default:
+				active += seg
+			}
+		}
+
+		return true
+	})
+
+	return active
+}
+
+// calls fn for each ANSI escape sequence and grapheme cluster in the given string, along with its
+// display width.  Iteration stops if fn returns false.
+func eachDisplaySegment(in string, fn func(seg string, width int, escape bool) bool) {
+	for len(in) > 0 {
+		var n, width int
+		var escape bool
+
+		if in[0] == 0x1B {
+			n = escapeSequenceLength(in)
+			escape = true
+		} else {
+			n, width = graphemeLength(in)
+		}
+
+		if !fn(in[:n], width, escape) {
+			return
+		}
+
+		in = in[n:]
+	}
+}
+
+// returns the length in bytes of the ANSI escape sequence at the start of the given string.
+func escapeSequenceLength(in string) int {
+	if len(in) < 2 {
+		return len(in)
+	}
+
+	switch in[1] {
+	case '[': // CSI: parameters and intermediates, terminated by a final byte in @-~
+		for i := 2; i < len(in); i++ {
+			if in[i] >= 0x40 && in[i] <= 0x7E {
+				return i + 1
+			}
+		}
+	case ']', 'P', 'X', '^', '_': // string sequences, terminated by BEL or ST
+		for i := 2; i < len(in); i++ {
+			if in[i] == 0x07 {
+				return i + 1
+			} else if in[i] == 0x1B && i+1 < len(in) && in[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		_, size := utf8.DecodeRuneInString(in[1:])
+		return 1 + size
+	}
+
+	return len(in)
+}
+
+// returns the length in bytes and display width of the grapheme cluster at the start of the
+// given string.  This covers combining marks, variation selectors, emoji modifiers and zero-width
+// joiner sequences, regional indicator pairs, and CRLF.
+func graphemeLength(in string) (int, int) {
+	var first, n = utf8.DecodeRuneInString(in)
+	var width = RuneWidth(first)
+
+	if first == '\r' && len(in) > 1 && in[1] == '\n' {
+		return 2, 0
+	} else if first < 0x20 || first == 0x7F {
+		return n, 0
+	}
+
+	var joined = false
+
+	for n < len(in) {
+		var r, size = utf8.DecodeRuneInString(in[n:])
+
+		switch {
+		case joined && r >= 0x20 && r != 0x7F:
+			joined = false
+		case r == 0x200D:
+			joined = true
+		case r == 0xFE0F:
+			width = 2
+		case isRegionalIndicator(first) && isRegionalIndicator(r) && n == utf8.RuneLen(first):
+		case r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F, r >= 0x1160 && r <= 0x11FF:
+		case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		default:
+			return n, width
+		}
+
+		n += size
+	}
+
+	return n, width
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
@@ -0,0 +1,105 @@
+package stringutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(0, DisplayWidth(``))
+	assert.Equal(5, DisplayWidth(`hello`))
+	assert.Equal(5, DisplayWidth("\x1b[0;31mhello\x1b[0m"))
+	assert.Equal(4, DisplayWidth("\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\"))
+	assert.Equal(4, DisplayWidth(`日本`))
+	assert.Equal(6, DisplayWidth(`ｈｅｌ`))
+	assert.Equal(4, DisplayWidth("café"))
+	assert.Equal(2, DisplayWidth(`👍`))
+	assert.Equal(2, DisplayWidth(`👍🏽`))
+	assert.Equal(2, DisplayWidth(`👨‍👩‍👧`))
+	assert.Equal(2, DisplayWidth(`🇺🇸`))
+	assert.Equal(2, DisplayWidth("❤️"))
+	assert.Equal(5, DisplayWidth("one\ntwo\nthree"))
+
+	assert.Equal(`red and plain`, StripANSI("\x1b[0;31mred\x1b[0m and plain"))
+	assert.Equal(`plain`, StripANSI(`plain`))
+}
+
+func TestPadAlign(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`ab   `, Pad(`ab`, 5))
+	assert.Equal(`   ab`, AlignRight(`ab`, 5))
+	assert.Equal(` ab  `, Center(`ab`, 5))
+	assert.Equal(`toolong`, Pad(`toolong`, 3))
+	assert.Equal(`日本 `, Pad(`日本`, 5))
+	assert.Equal("\x1b[1mab\x1b[0m   ", Pad("\x1b[1mab\x1b[0m", 5))
+	assert.Equal(`  日本  `, Center(`日本`, 8))
+}
+
+func TestElideWidth(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`hello`, Elide(`hello`, 5, `...`))
+	assert.Equal(`日本…`, Elide(`日本語`, 5, `…`))
+	assert.Equal(`…本語`, ElideRight(`日本語`, 5, `…`))
+	assert.Equal("café", Elide("cafés", 4))
+	assert.Equal(`👍🏽`, Elide(`👍🏽👍🏽`, 3))
+
+	// escape sequences are never cut, and resets are kept
+	assert.Equal("\x1b[0;31mhel\x1b[0m...", Elide("\x1b[0;31mhello\x1b[0m", 3, `...`))
+	assert.Equal("...\x1b[0;31mllo\x1b[0m", ElideRight("\x1b[0;31mhello\x1b[0m", 3, `...`))
+
+	// multi-line strings are elided by their total width, not that of their widest line
+	assert.Equal("aaa\nb...", Elide("aaa\nbbb\nccc", 4, `...`))
+	assert.Equal("...b\nccc", ElideRight("aaa\nbbb\nccc", 4, `...`))
+	assert.Equal("a\nb", Elide("a\nb", 2, `...`))
+
+	// zero-width characters at the cut point are removed with it
+	assert.Equal(`abcdef...`, Elide("abcdef\tghi", 6, `...`))
+	assert.Equal(`...ghi`, ElideRight("abc\tghi", 3, `...`))
+	assert.Equal("ab\tc...", Elide("ab\tcdef", 3, `...`))
+	assert.Equal("ab\x1b[0m...", Elide("ab\t\x1b[0mcd", 2, `...`))
+}
+
+func TestWordWrap(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(strings.Join([]string{
+		`The quick brown`,
+		`fox jumps over`,
+		`the lazy dog.`,
+	}, "\n"), WordWrap(`The quick brown fox jumps over the lazy dog.`, 15))
+
+	// paragraphs and blank lines are preserved
+	assert.Equal("one two\nthree\n\nfour", WordWrap("one two three\n\nfour", 8))
+
+	// long words are hard-wrapped
+	assert.Equal("a\nabcdef\nghij b", WordWrap(`a abcdefghij b`, 6))
+
+	// wide characters are measured by display width
+	assert.Equal("日本語\nです", WordWrap(`日本語です`, 6))
+
+	// ANSI sequences do not count toward the width
+	assert.Equal("\x1b[0;31mred\x1b[0m words\nhere", WordWrap("\x1b[0;31mred\x1b[0m words here", 9))
+
+	assert.Equal(strings.Join([]string{
+		`  - Lorem ipsum`,
+		`    dolor sit`,
+		`    amet`,
+	}, "\n"), WrapOptions{
+		Width:         15,
+		Indent:        `  - `,
+		HangingIndent: `    `,
+	}.Wrap(`Lorem ipsum dolor sit amet`))
+
+	assert.Equal("a\nabcdefghij\nb", WrapOptions{
+		Width:         6,
+		KeepLongWords: true,
+	}.Wrap(`a abcdefghij b`))
+
+	assert.Equal(`no width limit here`, WordWrap(`no   width limit here`, 0))
+}
@@ -0,0 +1,595 @@
+package typeutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mgutz/ansi"
+)
+
+// Color expressions (in the same format accepted by the log package, e.g. "red+b") used
+// to highlight the various parts of dumped values.
+type DumpColors struct {
+	Type   string
+	Field  string
+	String string
+	Number string
+	Bool   string
+	Nil    string
+	Marker string
+	Insert string
+	Delete string
+	Change string
+}
+
+var DefaultDumpColors = DumpColors{
+	Type:   `blue`,
+	Field:  `cyan`,
+	String: `green`,
+	Number: `yellow`,
+	Bool:   `magenta`,
+	Nil:    `red`,
+	Marker: `black+h`,
+	Insert: `green`,
+	Delete: `red`,
+	Change: `yellow`,
+}
+
+// A Dumper renders arbitrary values as human-readable, Go-like text.
+type Dumper struct {
+	// The string used to indent nested values (not used in compact mode).
+	Indent string
+
+	// The maximum nesting depth to render; deeper values are elided. Zero means unlimited.
+	MaxDepth int
+
+	// The maximum number of elements, map entries, or struct fields to render for any
+	// single value; the remainder are summarized. Zero means unlimited.
+	MaxItems int
+
+	// Render values on a single line, suitable for log output.
+	Compact bool
+
+	// Highlight output with ANSI color sequences.
+	Color bool
+
+	// Omit unexported struct fields from the output.
+	HideUnexported bool
+
+	// The colors to use when Color is enabled.
+	Colors DumpColors
+}
+
+// the number of bytes rendered on each line when dumping byte slices
+var dumpBytesPerRow = 16
+
+// The Dumper used by the package-level Dump, Dumpf, and DumpDiff functions.
+var DefaultDumper = NewDumper()
+
+// Returns a new Dumper with default settings.
+func NewDumper() *Dumper {
+	return &Dumper{
+		Indent: `    `,
+		Colors: DefaultDumpColors,
+	}
+}
+
+// Returns a pretty-printed string representation of the given values, one per line.
+func (self *Dumper) Dump(values ...interface{}) string {
+	var out = make([]string, len(values))
+
+	for i, value := range values {
+		var state = &dumpState{
+			dumper:   self,
+			visiting: make(map[dumpVisit]bool),
+		}
+
+		state.dump(reflect.ValueOf(value), 0)
+		out[i] = state.buf.String()
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// Returns the pretty-printed representation of the given values, formatted with the given format string.
+func (self *Dumper) Dumpf(format string, values ...interface{}) string {
+	return fmt.Sprintf(format, self.Dump(values...))
+}
+
+// Renders the expected and actual values side-by-side, marking lines that only appear in
+// the expected value with "<", lines that only appear in the actual value with ">", and lines
+// that differ between the two with "|".
+func (self *Dumper) Diff(expected interface{}, actual interface{}) string {
+	var plain = *self
+
+	plain.Color = false
+	plain.Compact = false
+
+	var left = strings.Split(plain.Dump(expected), "\n")
+	var right = strings.Split(plain.Dump(actual), "\n")
+	var width int
+	var out = make([]string, 0)
+
+	for _, line := range left {
+		if w := utf8.RuneCountInString(line); w > width {
+			width = w
+		}
+	}
+
+	for _, row := range diffLines(left, right) {
+		var l = row.left + strings.Repeat(` `, width-utf8.RuneCountInString(row.left))
+		var r = row.right
+		var color string
+
+		switch row.marker {
+		case '<':
+			color = self.Colors.Delete
+		case '>':
+			color = self.Colors.Insert
+		case '|':
+			color = self.Colors.Change
+		}
+
+		// trim before colorizing, otherwise the trailing reset sequence keeps the padding
+		var line = strings.TrimRight(l+` `+string(row.marker)+` `+r, ` `)
+
+		if color != `` {
+			line = self.colorize(color, line)
+		}
+
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func (self *Dumper) colorize(expr string, text string) string {
+	if self.Color && expr != `` {
+		return ansi.ColorCode(expr) + text + ansi.Reset
+	} else {
+		return text
+	}
+}
+
+type dumpState struct {
+	dumper   *Dumper
+	buf      strings.Builder
+	visiting map[dumpVisit]bool
+}
+
+// identifies a value being dumped.  The type is needed as well as the address because a struct
+// and its first field share an address.
+type dumpVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func (self *dumpState) write(color string, text string) {
+	self.buf.WriteString(self.dumper.colorize(color, text))
+}
+
+func (self *dumpState) newline(depth int) {
+	if self.dumper.Compact {
+		return
+	}
+
+	self.buf.WriteString("\n")
+	self.buf.WriteString(strings.Repeat(self.dumper.Indent, depth))
+}
+
+func (self *dumpState) dump(value reflect.Value, depth int) {
+	var colors = self.dumper.Colors
+
+	if !value.IsValid() {
+		self.write(colors.Nil, `nil`)
+		return
+	}
+
+	if self.dumpStringer(value) {
+		return
+	}
+
+	var valueT = value.Type()
+
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			self.write(colors.Nil, `nil`)
+		} else {
+			self.dump(value.Elem(), depth)
+		}
+
+	case reflect.Ptr:
+		if value.IsNil() {
+			self.writeTypedNil(valueT)
+		} else if visit := (dumpVisit{value.Pointer(), valueT}); self.visiting[visit] {
+			self.write(colors.Marker, fmt.Sprintf("<cycle %v>", valueT))
+		} else {
+			self.visiting[visit] = true
+			self.buf.WriteString(`&`)
+			self.dump(value.Elem(), depth)
+			delete(self.visiting, visit)
+		}
+
+	case reflect.Map:
+		if value.IsNil() {
+			self.writeTypedNil(valueT)
+			return
+		}
+
+		var keys = value.MapKeys()
+
+		sort.Slice(keys, func(i int, j int) bool {
+			return dumpKeyLess(keys[i], keys[j])
+		})
+
+		self.dumpContainer(value, depth, len(keys), func(i int) {
+			self.dump(keys[i], depth+1)
+			self.buf.WriteString(`: `)
+			self.dump(value.MapIndex(keys[i]), depth+1)
+		})
+
+	case reflect.Slice:
+		if value.IsNil() {
+			self.writeTypedNil(valueT)
+			return
+		}
+
+		fallthrough
+
+	case reflect.Array:
+		if valueT.Elem().Kind() == reflect.Uint8 {
+			self.dumpBytes(value, depth)
+		} else {
+			self.dumpContainer(value, depth, value.Len(), func(i int) {
+				self.dump(value.Index(i), depth+1)
+			})
+		}
+
+	case reflect.Struct:
+		var fields = make([]int, 0, valueT.NumField())
+
+		for i := 0; i < valueT.NumField(); i++ {
+			if self.dumper.HideUnexported && valueT.Field(i).PkgPath != `` {
+				continue
+			}
+
+			fields = append(fields, i)
+		}
+
+		self.dumpContainer(value, depth, len(fields), func(i int) {
+			self.write(colors.Field, valueT.Field(fields[i]).Name)
+			self.buf.WriteString(`: `)
+			self.dump(value.Field(fields[i]), depth+1)
+		})
+
+	case reflect.String:
+		self.writeScalar(valueT, reflect.String, colors.String, strconv.Quote(value.String()))
+
+	case reflect.Bool:
+		self.writeScalar(valueT, reflect.Bool, colors.Bool, strconv.FormatBool(value.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		self.writeScalar(valueT, reflect.Int, colors.Number, strconv.FormatInt(value.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		self.writeScalar(valueT, reflect.Uint, colors.Number, strconv.FormatUint(value.Uint(), 10))
+
+	case reflect.Uintptr:
+		self.writeScalar(valueT, reflect.Invalid, colors.Number, fmt.Sprintf("0x%x", value.Uint()))
+
+	case reflect.Float32, reflect.Float64:
+		self.writeScalar(valueT, reflect.Float64, colors.Number, strconv.FormatFloat(value.Float(), 'g', -1, valueT.Bits()))
+
+	case reflect.Complex64, reflect.Complex128:
+		self.writeScalar(valueT, reflect.Invalid, colors.Number, strconv.FormatComplex(value.Complex(), 'g', -1, valueT.Bits()))
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if value.IsNil() {
+			self.writeTypedNil(valueT)
+		} else {
+			self.write(colors.Type, valueT.String())
+		}
+
+	default:
+		self.write(colors.Type, valueT.String())
+	}
+}
+
+// renders the opening and closing of a compound value, calling fn to render each item.
+func (self *dumpState) dumpContainer(value reflect.Value, depth int, length int, fn func(i int)) {
+	var colors = self.dumper.Colors
+
+	// guard against self-referential maps and slices
+	if kind := value.Kind(); (kind == reflect.Map || kind == reflect.Slice) && length > 0 {
+		if visit := (dumpVisit{value.Pointer(), value.Type()}); self.visiting[visit] {
+			self.write(colors.Marker, fmt.Sprintf("<cycle %v>", value.Type()))
+			return
+		} else {
+			self.visiting[visit] = true
+			defer delete(self.visiting, visit)
+		}
+	}
+
+	self.write(colors.Type, value.Type().String())
+
+	if length == 0 {
+		self.buf.WriteString(`{}`)
+		return
+	} else if max := self.dumper.MaxDepth; max > 0 && depth >= max {
+		self.buf.WriteString(`{`)
+		self.write(colors.Marker, `...`)
+		self.buf.WriteString(`}`)
+		return
+	}
+
+	var shown = length
+
+	if max := self.dumper.MaxItems; max > 0 && max < length {
+		shown = max
+	}
+
+	self.buf.WriteString(`{`)
+
+	for i := 0; i < shown; i++ {
+		if self.dumper.Compact && i > 0 {
+			self.buf.WriteString(`, `)
+		}
+
+		self.newline(depth + 1)
+		fn(i)
+
+		if !self.dumper.Compact {
+			self.buf.WriteString(`,`)
+		}
+	}
+
+	if shown < length {
+		if self.dumper.Compact {
+			self.buf.WriteString(`, `)
+		}
+
+		self.newline(depth + 1)
+		self.write(colors.Marker, fmt.Sprintf("...%d more", length-shown))
+	}
+
+	self.newline(depth)
+	self.buf.WriteString(`}`)
+}
+
+// renders byte slices and arrays as rows of hex values rather than one value per line.
+func (self *dumpState) dumpBytes(value reflect.Value, depth int) {
+	var colors = self.dumper.Colors
+	var length = value.Len()
+	var shown = length
+
+	self.write(colors.Type, value.Type().String())
+
+	if length == 0 {
+		self.buf.WriteString(`{}`)
+		return
+	} else if max := self.dumper.MaxDepth; max > 0 && depth >= max {
+		self.buf.WriteString(`{`)
+		self.write(colors.Marker, `...`)
+		self.buf.WriteString(`}`)
+		return
+	}
+
+	if max := self.dumper.MaxItems; max > 0 && max < length {
+		shown = max
+	}
+
+	// short values and compact output stay on one line
+	var rows = !self.dumper.Compact && length > dumpBytesPerRow
+
+	self.buf.WriteString(`{`)
+
+	for i := 0; i < shown; i++ {
+		if rows && i%dumpBytesPerRow == 0 {
+			if i > 0 {
+				self.buf.WriteString(`,`)
+			}
+
+			self.newline(depth + 1)
+		} else if i > 0 {
+			self.buf.WriteString(`, `)
+		}
+
+		self.write(colors.Number, fmt.Sprintf("0x%02x", value.Index(i).Uint()))
+	}
+
+	if shown < length {
+		self.buf.WriteString(`, `)
+		self.write(colors.Marker, fmt.Sprintf("...%d more", length-shown))
+	}
+
+	if rows {
+		self.buf.WriteString(`,`)
+		self.newline(depth)
+	}
+
+	self.buf.WriteString(`}`)
+}
+
+// renders values that implement the error or fmt.Stringer interfaces using those methods.
+func (self *dumpState) dumpStringer(value reflect.Value) (handled bool) {
+	switch value.Kind() {
+	case reflect.Interface, reflect.Map, reflect.Slice, reflect.Array:
+		return false
+	case reflect.Ptr:
+		if value.IsNil() {
+			return false
+		}
+	}
+
+	if !value.CanInterface() {
+		return false
+	}
+
+	var text string
+
+	defer func() {
+		if recover() != nil {
+			handled = false
+		}
+	}()
+
+	switch v := value.Interface().(type) {
+	case error:
+		text = v.Error()
+	case fmt.Stringer:
+		text = v.String()
+	default:
+		return false
+	}
+
+	self.write(self.dumper.Colors.Type, value.Type().String())
+	self.buf.WriteString(`(`)
+	self.write(self.dumper.Colors.String, text)
+	self.buf.WriteString(`)`)
+
+	return true
+}
+
+func (self *dumpState) writeTypedNil(valueT reflect.Type) {
+	if valueT.Kind() == reflect.Ptr || valueT.Kind() == reflect.Func {
+		self.buf.WriteString(`(`)
+		self.write(self.dumper.Colors.Type, valueT.String())
+		self.buf.WriteString(`)`)
+	} else {
+		self.write(self.dumper.Colors.Type, valueT.String())
+	}
+
+	self.buf.WriteString(`(`)
+	self.write(self.dumper.Colors.Nil, `nil`)
+	self.buf.WriteString(`)`)
+}
+
+// writes a scalar value, prefixed by its type name if it is not the default type for its literal form.
+func (self *dumpState) writeScalar(valueT reflect.Type, literalKind reflect.Kind, color string, text string) {
+	if valueT.Kind() == literalKind && valueT.Name() == literalKind.String() && valueT.PkgPath() == `` {
+		self.write(color, text)
+	} else {
+		self.write(self.dumper.Colors.Type, valueT.String())
+		self.buf.WriteString(`(`)
+		self.write(color, text)
+		self.buf.WriteString(`)`)
+	}
+}
+
+// orders map keys numerically if both are numbers, otherwise lexically.
+func dumpKeyLess(a reflect.Value, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+
+	if af, ok := dumpNumber(a); ok {
+		if bf, ok := dumpNumber(b); ok {
+			return af < bf
+		}
+	}
+
+	return fmt.Sprintf("%v", dumpSortable(a)) < fmt.Sprintf("%v", dumpSortable(b))
+}
+
+func dumpNumber(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func dumpSortable(value reflect.Value) interface{} {
+	if !value.IsValid() {
+		return ``
+	} else if value.Kind() == reflect.String {
+		return value.String()
+	} else if value.CanInterface() {
+		return value.Interface()
+	} else {
+		return value.String()
+	}
+}
+
+type diffRow struct {
+	left   string
+	right  string
+	marker rune
+}
+
+// aligns two sets of lines using their longest common subsequence.
+func diffLines(left []string, right []string) []diffRow {
+	var lcs = make([][]int, len(left)+1)
+
+	for i := range lcs {
+		lcs[i] = make([]int, len(right)+1)
+	}
+
+	for i := len(left) - 1; i >= 0; i-- {
+		for j := len(right) - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var rows = make([]diffRow, 0)
+	var deleted, inserted []string
+
+	// pair up runs of deletions and insertions as changed lines
+	var flush = func() {
+		for k := 0; k < len(deleted) || k < len(inserted); k++ {
+			switch {
+			case k < len(deleted) && k < len(inserted):
+				rows = append(rows, diffRow{left: deleted[k], right: inserted[k], marker: '|'})
+			case k < len(deleted):
+				rows = append(rows, diffRow{left: deleted[k], marker: '<'})
+			default:
+				rows = append(rows, diffRow{right: inserted[k], marker: '>'})
+			}
+		}
+
+		deleted = nil
+		inserted = nil
+	}
+
+	var i, j int
+
+	for i < len(left) || j < len(right) {
+		switch {
+		case i < len(left) && j < len(right) && left[i] == right[j]:
+			flush()
+			rows = append(rows, diffRow{left: left[i], right: right[j], marker: ' '})
+			i++
+			j++
+		case j >= len(right) || (i < len(left) && lcs[i+1][j] >= lcs[i][j+1]):
+			deleted = append(deleted, left[i])
+			i++
+		default:
+			inserted = append(inserted, right[j])
+			j++
+		}
+	}
+
+	flush()
+
+	return rows
+}
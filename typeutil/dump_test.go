@@ -0,0 +1,197 @@
+package typeutil
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghetzel/testify/require"
+)
+
+type dumpTestNode struct {
+	Name   string
+	Count  int64
+	labels []string
+	Next   *dumpTestNode
+}
+
+type dumpTestInner struct {
+	Value int
+}
+
+type dumpTestOuter struct {
+	First dumpTestInner
+	Ref   *dumpTestInner
+}
+
+func TestDump(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`nil`, Dump(nil))
+	assert.Equal(`"hello"`, Dump(`hello`))
+	assert.Equal(`42`, Dump(42))
+	assert.Equal(`int64(42)`, Dump(int64(42)))
+	assert.Equal(`1.5`, Dump(1.5))
+	assert.Equal(`true`, Dump(true))
+	assert.Equal("1\n\"two\"", Dump(1, `two`))
+	assert.Equal(`time.Duration(1s)`, Dump(time.Second))
+	assert.Equal(`*errors.errorString(boom)`, Dump(errors.New(`boom`)))
+	assert.Equal(`[]string(nil)`, Dump([]string(nil)))
+	assert.Equal(`(*typeutil.dumpTestNode)(nil)`, Dump((*dumpTestNode)(nil)))
+	assert.Equal(`[]int{}`, Dump([]int{}))
+
+	assert.Equal(strings.Join([]string{
+		`map[string]interface {}{`,
+		`    "a": 1,`,
+		`    "b": []int{`,
+		`        1,`,
+		`        2,`,
+		`    },`,
+		`    "c": nil,`,
+		`}`,
+	}, "\n"), Dump(map[string]interface{}{
+		`c`: nil,
+		`b`: []int{1, 2},
+		`a`: 1,
+	}))
+
+	// numeric keys sort numerically
+	assert.Equal(strings.Join([]string{
+		`map[int]bool{`,
+		`    2: true,`,
+		`    10: false,`,
+		`}`,
+	}, "\n"), Dump(map[int]bool{10: false, 2: true}))
+
+	// unexported fields are shown by default, and cycles are marked
+	node := &dumpTestNode{
+		Name:   `first`,
+		Count:  1,
+		labels: []string{`x`},
+	}
+
+	node.Next = node
+
+	assert.Equal(strings.Join([]string{
+		`&typeutil.dumpTestNode{`,
+		`    Name: "first",`,
+		`    Count: int64(1),`,
+		`    labels: []string{`,
+		`        "x",`,
+		`    },`,
+		`    Next: <cycle *typeutil.dumpTestNode>,`,
+		`}`,
+	}, "\n"), Dump(node))
+
+	// byte slices are rendered on one line, or in rows of 16 when longer than that
+	assert.Equal(`[]uint8{0x68, 0x69}`, Dump([]byte(`hi`)))
+	assert.Equal(`[2]uint8{0x00, 0xff}`, Dump([2]byte{0, 255}))
+	assert.Equal(strings.Join([]string{
+		`[]uint8{`,
+		`    0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66,`,
+		`    0x67, 0x68,`,
+		`}`,
+	}, "\n"), Dump([]byte(`0123456789abcdefgh`)))
+
+	// a pointer to a struct's first field shares the struct's address, but is not a cycle
+	outer := &dumpTestOuter{
+		First: dumpTestInner{
+			Value: 1,
+		},
+	}
+
+	outer.Ref = &outer.First
+
+	assert.Equal(strings.Join([]string{
+		`&typeutil.dumpTestOuter{`,
+		`    First: typeutil.dumpTestInner{`,
+		`        Value: 1,`,
+		`    },`,
+		`    Ref: &typeutil.dumpTestInner{`,
+		`        Value: 1,`,
+		`    },`,
+		`}`,
+	}, "\n"), Dump(outer))
+
+	cyclic := make(map[string]interface{})
+	cyclic[`self`] = cyclic
+	assert.Equal("map[string]interface {}{\n    \"self\": <cycle map[string]interface {}>,\n}", Dump(cyclic))
+}
+
+func TestDumperOptions(t *testing.T) {
+	assert := require.New(t)
+
+	dumper := NewDumper()
+	dumper.Compact = true
+
+	value := map[string]interface{}{
+		`a`: []int{1, 2, 3},
+		`b`: map[string]interface{}{
+			`c`: map[string]interface{}{
+				`d`: true,
+			},
+		},
+		`e`: []byte(`hi`),
+	}
+
+	assert.Equal(
+		`map[string]interface {}{"a": []int{1, 2, 3}, "b": map[string]interface {}{"c": map[string]interface {}{"d": true}}, "e": []uint8{0x68, 0x69}}`,
+		dumper.Dump(value),
+	)
+
+	dumper.MaxDepth = 2
+	assert.Equal(
+		`map[string]interface {}{"a": []int{1, 2, 3}, "b": map[string]interface {}{"c": map[string]interface {}{...}}, "e": []uint8{0x68, 0x69}}`,
+		dumper.Dump(value),
+	)
+
+	dumper.MaxDepth = 0
+	dumper.MaxItems = 2
+	assert.Equal(
+		`map[string]interface {}{"a": []int{1, 2, ...1 more}, "b": map[string]interface {}{"c": map[string]interface {}{"d": true}}, ...1 more}`,
+		dumper.Dump(value),
+	)
+
+	assert.Equal(`[]uint8{0x68, 0x65, ...3 more}`, dumper.Dump([]byte(`hello`)))
+
+	dumper = NewDumper()
+	dumper.Compact = true
+	dumper.HideUnexported = true
+	assert.Equal(
+		`typeutil.dumpTestNode{Name: "x", Count: int64(0), Next: (*typeutil.dumpTestNode)(nil)}`,
+		dumper.Dump(dumpTestNode{Name: `x`, labels: []string{`y`}}),
+	)
+
+	dumper.Color = true
+	assert.Equal("\x1b[0;34m[]interface {}\x1b[0m{\x1b[0;33m1\x1b[0m, \x1b[0;32m\"x\"\x1b[0m, \x1b[0;31mnil\x1b[0m}", dumper.Dump([]interface{}{1, `x`, nil}))
+}
+
+func TestDumpDiff(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(strings.Join([]string{
+		`map[string]int{   map[string]int{`,
+		`    "a": 1,           "a": 1,`,
+		`    "b": 2,     |     "b": 3,`,
+		`                >     "c": 4,`,
+		`}                 }`,
+	}, "\n"), DumpDiff(
+		map[string]int{`a`: 1, `b`: 2},
+		map[string]int{`a`: 1, `b`: 3, `c`: 4},
+	))
+
+	assert.Equal(strings.Join([]string{
+		`[]string{   []string{`,
+		`    "a",  <`,
+		`    "b",        "b",`,
+		`}           }`,
+	}, "\n"), DumpDiff([]string{`a`, `b`}, []string{`b`}))
+
+	// colorized lines are trimmed inside the color sequences
+	dumper := NewDumper()
+	dumper.Color = true
+
+	var lines = strings.Split(dumper.Diff([]string{`a`, `b`}, []string{`b`}), "\n")
+	assert.Equal("\x1b[0;31m    \"a\",  <\x1b[0m", lines[1])
+}
@@ -8,18 +8,11 @@ import (
 	"strings"
 	"time"
 
-	"github.com/davecgh/go-spew/spew"
 	"github.com/ghetzel/go-stockutil/utils"
 )
 
 type TypeConvertFunc = utils.TypeConvertFunc
 
-var scs = spew.ConfigState{
-	Indent:            `    `,
-	DisableCapacities: true,
-	SortKeys:          true,
-}
-
 // Register's a handler used for converting one type to another. Type are checked in the following
 // manner:  The input value's reflect.Type String() value is matched, falling back to its
 // reflect.Kind String() value, finally checking for a special "*" value that matches any type.
@@ -198,12 +191,17 @@ func Len(in interface{}) int {
 
 // Returns a pretty-printed string representation of the given values.
 func Dump(in1 interface{}, in ...interface{}) string {
-	return scs.Sdump(append([]interface{}{in1}, in...)...)
+	return DefaultDumper.Dump(append([]interface{}{in1}, in...)...)
 }
 
 // Returns a pretty-printed string representation of the given values.
 func Dumpf(format string, in ...interface{}) string {
-	return fmt.Sprintf(format, scs.Sdump(in...))
+	return DefaultDumper.Dumpf(format, in...)
+}
+
+// Returns a side-by-side comparison of the pretty-printed representations of the given values.
+func DumpDiff(expected interface{}, actual interface{}) string {
+	return DefaultDumper.Diff(expected, actual)
 }
 
 // Attempts to set the given reflect.Value to the given interface value
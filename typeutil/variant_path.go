@@ -0,0 +1,460 @@
+package typeutil
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// The struct tag consulted when resolving path segments against struct fields.
+var VariantPathStructTag = `json`
+
+// Returns the value found by following the given sequence of keys and indices into the
+// underlying value.  Each key can be a map key, struct field name (or the name given in its
+// json tag), or integer index into an array or slice.  Pointers and interfaces are
+// dereferenced along the way.  A nil Variant is returned if the path does not exist.
+func (self Variant) Get(keys ...interface{}) Variant {
+	if value, ok := variantLookup(reflect.ValueOf(self.Interface()), keys); ok && value.IsValid() && value.CanInterface() {
+		return V(value.Interface())
+	} else {
+		return Nil()
+	}
+}
+
+// Returns the element at the given index of an array or slice value. Negative indices are
+// counted backwards from the end. A nil Variant is returned if the index is out of range.
+func (self Variant) At(index int) Variant {
+	return self.Get(index)
+}
+
+// Returns the value at the given path expression.  Paths are a series of keys separated by
+// periods, with array indices and keys containing special characters given in brackets
+// (e.g.: "a.b[2].c", `users[0]["first.name"]`).  A nil Variant is returned if the path is
+// invalid or does not exist.
+func (self Variant) Path(path string) Variant {
+	if keys, err := parseVariantPath(path); err == nil {
+		return self.Get(keys...)
+	} else {
+		return Nil()
+	}
+}
+
+// Returns whether the given path expression exists in the underlying value, even if the
+// value at that path is nil.
+func (self Variant) Has(path string) bool {
+	if keys, err := parseVariantPath(path); err == nil {
+		_, ok := variantLookup(reflect.ValueOf(self.Interface()), keys)
+		return ok
+	} else {
+		return false
+	}
+}
+
+// Sets the value at the given path expression. Intermediate maps and slices are created as
+// needed, slices are grown to accommodate indices beyond their current length, and values
+// are converted to the type of their destination where possible.  Struct fields can only be
+// set if they are exported.
+func (self *Variant) Set(path string, value interface{}) error {
+	keys, err := parseVariantPath(path)
+
+	if err != nil {
+		return err
+	} else if len(keys) == 0 {
+		self.Value = V(value).Interface()
+		return nil
+	}
+
+	if root, err := variantSet(reflect.ValueOf(self.Interface()), keys, value); err == nil {
+		if root.IsValid() && root.CanInterface() {
+			self.Value = root.Interface()
+		} else {
+			self.Value = nil
+		}
+
+		return nil
+	} else {
+		return fmt.Errorf("cannot set %q: %v", path, err)
+	}
+}
+
+func variantLookup(current reflect.Value, keys []interface{}) (reflect.Value, bool) {
+	for _, key := range keys {
+		current = variantIndirect(current)
+
+		if !current.IsValid() {
+			return current, false
+		}
+
+		switch current.Kind() {
+		case reflect.Map:
+			if keyV, ok := variantMapKey(current.Type(), key); ok {
+				if value := current.MapIndex(keyV); value.IsValid() {
+					current = value
+					continue
+				}
+			}
+
+			return reflect.Value{}, false
+
+		case reflect.Array, reflect.Slice:
+			if index, ok := variantIndex(key, current.Len()); ok && index < current.Len() {
+				current = current.Index(index)
+				continue
+			}
+
+			return reflect.Value{}, false
+
+		case reflect.Struct:
+			if field := variantStructField(current, key); field.IsValid() {
+				current = field
+				continue
+			}
+
+			return reflect.Value{}, false
+
+		default:
+			return reflect.Value{}, false
+		}
+	}
+
+	if current.IsValid() && current.Kind() == reflect.Interface {
+		current = current.Elem()
+	}
+
+	return current, true
+}
+
+// sets the value at the given keys within current, returning the value current should be
+// replaced with (which may be a copy of it, or a newly-created container).
+func variantSet(current reflect.Value, keys []interface{}, value interface{}) (reflect.Value, error) {
+	if len(keys) == 0 {
+		return reflect.ValueOf(V(value).Interface()), nil
+	}
+
+	var key = keys[0]
+	var rest = keys[1:]
+
+	if current.IsValid() && current.Kind() == reflect.Interface {
+		current = current.Elem()
+	}
+
+	// create containers for nonexistent intermediate values
+	if !current.IsValid() {
+		if _, ok := key.(int); ok {
+			current = reflect.ValueOf(make([]interface{}, 0))
+		} else {
+			current = reflect.ValueOf(make(map[string]interface{}))
+		}
+	}
+
+	switch current.Kind() {
+	case reflect.Ptr:
+		if current.IsNil() {
+			current = reflect.New(current.Type().Elem())
+		}
+
+		if elem, err := variantSet(current.Elem(), keys, value); err == nil {
+			return current, variantAssign(current.Elem(), elem)
+		} else {
+			return current, err
+		}
+
+	case reflect.Map:
+		var mapT = current.Type()
+
+		if current.IsNil() {
+			current = reflect.MakeMap(mapT)
+		}
+
+		if keyV, ok := variantMapKey(mapT, key); ok {
+			if child, err := variantSet(current.MapIndex(keyV), rest, value); err == nil {
+				var elem = reflect.New(mapT.Elem()).Elem()
+
+				if err := variantAssign(elem, child); err == nil {
+					current.SetMapIndex(keyV, elem)
+					return current, nil
+				} else {
+					return current, err
+				}
+			} else {
+				return current, err
+			}
+		} else {
+			return current, fmt.Errorf("%v is not a valid key for %v", key, mapT)
+		}
+
+	case reflect.Slice, reflect.Array:
+		var index, ok = variantIndex(key, current.Len())
+
+		if !ok {
+			return current, fmt.Errorf("invalid index %v", key)
+		}
+
+		if index >= current.Len() {
+			if current.Kind() == reflect.Array {
+				return current, fmt.Errorf("index %d out of range", index)
+			}
+
+			current = reflect.AppendSlice(current, reflect.MakeSlice(current.Type(), index-current.Len()+1, index-current.Len()+1))
+		} else if !current.Index(index).CanSet() {
+			// arrays held by value are not addressable, so work on a copy
+			var dup = reflect.New(current.Type()).Elem()
+			dup.Set(current)
+			current = dup
+		}
+
+		var elem = current.Index(index)
+
+		if child, err := variantSet(elem, rest, value); err == nil {
+			return current, variantAssign(elem, child)
+		} else {
+			return current, err
+		}
+
+	case reflect.Struct:
+		// structs held by value are not addressable, so work on a copy
+		if !current.CanAddr() {
+			var dup = reflect.New(current.Type()).Elem()
+			dup.Set(current)
+			current = dup
+		}
+
+		if field := variantStructField(current, key); field.IsValid() {
+			if !field.CanSet() {
+				return current, fmt.Errorf("field %v cannot be set", key)
+			}
+
+			if child, err := variantSet(field, rest, value); err == nil {
+				return current, variantAssign(field, child)
+			} else {
+				return current, err
+			}
+		} else {
+			return current, fmt.Errorf("no such field %v in %v", key, current.Type())
+		}
+
+	default:
+		return current, fmt.Errorf("cannot set %v on a %v value", key, current.Kind())
+	}
+}
+
+func variantAssign(target reflect.Value, value reflect.Value) error {
+	if !value.IsValid() {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	} else if value.Type().AssignableTo(target.Type()) {
+		target.Set(value)
+		return nil
+	} else {
+		return SetValue(target, value.Interface())
+	}
+}
+
+func variantIndirect(value reflect.Value) reflect.Value {
+	for value.IsValid() {
+		switch value.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if value.IsNil() {
+				return reflect.Value{}
+			}
+
+			value = value.Elem()
+		default:
+			if !value.CanInterface() {
+				return value
+			} else if vv, ok := value.Interface().(Variant); ok {
+				value = reflect.ValueOf(vv.Value)
+				continue
+			}
+
+			return value
+		}
+	}
+
+	return value
+}
+
+func variantIndex(key interface{}, length int) (int, bool) {
+	var index int
+
+	switch keyV := reflect.ValueOf(key); keyV.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		index = int(keyV.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		index = int(keyV.Uint())
+	case reflect.String:
+		if i, err := strconv.Atoi(keyV.String()); err == nil {
+			index = i
+		} else {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if index < 0 {
+		index += length
+	}
+
+	return index, (index >= 0)
+}
+
+func variantMapKey(mapT reflect.Type, key interface{}) (reflect.Value, bool) {
+	var keyT = mapT.Key()
+	var keyV = reflect.ValueOf(key)
+
+	if !keyV.IsValid() {
+		return keyV, false
+	} else if keyV.Type().AssignableTo(keyT) {
+		return keyV, true
+	}
+
+	switch keyT.Kind() {
+	case reflect.Interface:
+		return keyV, true
+	case reflect.String:
+		return reflect.ValueOf(String(key)).Convert(keyT), true
+	}
+
+	// convert numeric strings into numeric keys
+	if autoV := reflect.ValueOf(Auto(key)); autoV.IsValid() && autoV.Type().ConvertibleTo(keyT) {
+		return autoV.Convert(keyT), true
+	} else {
+		return keyV, false
+	}
+}
+
+func variantStructField(structV reflect.Value, key interface{}) reflect.Value {
+	var name = String(key)
+	var structT = structV.Type()
+
+	if name == `` {
+		return reflect.Value{}
+	}
+
+	// tagged names take precedence over field names
+	for i := 0; i < structT.NumField(); i++ {
+		var fieldT = structT.Field(i)
+
+		if fieldT.PkgPath != `` && !fieldT.Anonymous {
+			continue
+		} else if tag := strings.Split(fieldT.Tag.Get(VariantPathStructTag), `,`)[0]; tag == `-` {
+			continue
+		} else if tag == name {
+			return structV.Field(i)
+		} else if tag == `` && fieldT.Anonymous {
+			if embedded := variantIndirect(structV.Field(i)); embedded.IsValid() && embedded.Kind() == reflect.Struct {
+				if field := variantStructField(embedded, key); field.IsValid() {
+					return field
+				}
+			}
+		}
+	}
+
+	if fieldT, ok := structT.FieldByName(name); ok && fieldT.PkgPath == `` {
+		if tag := strings.Split(fieldT.Tag.Get(VariantPathStructTag), `,`)[0]; tag == `` {
+			var field = structV
+
+			// walk the field index manually so that nil embedded pointers don't panic
+			for _, i := range fieldT.Index {
+				if field = variantIndirect(field); field.IsValid() && field.Kind() == reflect.Struct {
+					field = field.Field(i)
+				} else {
+					return reflect.Value{}
+				}
+			}
+
+			return field
+		}
+	}
+
+	return reflect.Value{}
+}
+
+// splits a path like `a.b[2]["c.d"]` into []interface{}{"a", "b", 2, "c.d"}
+func parseVariantPath(path string) ([]interface{}, error) {
+	var keys = make([]interface{}, 0)
+	var current strings.Builder
+	var pending bool
+	var runes = []rune(path)
+
+	var flush = func() {
+		if pending || current.Len() > 0 {
+			keys = append(keys, current.String())
+		}
+
+		current.Reset()
+		pending = false
+	}
+
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+				pending = true
+			}
+
+		case '.':
+			flush()
+
+		case '[':
+			flush()
+
+			var end = i + 1
+
+			for end < len(runes) && runes[end] != ']' {
+				if runes[end] == '"' || runes[end] == '\'' {
+					var quote = runes[end]
+
+					for end++; end < len(runes) && runes[end] != quote; end++ {
+						if runes[end] == '\\' {
+							end++
+						}
+					}
+
+					if end >= len(runes) {
+						return nil, fmt.Errorf("unterminated quote in path %q at position %d", path, i)
+					}
+				}
+
+				end++
+			}
+
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated bracket in path %q at position %d", path, i)
+			}
+
+			var inner = strings.TrimSpace(string(runes[i+1 : end]))
+
+			if index, err := strconv.Atoi(inner); err == nil {
+				keys = append(keys, index)
+			} else if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+				if inner[0] == '\'' {
+					inner = `"` + strings.ReplaceAll(strings.ReplaceAll(inner[1:len(inner)-1], `"`, `\"`), `\'`, `'`) + `"`
+				}
+
+				if unquoted, err := strconv.Unquote(inner); err == nil {
+					keys = append(keys, unquoted)
+				} else {
+					return nil, fmt.Errorf("invalid key %s in path %q: %v", inner, path, err)
+				}
+			} else if inner == `` {
+				return nil, fmt.Errorf("empty brackets in path %q at position %d", path, i)
+			} else {
+				keys = append(keys, inner)
+			}
+
+			i = end
+
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return keys, nil
+}
@@ -0,0 +1,160 @@
+package typeutil
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+type vPathAddress struct {
+	Street string `json:"street"`
+	City   string
+	Zip    string `json:"-"`
+}
+
+type vPathUser struct {
+	Name      string        `json:"name"`
+	Tags      []string      `json:"tags,omitempty"`
+	Address   *vPathAddress `json:"address"`
+	Previous  []vPathAddress
+	Extra     map[string]interface{} `json:"extra"`
+	secretKey string
+}
+
+func TestVariantPathGet(t *testing.T) {
+	assert := require.New(t)
+
+	doc := V(map[string]interface{}{
+		`a`: map[string]interface{}{
+			`b`: []interface{}{
+				1,
+				2,
+				map[string]interface{}{
+					`c`: `hello`,
+				},
+			},
+			`with.dot`: true,
+			`nothing`:  nil,
+		},
+		`user`: &vPathUser{
+			Name: `Alice`,
+			Tags: []string{`admin`, `ops`},
+			Address: &vPathAddress{
+				Street: `123 Fake St`,
+				City:   `Springfield`,
+				Zip:    `12345`,
+			},
+			Previous: []vPathAddress{
+				{City: `Shelbyville`},
+			},
+			secretKey: `hunter2`,
+		},
+		`counts`: map[int]string{
+			1: `one`,
+		},
+	})
+
+	assert.Equal(`hello`, doc.Path(`a.b[2].c`).String())
+	assert.Equal(`hello`, doc.Path(`a.b.2.c`).String())
+	assert.Equal(`hello`, doc.Get(`a`, `b`, 2, `c`).String())
+	assert.Equal(int64(2), doc.Get(`a`, `b`).At(1).Int())
+	assert.Equal(`hello`, doc.Get(`a`, `b`).At(-1).Get(`c`).String())
+	assert.True(doc.Path(`a["with.dot"]`).Bool())
+	assert.True(doc.Path(`a['with.dot']`).Bool())
+	assert.True(doc.Path(`a.with\.dot`).Bool())
+
+	// structs honor json tags, and fall back to field names
+	assert.Equal(`Alice`, doc.Path(`user.name`).String())
+	assert.Equal(`ops`, doc.Path(`user.tags[1]`).String())
+	assert.Equal(`123 Fake St`, doc.Path(`user.address.street`).String())
+	assert.Equal(`Springfield`, doc.Path(`user.address.City`).String())
+	assert.Equal(`Shelbyville`, doc.Path(`user.Previous[0].City`).String())
+	assert.True(doc.Path(`user.Name`).IsNil())
+	assert.True(doc.Path(`user.address.Zip`).IsNil())
+	assert.True(doc.Path(`user.secretKey`).IsNil())
+
+	// non-string map keys
+	assert.Equal(`one`, doc.Path(`counts.1`).String())
+
+	// missing paths
+	assert.True(doc.Path(`a.b[9]`).IsNil())
+	assert.True(doc.Path(`a.b[2].nope`).IsNil())
+	assert.True(doc.Path(`a.b[2].c.d`).IsNil())
+	assert.True(doc.Path(`a[`).IsNil())
+	assert.True(V(nil).Path(`a`).IsNil())
+	assert.Equal(doc, doc.Path(``))
+
+	// Has
+	assert.True(doc.Has(`a.b[2].c`))
+	assert.True(doc.Has(`a.nothing`))
+	assert.True(doc.Path(`a.nothing`).IsNil())
+	assert.False(doc.Has(`a.something`))
+	assert.False(doc.Has(`user.secretKey`))
+	assert.False(doc.Has(`a.b[3]`))
+}
+
+func TestVariantPathSet(t *testing.T) {
+	assert := require.New(t)
+
+	doc := VV(map[string]interface{}{
+		`a`: map[string]interface{}{
+			`b`: []interface{}{1, 2},
+		},
+	})
+
+	assert.NoError(doc.Set(`a.b[0]`, 42))
+	assert.Equal(int64(42), doc.Path(`a.b[0]`).Int())
+
+	assert.NoError(doc.Set(`a.b[3]`, `four`))
+	assert.Equal(`four`, doc.Path(`a.b[3]`).String())
+	assert.True(doc.Has(`a.b[2]`))
+	assert.Len(doc.Path(`a.b`).Slice(), 4)
+
+	assert.NoError(doc.Set(`x.y.z`, true))
+	assert.True(doc.Path(`x.y.z`).Bool())
+
+	assert.NoError(doc.Set(`list[1].name`, `second`))
+	assert.Equal(`second`, doc.Path(`list[1].name`).String())
+	assert.True(doc.Path(`list[0]`).IsNil())
+
+	// struct pointers are modified in place
+	user := &vPathUser{
+		Name: `Alice`,
+	}
+
+	uv := VV(user)
+
+	assert.NoError(uv.Set(`name`, `Bob`))
+	assert.Equal(`Bob`, user.Name)
+
+	assert.NoError(uv.Set(`address.street`, `742 Evergreen Terrace`))
+	assert.NotNil(user.Address)
+	assert.Equal(`742 Evergreen Terrace`, user.Address.Street)
+
+	assert.NoError(uv.Set(`tags[1]`, `ops`))
+	assert.Equal([]string{``, `ops`}, user.Tags)
+
+	assert.NoError(uv.Set(`extra.level`, 5))
+	assert.Equal(5, user.Extra[`level`])
+
+	assert.NoError(uv.Set(`Previous[0].City`, `Capital City`))
+	assert.Equal(`Capital City`, user.Previous[0].City)
+
+	// values are converted to the destination type
+	assert.NoError(uv.Set(`name`, 1234))
+	assert.Equal(`1234`, user.Name)
+
+	assert.Error(uv.Set(`secretKey`, `nope`))
+	assert.Error(uv.Set(`missing`, `nope`))
+	assert.Error(uv.Set(`name.first`, `nope`))
+	assert.Error(uv.Set(`a[`, `nope`))
+
+	// structs held by value are copied and replaced
+	sv := VV(vPathAddress{})
+	assert.NoError(sv.Set(`City`, `Ogdenville`))
+	assert.Equal(`Ogdenville`, sv.Value.(vPathAddress).City)
+
+	// empty paths replace the value outright
+	assert.NoError(sv.Set(``, 5))
+	assert.Equal(5, sv.Value)
+}
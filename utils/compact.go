@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var rxCompactNumber = regexp.MustCompile(`^(?P<number>[+-]?(?:\d+(?:\.\d*)?|\.\d+))\s*(?P<suffix>[A-Za-z]+)$`)
+
+// If set to true, Autotype will convert strings in compact notation (e.g.: "2.5k", "1.2M") into numbers.
+var AutotypeCompactNumbers = false
+
+// The multipliers applied to numbers in compact notation, keyed by lowercase suffix.
+var CompactNumberMultipliers = map[string]float64{
+	`k`:        1e3,
+	`thousand`: 1e3,
+	`m`:        1e6,
+	`mm`:       1e6,
+	`million`:  1e6,
+	`b`:        1e9,
+	`bn`:       1e9,
+	`billion`:  1e9,
+	`t`:        1e12,
+	`tn`:       1e12,
+	`trillion`: 1e12,
+}
+
+// Parses a number written in compact notation (e.g.: "2.5k", "-1.2M", "3 billion") and returns its value.
+// Plain numbers are also accepted.
+func ParseCompactNumber(in string) (float64, error) {
+	in = strings.TrimSpace(in)
+
+	if v, err := strconv.ParseFloat(in, 64); err == nil {
+		return v, nil
+	} else if match := rxCompactNumber.FindStringSubmatch(in); match != nil {
+		if multiplier, ok := CompactNumberMultipliers[strings.ToLower(match[2])]; ok {
+			if v, err := strconv.ParseFloat(match[1], 64); err == nil {
+				// round away floating point error (e.g.: 1.1 * 1e3 = 1100.0000000000002)
+				var product = v * multiplier
+
+				if rounded := math.Round(product); math.Abs(product-rounded) < 1e-9*math.Max(1, math.Abs(product)) {
+					product = rounded
+				}
+
+				return product, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("Cannot parse '%s' as a compact number", in)
+}
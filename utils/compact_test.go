@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestParseCompactNumber(t *testing.T) {
+	assert := require.New(t)
+
+	for in, want := range map[string]float64{
+		`42`:         42,
+		`2.5k`:       2500,
+		`1.1K`:       1100,
+		`-1.2M`:      -1200000,
+		`3 billion`:  3e9,
+		`5.6bn`:      5.6e9,
+		`.5t`:        5e11,
+		`7 thousand`: 7000,
+	} {
+		v, err := ParseCompactNumber(in)
+		assert.NoError(err, in)
+		assert.Equal(want, v, in)
+	}
+
+	for _, in := range []string{``, `k`, `2.5x`, `1.2.3k`, `twelve`} {
+		_, err := ParseCompactNumber(in)
+		assert.Error(err, in)
+	}
+}
+
+func TestAutotypeCompactNumbers(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(`2.5k`, Autotype(`2.5k`))
+
+	AutotypeCompactNumbers = true
+	defer func() {
+		AutotypeCompactNumbers = false
+	}()
+
+	assert.Equal(int64(2500), Autotype(`2.5k`))
+	assert.Equal(float64(1500.5), Autotype(`1.5005k`))
+	assert.Equal(int64(-3000000), Autotype(`-3M`))
+	assert.Equal(`hello`, Autotype(`hello`))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -264,40 +265,7 @@ func ConvertTo(toType ConvertType, inI interface{}) (interface{}, error) {
 			return inTime, nil
 		}
 
-		inS = strings.Trim(strings.TrimSpace(inS), `"'`)
-
-		if DetectTimeFormat(inS) == `epoch` {
-			if v, err := strconv.ParseInt(inS, 10, 64); err == nil {
-				return time.Unix(v, 0), nil
-			}
-		}
-
-		for _, format := range TimeFormats {
-			if tm, err := time.Parse(format, strings.TrimSpace(inS)); err == nil {
-				return tm, nil
-			}
-		}
-
-		switch inS {
-		case `now`:
-			return time.Now(), nil
-		default:
-			// handle time zero values
-			tmS := strings.Map(func(r rune) rune {
-				switch r {
-				case '-', ':', ' ', 'T', 'Z':
-					return '0'
-				}
-
-				return r
-			}, inS)
-
-			if v, err := strconv.ParseInt(tmS, 10, 64); err == nil && v == 0 {
-				return time.Time{}, nil
-			}
-
-			return nil, fmt.Errorf("Cannot convert '%s' into a date/time value", inS)
-		}
+		return convertStringToTime(inS, time.Now(), time.UTC)
 
 	case Bytes:
 		if inI == nil {
@@ -430,6 +398,35 @@ func ConvertToTime(in interface{}) (time.Time, error) {
 	}
 }
 
+// Converts the given value to a time, resolving relative and natural language expressions
+// (e.g.: "3 days ago", "next monday", "end of month") against the given reference time.  Values
+// that do not specify a time zone are interpreted in the given location. If loc is nil, the
+// reference time's location is used.  If the reference time is zero, the current time is used.
+func ConvertToTimeIn(in interface{}, reference time.Time, loc *time.Location) (time.Time, error) {
+	if reference.IsZero() {
+		reference = time.Now()
+	}
+
+	if loc == nil {
+		loc = reference.Location()
+	}
+
+	switch in.(type) {
+	case time.Time:
+		return in.(time.Time).In(loc), nil
+	default:
+		if inS, err := ToString(in); err == nil {
+			if tm, err := convertStringToTime(inS, reference.In(loc), loc); err == nil {
+				return tm.(time.Time), nil
+			} else {
+				return time.Time{}, err
+			}
+		} else {
+			return time.Time{}, err
+		}
+	}
+}
+
 func ConvertToBytes(in interface{}) ([]byte, error) {
 	if v, err := ConvertTo(Bytes, in); err == nil {
 		return v.([]byte), nil
@@ -452,6 +449,51 @@ func DetectTimeFormat(in string) string {
 	return ``
 }
 
+func convertStringToTime(inS string, reference time.Time, layoutLoc *time.Location) (interface{}, error) {
+	inS = strings.Trim(strings.TrimSpace(inS), `"'`)
+
+	if DetectTimeFormat(inS) == `epoch` {
+		if v, err := strconv.ParseInt(inS, 10, 64); err == nil {
+			return ConvertEpochToTime(v).In(reference.Location()), nil
+		}
+	}
+
+	for _, format := range TimeFormats {
+		if tm, err := time.ParseInLocation(format, inS, layoutLoc); err == nil {
+			return tm, nil
+		}
+	}
+
+	if tm, err := ParseISOWeekDate(inS, reference.Location()); err == nil {
+		return tm, nil
+	}
+
+	switch inS {
+	case `now`:
+		return reference, nil
+	default:
+		// handle time zero values
+		tmS := strings.Map(func(r rune) rune {
+			switch r {
+			case '-', ':', ' ', 'T', 'Z':
+				return '0'
+			}
+
+			return r
+		}, inS)
+
+		if v, err := strconv.ParseInt(tmS, 10, 64); err == nil && v == 0 {
+			return time.Time{}, nil
+		}
+
+		if tm, err := ParseRelativeTime(inS, reference); err == nil {
+			return tm, nil
+		}
+
+		return nil, fmt.Errorf("Cannot convert '%s' into a date/time value", inS)
+	}
+}
+
 // Returns the given value, converted according to any handlers set via RegisterTypeHandler.
 func ConvertCustomType(in interface{}) (interface{}, error) {
 	var convert TypeConvertFunc
@@ -519,6 +561,16 @@ func Detect(in interface{}) (ConvertType, interface{}) {
 				return Nil, nil
 			}
 		}
+
+		if AutotypeCompactNumbers && rxCompactNumber.MatchString(strings.TrimSpace(vStr)) {
+			if v, err := ParseCompactNumber(vStr); err == nil {
+				if v == math.Trunc(v) && math.Abs(v) < math.MaxInt64 {
+					return Integer, int64(v)
+				}
+
+				return Float, v
+			}
+		}
 	}
 
 	for _, ctype := range []ConvertType{
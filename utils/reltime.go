@@ -0,0 +1,549 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var rxIsoWeekDate = regexp.MustCompile(`^(?P<year>\d{4})-?[Ww](?P<week>\d{2})(?:-?(?P<day>[1-7]))?$`)
+var rxRelativeCompact = regexp.MustCompile(`^[+-]?(\d+[a-z]+)+$`)
+var rxRelativeCompactPart = regexp.MustCompile(`(\d+)([a-z]+)`)
+var rxClockTime = regexp.MustCompile(`^(?P<hour>\d{1,2})(?::(?P<minute>\d{2})(?::(?P<second>\d{2}))?)?(?P<meridiem>am|pm|a\.m\.|p\.m\.)?$`)
+
+// Epoch values whose magnitude is below these thresholds are interpreted as
+// seconds, milliseconds, and microseconds (respectively).  Anything larger is
+// treated as nanoseconds.
+var EpochSecondsThreshold int64 = 1e11
+var EpochMillisecondsThreshold int64 = 1e14
+var EpochMicrosecondsThreshold int64 = 1e17
+
+type relativeUnit struct {
+	months   int
+	days     int
+	duration time.Duration
+}
+
+func (self relativeUnit) apply(tm time.Time, n int) time.Time {
+	if self.months != 0 {
+		tm = addMonthsClamped(tm, self.months*n)
+	}
+
+	if self.days != 0 {
+		tm = tm.AddDate(0, 0, self.days*n)
+	}
+
+	return tm.Add(self.duration * time.Duration(n))
+}
+
+// adds the given number of months to a time, clamping the day to the last day of the resulting
+// month (so January 31st plus one month is February 28th, not March 3rd).
+func addMonthsClamped(tm time.Time, months int) time.Time {
+	var first = time.Date(tm.Year(), tm.Month()+time.Month(months), 1, 0, 0, 0, 0, tm.Location())
+	var day = tm.Day()
+
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return time.Date(first.Year(), first.Month(), day, tm.Hour(), tm.Minute(), tm.Second(), tm.Nanosecond(), tm.Location())
+}
+
+var relativeUnits = map[string]relativeUnit{
+	`ns`:           {duration: time.Nanosecond},
+	`nanosecond`:   {duration: time.Nanosecond},
+	`nanoseconds`:  {duration: time.Nanosecond},
+	`us`:           {duration: time.Microsecond},
+	`microsecond`:  {duration: time.Microsecond},
+	`microseconds`: {duration: time.Microsecond},
+	`ms`:           {duration: time.Millisecond},
+	`millisecond`:  {duration: time.Millisecond},
+	`milliseconds`: {duration: time.Millisecond},
+	`s`:            {duration: time.Second},
+	`sec`:          {duration: time.Second},
+	`secs`:         {duration: time.Second},
+	`second`:       {duration: time.Second},
+	`seconds`:      {duration: time.Second},
+	`m`:            {duration: time.Minute},
+	`min`:          {duration: time.Minute},
+	`mins`:         {duration: time.Minute},
+	`minute`:       {duration: time.Minute},
+	`minutes`:      {duration: time.Minute},
+	`h`:            {duration: time.Hour},
+	`hr`:           {duration: time.Hour},
+	`hrs`:          {duration: time.Hour},
+	`hour`:         {duration: time.Hour},
+	`hours`:        {duration: time.Hour},
+	`d`:            {days: 1},
+	`day`:          {days: 1},
+	`days`:         {days: 1},
+	`w`:            {days: 7},
+	`wk`:           {days: 7},
+	`wks`:          {days: 7},
+	`week`:         {days: 7},
+	`weeks`:        {days: 7},
+	`fortnight`:    {days: 14},
+	`fortnights`:   {days: 14},
+	`mo`:           {months: 1},
+	`mos`:          {months: 1},
+	`month`:        {months: 1},
+	`months`:       {months: 1},
+	`quarter`:      {months: 3},
+	`quarters`:     {months: 3},
+	`y`:            {months: 12},
+	`yr`:           {months: 12},
+	`yrs`:          {months: 12},
+	`year`:         {months: 12},
+	`years`:        {months: 12},
+	`decade`:       {months: 120},
+	`decades`:      {months: 120},
+}
+
+var relativeWeekdays = map[string]time.Weekday{
+	`sunday`:    time.Sunday,
+	`sun`:       time.Sunday,
+	`monday`:    time.Monday,
+	`mon`:       time.Monday,
+	`tuesday`:   time.Tuesday,
+	`tue`:       time.Tuesday,
+	`tues`:      time.Tuesday,
+	`wednesday`: time.Wednesday,
+	`wed`:       time.Wednesday,
+	`thursday`:  time.Thursday,
+	`thu`:       time.Thursday,
+	`thur`:      time.Thursday,
+	`thurs`:     time.Thursday,
+	`friday`:    time.Friday,
+	`fri`:       time.Friday,
+	`saturday`:  time.Saturday,
+	`sat`:       time.Saturday,
+}
+
+// Converts the given integer into a time, inferring whether the value represents
+// seconds, milliseconds, microseconds, or nanoseconds since the Unix epoch from
+// its magnitude.
+func ConvertEpochToTime(epoch int64) time.Time {
+	var magnitude = epoch
+
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+
+	switch {
+	case magnitude < EpochSecondsThreshold:
+		return time.Unix(epoch, 0)
+	case magnitude < EpochMillisecondsThreshold:
+		return time.UnixMilli(epoch)
+	case magnitude < EpochMicrosecondsThreshold:
+		return time.UnixMicro(epoch)
+	default:
+		return time.Unix(0, epoch)
+	}
+}
+
+// Parses an ISO 8601 week date (e.g.: "2024-W05", "2024-W05-3", "2024W053") into
+// a time at midnight in the given location.  Days are numbered 1 (Monday)
+// through 7 (Sunday); if omitted, the Monday of the given week is returned.
+func ParseISOWeekDate(in string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var parts = rxIsoWeekDate.FindStringSubmatch(strings.TrimSpace(in))
+
+	if parts == nil {
+		return time.Time{}, fmt.Errorf("Cannot parse '%s' as an ISO week date", in)
+	}
+
+	var year, _ = strconv.Atoi(parts[1])
+	var week, _ = strconv.Atoi(parts[2])
+	var day = 1
+
+	if parts[3] != `` {
+		day, _ = strconv.Atoi(parts[3])
+	}
+
+	if week < 1 || week > isoWeeksInYear(year) {
+		return time.Time{}, fmt.Errorf("Week %d is out of range for year %d", week, year)
+	}
+
+	// January 4th is always in ISO week 1
+	var jan4 = time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	var week1 = jan4.AddDate(0, 0, -isoWeekdayOffset(jan4.Weekday()))
+
+	return week1.AddDate(0, 0, (week-1)*7+(day-1)), nil
+}
+
+// Parses the given natural language or relative time expression, resolving it
+// against the given reference time.  The location of the reference time is
+// used for all calendar calculations.  Supported expressions include:
+//
+//	now, today, yesterday, tomorrow, noon, midnight
+//	3 days ago, an hour ago, 2h30m ago, -3d
+//	in 2h, in 1 week and 2 days, +90m, 4 hours from now
+//	monday, next monday, last friday, this sunday
+//	next week, last month, this year
+//	start of day, beginning of next week, end of month, end of last year
+//	yesterday 14:00, tomorrow at 9am, next monday 3:30pm, 14:00
+func ParseRelativeTime(in string, reference time.Time) (time.Time, error) {
+	var tokens = tokenizeRelativeTime(in)
+
+	if len(tokens) == 0 {
+		return time.Time{}, fmt.Errorf("Cannot convert '%s' into a date/time value", in)
+	}
+
+	var clock []int
+
+	// look for a time of day at the end (or start) of the expression
+	if c, n := parseClockTokens(tokens, true); c != nil {
+		clock = c
+		tokens = tokens[:len(tokens)-n]
+
+		if len(tokens) > 0 && tokens[len(tokens)-1] == `at` {
+			tokens = tokens[:len(tokens)-1]
+		}
+	} else if c, n := parseClockTokens(tokens, false); c != nil {
+		clock = c
+		tokens = tokens[n:]
+	}
+
+	var base time.Time
+	var isDate bool
+	var err error
+
+	if len(tokens) == 0 {
+		if clock == nil {
+			return time.Time{}, fmt.Errorf("Cannot convert '%s' into a date/time value", in)
+		}
+
+		base = reference
+	} else if base, isDate, err = parseRelativeBase(tokens, reference); err != nil {
+		return time.Time{}, fmt.Errorf("Cannot convert '%s' into a date/time value: %v", in, err)
+	}
+
+	if clock != nil {
+		return time.Date(base.Year(), base.Month(), base.Day(), clock[0], clock[1], clock[2], 0, base.Location()), nil
+	} else if isDate {
+		return truncateToUnit(base, `day`), nil
+	} else {
+		return base, nil
+	}
+}
+
+func tokenizeRelativeTime(in string) []string {
+	var tokens []string
+
+	in = strings.ToLower(strings.TrimSpace(in))
+	in = strings.NewReplacer(`,`, ` `, `;`, ` `).Replace(in)
+
+	for _, field := range strings.Fields(in) {
+		// expand compact offsets like "2h30m" or "-3d" into discrete number/unit tokens
+		if rxRelativeCompact.MatchString(field) {
+			var expanded []string
+			var valid = true
+
+			if field[0] == '+' || field[0] == '-' {
+				expanded = append(expanded, field[0:1])
+			}
+
+			for _, part := range rxRelativeCompactPart.FindAllStringSubmatch(field, -1) {
+				if _, ok := relativeUnits[part[2]]; ok {
+					expanded = append(expanded, part[1], part[2])
+				} else {
+					valid = false
+					break
+				}
+			}
+
+			if valid {
+				tokens = append(tokens, expanded...)
+				continue
+			}
+		}
+
+		if len(field) > 1 && (field[0] == '+' || field[0] == '-') {
+			tokens = append(tokens, field[0:1], field[1:])
+		} else {
+			tokens = append(tokens, field)
+		}
+	}
+
+	return tokens
+}
+
+// attempts to parse a time of day from the end (or start) of the token list, returning
+// the [hour, minute, second] and the number of tokens consumed.
+func parseClockTokens(tokens []string, fromEnd bool) ([]int, int) {
+	for _, n := range []int{2, 1} {
+		if len(tokens) < n {
+			continue
+		}
+
+		var candidate []string
+
+		if fromEnd {
+			candidate = tokens[len(tokens)-n:]
+		} else {
+			candidate = tokens[:n]
+		}
+
+		if clock := parseClock(strings.Join(candidate, ``)); clock != nil {
+			// a bare number is only a time of day if it has a meridiem or a colon; otherwise
+			// it is much more likely to be a quantity (e.g. "3 days")
+			if n == 1 || (candidate[1] == `am` || candidate[1] == `pm`) {
+				return clock, n
+			}
+		}
+	}
+
+	return nil, 0
+}
+
+func parseClock(in string) []int {
+	switch in {
+	case `noon`, `midday`:
+		return []int{12, 0, 0}
+	case `midnight`:
+		return []int{0, 0, 0}
+	}
+
+	var parts = rxClockTime.FindStringSubmatch(in)
+
+	if parts == nil || (parts[2] == `` && parts[4] == ``) {
+		return nil
+	}
+
+	var hour, _ = strconv.Atoi(parts[1])
+	var minute, _ = strconv.Atoi(parts[2])
+	var second, _ = strconv.Atoi(parts[3])
+
+	switch strings.Trim(parts[4], `.`) {
+	case `am`, `a.m`:
+		if hour < 1 || hour > 12 {
+			return nil
+		} else if hour == 12 {
+			hour = 0
+		}
+	case `pm`, `p.m`:
+		if hour < 1 || hour > 12 {
+			return nil
+		} else if hour < 12 {
+			hour += 12
+		}
+	}
+
+	if hour > 23 || minute > 59 || second > 59 {
+		return nil
+	}
+
+	return []int{hour, minute, second}
+}
+
+// parses the date portion of a relative expression. The boolean return value indicates
+// whether the result represents a whole day (and should be truncated to midnight).
+func parseRelativeBase(tokens []string, reference time.Time) (time.Time, bool, error) {
+	var first = tokens[0]
+	var last = tokens[len(tokens)-1]
+
+	if len(tokens) == 1 {
+		switch first {
+		case `now`:
+			return reference, false, nil
+		case `today`:
+			return reference, true, nil
+		case `yesterday`:
+			return reference.AddDate(0, 0, -1), true, nil
+		case `tomorrow`:
+			return reference.AddDate(0, 0, 1), true, nil
+		}
+
+		if wd, ok := relativeWeekdays[first]; ok {
+			return reference.AddDate(0, 0, daysUntilWeekday(reference.Weekday(), wd, true)), true, nil
+		}
+	}
+
+	switch {
+	case last == `ago`:
+		return applyRelativeOffsets(tokens[:len(tokens)-1], reference, -1)
+
+	case len(tokens) > 2 && last == `now` && tokens[len(tokens)-2] == `from`:
+		return applyRelativeOffsets(tokens[:len(tokens)-2], reference, 1)
+
+	case first == `in` || first == `+`:
+		return applyRelativeOffsets(tokens[1:], reference, 1)
+
+	case first == `-`:
+		return applyRelativeOffsets(tokens[1:], reference, -1)
+
+	case len(tokens) == 2 && (first == `next` || first == `last` || first == `this`):
+		if wd, ok := relativeWeekdays[last]; ok {
+			switch first {
+			case `next`:
+				return reference.AddDate(0, 0, daysUntilWeekday(reference.Weekday(), wd, false)), true, nil
+			case `last`:
+				return reference.AddDate(0, 0, -daysSinceWeekday(reference.Weekday(), wd)), true, nil
+			default:
+				var monday = reference.AddDate(0, 0, -isoWeekdayOffset(reference.Weekday()))
+				return monday.AddDate(0, 0, isoWeekdayOffset(wd)), true, nil
+			}
+		} else if unit, ok := relativeUnits[last]; ok {
+			switch first {
+			case `next`:
+				return unit.apply(reference, 1), false, nil
+			case `last`:
+				return unit.apply(reference, -1), false, nil
+			default:
+				return reference, false, nil
+			}
+		}
+
+	case len(tokens) >= 3 && (first == `start` || first == `beginning` || first == `end`) && tokens[1] == `of`:
+		var rest = tokens[2:]
+		var tm = reference
+
+		if len(rest) > 0 && rest[0] == `the` {
+			rest = rest[1:]
+		}
+
+		if len(rest) == 2 {
+			if unit, ok := relativeUnits[rest[1]]; ok {
+				switch rest[0] {
+				case `next`:
+					tm = unit.apply(tm, 1)
+				case `last`, `previous`:
+					tm = unit.apply(tm, -1)
+				case `this`, `the`:
+					break
+				default:
+					return time.Time{}, false, fmt.Errorf("unrecognized qualifier %q", rest[0])
+				}
+
+				rest = rest[1:]
+			}
+		}
+
+		if len(rest) == 1 {
+			if _, ok := relativeUnits[rest[0]]; ok {
+				var start = truncateToUnit(tm, rest[0])
+
+				if first == `end` {
+					return relativeUnits[rest[0]].apply(start, 1).Add(-time.Nanosecond), false, nil
+				} else {
+					return start, false, nil
+				}
+			}
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("unrecognized expression %q", strings.Join(tokens, ` `))
+}
+
+// applies a sequence of quantity/unit pairs (e.g.: "3 days and 4 hours") to the given time.
+func applyRelativeOffsets(tokens []string, reference time.Time, direction int) (time.Time, bool, error) {
+	var tm = reference
+	var applied int
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case `and`:
+			continue
+		}
+
+		if i+1 >= len(tokens) {
+			return time.Time{}, false, fmt.Errorf("expected a unit after %q", tokens[i])
+		}
+
+		var n int
+
+		switch tokens[i] {
+		case `a`, `an`, `one`:
+			n = 1
+		default:
+			if v, err := strconv.Atoi(tokens[i]); err == nil {
+				n = v
+			} else {
+				return time.Time{}, false, fmt.Errorf("invalid quantity %q", tokens[i])
+			}
+		}
+
+		if unit, ok := relativeUnits[tokens[i+1]]; ok {
+			tm = unit.apply(tm, n*direction)
+			applied++
+			i++
+		} else {
+			return time.Time{}, false, fmt.Errorf("unrecognized unit %q", tokens[i+1])
+		}
+	}
+
+	if applied == 0 {
+		return time.Time{}, false, fmt.Errorf("no time offset specified")
+	}
+
+	return tm, false, nil
+}
+
+// returns the given time truncated to the start of the named unit
+func truncateToUnit(tm time.Time, unit string) time.Time {
+	var loc = tm.Location()
+
+	switch u := relativeUnits[unit]; {
+	case u.months >= 120:
+		var year = tm.Year() - ((tm.Year()%10)+10)%10
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	case u.months >= 12:
+		return time.Date(tm.Year(), time.January, 1, 0, 0, 0, 0, loc)
+	case u.months >= 3:
+		return time.Date(tm.Year(), tm.Month()-time.Month((int(tm.Month())-1)%3), 1, 0, 0, 0, 0, loc)
+	case u.months > 0:
+		return time.Date(tm.Year(), tm.Month(), 1, 0, 0, 0, 0, loc)
+	case u.days >= 7:
+		var day = time.Date(tm.Year(), tm.Month(), tm.Day(), 0, 0, 0, 0, loc)
+		return day.AddDate(0, 0, -isoWeekdayOffset(tm.Weekday()))
+	case u.days > 0:
+		return time.Date(tm.Year(), tm.Month(), tm.Day(), 0, 0, 0, 0, loc)
+	case u.duration >= time.Hour:
+		return time.Date(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), 0, 0, 0, loc)
+	case u.duration >= time.Minute:
+		return time.Date(tm.Year(), tm.Month(), tm.Day(), tm.Hour(), tm.Minute(), 0, 0, loc)
+	case u.duration > 0:
+		return tm.Truncate(u.duration)
+	default:
+		return tm
+	}
+}
+
+// returns the number of days since Monday (Monday=0, Sunday=6)
+func isoWeekdayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func daysUntilWeekday(from time.Weekday, to time.Weekday, includeToday bool) int {
+	var days = (int(to) - int(from) + 7) % 7
+
+	if days == 0 && !includeToday {
+		days = 7
+	}
+
+	return days
+}
+
+func daysSinceWeekday(from time.Weekday, to time.Weekday) int {
+	var days = (int(from) - int(to) + 7) % 7
+
+	if days == 0 {
+		days = 7
+	}
+
+	return days
+}
+
+func isoWeeksInYear(year int) int {
+	// years whose last week contains December 28th have 53 weeks
+	if _, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek(); week == 53 {
+		return 53
+	}
+
+	return 52
+}
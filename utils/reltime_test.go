@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ghetzel/testify/require"
+)
+
+func TestParseRelativeTime(t *testing.T) {
+	assert := require.New(t)
+
+	loc := time.FixedZone(`EST`, -5*60*60)
+
+	// Wednesday, 2021-03-17 10:30:00 -0500
+	ref := time.Date(2021, 3, 17, 10, 30, 0, 0, loc)
+
+	for in, out := range map[string]time.Time{
+		`now`:                     ref,
+		`today`:                   time.Date(2021, 3, 17, 0, 0, 0, 0, loc),
+		`Yesterday`:               time.Date(2021, 3, 16, 0, 0, 0, 0, loc),
+		`tomorrow`:                time.Date(2021, 3, 18, 0, 0, 0, 0, loc),
+		`yesterday 14:00`:         time.Date(2021, 3, 16, 14, 0, 0, 0, loc),
+		`tomorrow at 9am`:         time.Date(2021, 3, 18, 9, 0, 0, 0, loc),
+		`tomorrow at 12am`:        time.Date(2021, 3, 18, 0, 0, 0, 0, loc),
+		`today noon`:              time.Date(2021, 3, 17, 12, 0, 0, 0, loc),
+		`14:15:16`:                time.Date(2021, 3, 17, 14, 15, 16, 0, loc),
+		`3 pm`:                    time.Date(2021, 3, 17, 15, 0, 0, 0, loc),
+		`3 days ago`:              time.Date(2021, 3, 14, 10, 30, 0, 0, loc),
+		`an hour ago`:             time.Date(2021, 3, 17, 9, 30, 0, 0, loc),
+		`2h30m ago`:               time.Date(2021, 3, 17, 8, 0, 0, 0, loc),
+		`-3d`:                     time.Date(2021, 3, 14, 10, 30, 0, 0, loc),
+		`-3 days`:                 time.Date(2021, 3, 14, 10, 30, 0, 0, loc),
+		`in 2h`:                   time.Date(2021, 3, 17, 12, 30, 0, 0, loc),
+		`+90m`:                    time.Date(2021, 3, 17, 12, 0, 0, 0, loc),
+		`in 1 week and 2 days`:    time.Date(2021, 3, 26, 10, 30, 0, 0, loc),
+		`4 hours from now`:        time.Date(2021, 3, 17, 14, 30, 0, 0, loc),
+		`in 1 month`:              time.Date(2021, 4, 17, 10, 30, 0, 0, loc),
+		`2 years ago`:             time.Date(2019, 3, 17, 10, 30, 0, 0, loc),
+		`wednesday`:               time.Date(2021, 3, 17, 0, 0, 0, 0, loc),
+		`friday`:                  time.Date(2021, 3, 19, 0, 0, 0, 0, loc),
+		`next wednesday`:          time.Date(2021, 3, 24, 0, 0, 0, 0, loc),
+		`next monday`:             time.Date(2021, 3, 22, 0, 0, 0, 0, loc),
+		`next monday 3:30pm`:      time.Date(2021, 3, 22, 15, 30, 0, 0, loc),
+		`last friday`:             time.Date(2021, 3, 12, 0, 0, 0, 0, loc),
+		`last wednesday`:          time.Date(2021, 3, 10, 0, 0, 0, 0, loc),
+		`this sunday`:             time.Date(2021, 3, 21, 0, 0, 0, 0, loc),
+		`this monday`:             time.Date(2021, 3, 15, 0, 0, 0, 0, loc),
+		`next week`:               time.Date(2021, 3, 24, 10, 30, 0, 0, loc),
+		`last month`:              time.Date(2021, 2, 17, 10, 30, 0, 0, loc),
+		`start of day`:            time.Date(2021, 3, 17, 0, 0, 0, 0, loc),
+		`start of week`:           time.Date(2021, 3, 15, 0, 0, 0, 0, loc),
+		`beginning of next week`:  time.Date(2021, 3, 22, 0, 0, 0, 0, loc),
+		`end of month`:            time.Date(2021, 3, 31, 23, 59, 59, 999999999, loc),
+		`end of the month`:        time.Date(2021, 3, 31, 23, 59, 59, 999999999, loc),
+		`end of last month`:       time.Date(2021, 2, 28, 23, 59, 59, 999999999, loc),
+		`start of quarter`:        time.Date(2021, 1, 1, 0, 0, 0, 0, loc),
+		`end of year`:             time.Date(2021, 12, 31, 23, 59, 59, 999999999, loc),
+		`start of the next year`:  time.Date(2022, 1, 1, 0, 0, 0, 0, loc),
+		`end of day`:              time.Date(2021, 3, 17, 23, 59, 59, 999999999, loc),
+		`start of hour`:           time.Date(2021, 3, 17, 10, 0, 0, 0, loc),
+		`end of previous quarter`: time.Date(2020, 12, 31, 23, 59, 59, 999999999, loc),
+	} {
+		v, err := ParseRelativeTime(in, ref)
+		assert.NoError(err, in)
+		assert.True(out.Equal(v), "in=%q want=%v got=%v", in, out, v)
+	}
+
+	for _, fail := range []string{``, `potato`, `1.5`, `false`, `3 potatoes ago`, `in`, `next potato`, `25:00`, `13pm`} {
+		_, err := ParseRelativeTime(fail, ref)
+		assert.Error(err, fail)
+	}
+}
+
+func TestParseRelativeTimeMonthEnds(t *testing.T) {
+	assert := require.New(t)
+
+	loc := time.FixedZone(`EST`, -5*60*60)
+
+	for _, tc := range []struct {
+		ref time.Time
+		in  string
+		out time.Time
+	}{
+		{time.Date(2025, 1, 31, 10, 30, 0, 0, loc), `next month`, time.Date(2025, 2, 28, 10, 30, 0, 0, loc)},
+		{time.Date(2025, 1, 31, 10, 30, 0, 0, loc), `in 1 month`, time.Date(2025, 2, 28, 10, 30, 0, 0, loc)},
+		{time.Date(2025, 1, 31, 10, 30, 0, 0, loc), `end of next month`, time.Date(2025, 2, 28, 23, 59, 59, 999999999, loc)},
+		{time.Date(2025, 1, 31, 10, 30, 0, 0, loc), `start of next month`, time.Date(2025, 2, 1, 0, 0, 0, 0, loc)},
+		{time.Date(2025, 1, 31, 10, 30, 0, 0, loc), `in 3 months`, time.Date(2025, 4, 30, 10, 30, 0, 0, loc)},
+		{time.Date(2024, 1, 31, 10, 30, 0, 0, loc), `next month`, time.Date(2024, 2, 29, 10, 30, 0, 0, loc)},
+		{time.Date(2025, 3, 31, 10, 30, 0, 0, loc), `last month`, time.Date(2025, 2, 28, 10, 30, 0, 0, loc)},
+		{time.Date(2025, 3, 31, 10, 30, 0, 0, loc), `end of last month`, time.Date(2025, 2, 28, 23, 59, 59, 999999999, loc)},
+		{time.Date(2025, 3, 31, 10, 30, 0, 0, loc), `1 month ago`, time.Date(2025, 2, 28, 10, 30, 0, 0, loc)},
+		{time.Date(2025, 5, 31, 10, 30, 0, 0, loc), `next quarter`, time.Date(2025, 8, 31, 10, 30, 0, 0, loc)},
+		{time.Date(2025, 3, 31, 10, 30, 0, 0, loc), `next quarter`, time.Date(2025, 6, 30, 10, 30, 0, 0, loc)},
+		{time.Date(2024, 2, 29, 10, 30, 0, 0, loc), `next year`, time.Date(2025, 2, 28, 10, 30, 0, 0, loc)},
+		{time.Date(2024, 2, 29, 10, 30, 0, 0, loc), `end of next year`, time.Date(2025, 12, 31, 23, 59, 59, 999999999, loc)},
+		{time.Date(2025, 1, 31, 10, 30, 0, 0, loc), `in 1 month and 1 day`, time.Date(2025, 3, 1, 10, 30, 0, 0, loc)},
+	} {
+		v, err := ParseRelativeTime(tc.in, tc.ref)
+		assert.NoError(err, tc.in)
+		assert.True(tc.out.Equal(v), "ref=%v in=%q want=%v got=%v", tc.ref, tc.in, tc.out, v)
+	}
+}
+
+func TestParseRelativeTimeDecades(t *testing.T) {
+	assert := require.New(t)
+
+	loc := time.FixedZone(`EST`, -5*60*60)
+	ref := time.Date(2025, 6, 15, 10, 30, 0, 0, loc)
+
+	for in, out := range map[string]time.Time{
+		`start of decade`:          time.Date(2020, 1, 1, 0, 0, 0, 0, loc),
+		`end of decade`:            time.Date(2029, 12, 31, 23, 59, 59, 999999999, loc),
+		`start of the next decade`: time.Date(2030, 1, 1, 0, 0, 0, 0, loc),
+		`end of last decade`:       time.Date(2019, 12, 31, 23, 59, 59, 999999999, loc),
+		`in 2 decades`:             time.Date(2045, 6, 15, 10, 30, 0, 0, loc),
+	} {
+		v, err := ParseRelativeTime(in, ref)
+		assert.NoError(err, in)
+		assert.True(out.Equal(v), "in=%q want=%v got=%v", in, out, v)
+	}
+
+	v, err := ParseRelativeTime(`start of decade`, time.Date(2030, 1, 1, 0, 0, 0, 0, loc))
+	assert.NoError(err)
+	assert.True(time.Date(2030, 1, 1, 0, 0, 0, 0, loc).Equal(v))
+}
+
+func TestParseISOWeekDate(t *testing.T) {
+	assert := require.New(t)
+
+	for in, out := range map[string]time.Time{
+		`2021-W01`:   time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+		`2021-W01-3`: time.Date(2021, 1, 6, 0, 0, 0, 0, time.UTC),
+		`2020W537`:   time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+		`2026-W01-1`: time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC),
+		`2024-w05`:   time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC),
+	} {
+		v, err := ParseISOWeekDate(in, nil)
+		assert.NoError(err, in)
+		assert.True(out.Equal(v), "in=%q want=%v got=%v", in, out, v)
+	}
+
+	_, err := ParseISOWeekDate(`2021-W53`, nil)
+	assert.Error(err)
+
+	_, err = ParseISOWeekDate(`2021-W00`, nil)
+	assert.Error(err)
+}
+
+func TestConvertEpochToTime(t *testing.T) {
+	assert := require.New(t)
+
+	expected := time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)
+
+	assert.True(expected.Equal(ConvertEpochToTime(1136239445)))
+	assert.True(expected.Equal(ConvertEpochToTime(1136239445000)))
+	assert.True(expected.Equal(ConvertEpochToTime(1136239445000000)))
+	assert.True(expected.Equal(ConvertEpochToTime(1136239445000000000)))
+	assert.True(time.Unix(0, 0).Equal(ConvertEpochToTime(0)))
+}
+
+func TestConvertToTimeIn(t *testing.T) {
+	assert := require.New(t)
+
+	loc := time.FixedZone(`PST`, -8*60*60)
+	ref := time.Date(2021, 3, 17, 10, 30, 0, 0, time.UTC)
+
+	v, err := ConvertToTimeIn(`2015-05-01 00:15:16`, ref, loc)
+	assert.NoError(err)
+	assert.True(time.Date(2015, 5, 1, 0, 15, 16, 0, loc).Equal(v))
+
+	v, err = ConvertToTimeIn(`2015-05-01T00:15:16Z`, ref, loc)
+	assert.NoError(err)
+	assert.True(time.Date(2015, 5, 1, 0, 15, 16, 0, time.UTC).Equal(v))
+
+	// relative expressions are resolved in the given location
+	v, err = ConvertToTimeIn(`today`, ref, loc)
+	assert.NoError(err)
+	assert.True(time.Date(2021, 3, 17, 0, 0, 0, 0, loc).Equal(v))
+
+	v, err = ConvertToTimeIn(`yesterday 14:00`, ref, nil)
+	assert.NoError(err)
+	assert.True(time.Date(2021, 3, 16, 14, 0, 0, 0, time.UTC).Equal(v))
+
+	v, err = ConvertToTimeIn(`1136239445123`, ref, nil)
+	assert.NoError(err)
+	assert.True(time.Date(2006, 1, 2, 22, 4, 5, 123000000, time.UTC).Equal(v))
+
+	v, err = ConvertToTimeIn(`2021-W11-3`, ref, nil)
+	assert.NoError(err)
+	assert.True(time.Date(2021, 3, 17, 0, 0, 0, 0, time.UTC).Equal(v))
+
+	_, err = ConvertToTimeIn(`potato`, ref, nil)
+	assert.Error(err)
+
+	// natural language is also supported by ConvertToTime, relative to the current time
+	v, err = ConvertToTime(`in 1 hour`)
+	assert.NoError(err)
+	assert.True(v.After(time.Now()))
+}